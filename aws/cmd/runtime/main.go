@@ -1,11 +1,24 @@
 package main
 
 import (
+	"context"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 
 	mongo_service "github.com/nitrictech/mongodb-provider/common"
+	mongo_env "github.com/nitrictech/mongodb-provider/common/env"
+	cappedpb "github.com/nitrictech/mongodb-provider/common/proto/capped/v1"
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	geopb "github.com/nitrictech/mongodb-provider/common/proto/geo/v1"
+	kvconditionalpb "github.com/nitrictech/mongodb-provider/common/proto/kvconditional/v1"
+	kvupdatepb "github.com/nitrictech/mongodb-provider/common/proto/kvupdate/v1"
+	outboxpb "github.com/nitrictech/mongodb-provider/common/proto/outbox/v1"
+	searchpb "github.com/nitrictech/mongodb-provider/common/proto/search/v1"
+	tenantspb "github.com/nitrictech/mongodb-provider/common/proto/tenants/v1"
+	timeseriespb "github.com/nitrictech/mongodb-provider/common/proto/timeseries/v1"
+	vectorspb "github.com/nitrictech/mongodb-provider/common/proto/vectors/v1"
 	"github.com/nitrictech/nitric/cloud/aws/runtime/api"
 	"github.com/nitrictech/nitric/cloud/aws/runtime/env"
 	lambda_service "github.com/nitrictech/nitric/cloud/aws/runtime/gateway"
@@ -18,6 +31,7 @@ import (
 	base_http "github.com/nitrictech/nitric/cloud/common/runtime/gateway"
 	"github.com/nitrictech/nitric/core/pkg/logger"
 	"github.com/nitrictech/nitric/core/pkg/membrane"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -27,6 +41,16 @@ func main() {
 
 	logger.SetLogLevel(logger.INFO)
 
+	// Registered before the servers, so their cache and compression counters are recorded
+	metricsReporter, err := mongo_service.NewMetricsReporter()
+	if err != nil {
+		logger.Fatalf("There was an error initializing the mongo metrics reporter: %v", err)
+	}
+
+	if metricsReporter != nil {
+		metricsReporter.Start()
+	}
+
 	gatewayEnv := env.GATEWAY_ENVIRONMENT.String()
 
 	membraneOpts := membrane.DefaultMembraneOptions()
@@ -37,36 +61,117 @@ func main() {
 		return
 	}
 
+	// Loaded first, the cluster's certificates may be secrets
+	membraneOpts.SecretManagerPlugin, _ = secrets_manager_secret_service.New(provider)
+
+	mongoServer, err := mongo_service.New(mongo_service.WithSecrets(membraneOpts.SecretManagerPlugin))
+	if err != nil {
+		logger.Fatalf("There was an error initializing the mongo server: %v", err)
+	}
+
+	httpGatewayOpts := &base_http.HttpGatewayOptions{}
+
+	if mongoStorage, _ := mongo_env.MONGO_STORAGE_ENABLED.Bool(); mongoStorage {
+		storageServer, err := mongo_service.NewStorage(mongoServer.Database())
+		if err != nil {
+			logger.Fatalf("There was an error initializing the mongo storage server: %v", err)
+		}
+
+		membraneOpts.StoragePlugin = storageServer
+		// Presigned urls are redeemed through the http gateway
+		httpGatewayOpts.RouteRegistrationHook = storageServer.RegisterRoutes
+	} else {
+		membraneOpts.StoragePlugin, _ = s3_service.New(provider)
+	}
+
 	// Load the appropriate gateway based on the environment.
 	switch gatewayEnv {
 	case "lambda":
 		membraneOpts.GatewayPlugin, _ = lambda_service.New(provider)
 	default:
-		membraneOpts.GatewayPlugin, _ = base_http.NewHttpGateway(nil)
+		membraneOpts.GatewayPlugin, _ = base_http.NewHttpGateway(httpGatewayOpts)
 	}
 
 	membraneOpts.ApiPlugin = api.NewAwsApiGatewayProvider(provider)
-	membraneOpts.SecretManagerPlugin, _ = secrets_manager_secret_service.New(provider)
-	membraneOpts.KeyValuePlugin, err = mongo_service.New()
-	if err != nil {
-		logger.Fatalf("There was an error initializing the mongo server: %v", err)
-	}
+	membraneOpts.KeyValuePlugin = mongoServer
 
 	membraneOpts.TopicsPlugin, _ = sns_service.New(provider)
-	membraneOpts.StoragePlugin, _ = s3_service.New(provider)
 	membraneOpts.ResourcesPlugin = provider
 	membraneOpts.WebsocketPlugin, _ = websocket.NewAwsApiGatewayWebsocket(provider)
 	membraneOpts.QueuesPlugin, _ = sqs_service.New(provider)
 
+	if mongoQueues, _ := mongo_env.MONGO_QUEUES_ENABLED.Bool(); mongoQueues {
+		membraneOpts.QueuesPlugin, err = mongo_service.NewQueues(mongoServer.Database())
+		if err != nil {
+			logger.Fatalf("There was an error initializing the mongo queues server: %v", err)
+		}
+	}
+
 	m, err := membrane.New(membraneOpts)
 	if err != nil {
 		logger.Fatalf("There was an error initializing the membrane server: %v", err)
 	}
 
+	// Serve the extension services alongside the membrane
+	extensionServer, err := mongo_service.NewExtensionServer(mongoServer.Database(), membraneOpts.StoragePlugin)
+	if err != nil {
+		logger.Fatalf("There was an error initializing the mongo extension services: %v", err)
+	}
+
+	// Tenant administration isn't scoped to a tenant, so it is served on its own address rather than alongside the services apps call
+	var adminServer *grpc.Server
+	if mongo_env.MONGO_TENANCY_MODE.String() != "" {
+		adminListener, err := net.Listen("tcp", mongo_env.MONGO_ADMIN_ADDRESS.String())
+		if err != nil {
+			logger.Fatalf("There was an error listening on the mongo admin address: %v", err)
+		}
+
+		adminServer = grpc.NewServer()
+		tenantspb.RegisterTenantsServer(adminServer, mongo_service.NewTenants(mongoServer))
+
+		go func() {
+			if err := adminServer.Serve(adminListener); err != nil {
+				logger.Errorf("Mongo admin server error: %v", err)
+			}
+		}()
+	}
+
+	// Partial and conditional writes and document, vector, full-text, geo, time-series and capped queries are served for the key value stores of this runtime
+	kvupdatepb.RegisterKvUpdateServer(extensionServer, mongo_service.NewKvUpdate(mongoServer))
+	kvconditionalpb.RegisterKvConditionalServer(extensionServer, mongo_service.NewKvConditional(mongoServer))
+	documentspb.RegisterDocumentsServer(extensionServer, mongo_service.NewDocuments(mongoServer))
+	vectorspb.RegisterVectorsServer(extensionServer, mongo_service.NewVectors(mongoServer))
+	searchpb.RegisterSearchServer(extensionServer, mongo_service.NewSearch(mongoServer))
+	geopb.RegisterGeoServer(extensionServer, mongo_service.NewGeo(mongoServer))
+	timeseriespb.RegisterTimeSeriesServer(extensionServer, mongo_service.NewTimeSeries(mongoServer))
+	cappedpb.RegisterCappedServer(extensionServer, mongo_service.NewCapped(mongoServer))
+
+	// Commit key value writes with their events and relay the events to the topics plugin
+	var outboxRelay *mongo_service.OutboxRelay
+	if mongoOutbox, _ := mongo_env.MONGO_OUTBOX_ENABLED.Bool(); mongoOutbox {
+		outboxpb.RegisterOutboxServer(extensionServer, mongo_service.NewOutbox(mongoServer))
+
+		outboxRelay = mongo_service.NewOutboxRelay(mongoServer.Database(), membraneOpts.TopicsPlugin)
+		if err := outboxRelay.Start(); err != nil {
+			logger.Fatalf("There was an error starting the mongo outbox relay: %v", err)
+		}
+	}
+
+	// Export key value stores to the snapshot bucket on schedule
+	var snapshotScheduler *mongo_service.SnapshotScheduler
+	if mongoSnapshots, _ := mongo_env.MONGO_SNAPSHOTS_ENABLED.Bool(); mongoSnapshots {
+		snapshotScheduler, err = mongo_service.NewSnapshotScheduler(mongoServer.Database(), membraneOpts.StoragePlugin)
+		if err != nil {
+			logger.Fatalf("There was an error initializing the mongo snapshot scheduler: %v", err)
+		}
+
+		snapshotScheduler.Start()
+	}
+
 	errChan := make(chan error)
 	// Start the Membrane server
 	go func(chan error) {
-		errChan <- m.Start()
+		errChan <- m.Start(membrane.WithGrpcServer(extensionServer))
 	}(errChan)
 
 	select {
@@ -77,4 +182,22 @@ func main() {
 	}
 
 	m.Stop()
+
+	if adminServer != nil {
+		adminServer.GracefulStop()
+	}
+
+	if outboxRelay != nil {
+		outboxRelay.Stop()
+	}
+
+	if snapshotScheduler != nil {
+		snapshotScheduler.Stop()
+	}
+
+	if metricsReporter != nil {
+		metricsReporter.Stop()
+	}
+
+	mongoServer.Disconnect(context.Background())
 }
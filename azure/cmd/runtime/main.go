@@ -15,11 +15,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
+	mongo_service "github.com/nitrictech/mongodb-provider/common"
+	mongo_env "github.com/nitrictech/mongodb-provider/common/env"
 	"github.com/nitrictech/nitric/cloud/azure/runtime/api"
 	"github.com/nitrictech/nitric/cloud/azure/runtime/resource"
 	"github.com/nitrictech/nitric/core/pkg/logger"
@@ -31,6 +34,7 @@ import (
 	azblob_service "github.com/nitrictech/nitric/cloud/azure/runtime/storage"
 	event_grid "github.com/nitrictech/nitric/cloud/azure/runtime/topic"
 	"github.com/nitrictech/nitric/core/pkg/membrane"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func main() {
@@ -46,6 +50,37 @@ func main() {
 
 	membraneOpts := membrane.DefaultMembraneOptions()
 
+	mongoQueues, _ := mongo_env.MONGO_QUEUES_ENABLED.Bool()
+	mongoStorage, _ := mongo_env.MONGO_STORAGE_ENABLED.Bool()
+	mongoSnapshots, _ := mongo_env.MONGO_SNAPSHOTS_ENABLED.Bool()
+
+	// Key value stores are kept in table storage, which the outbox's transaction can't include
+	if mongoOutbox, _ := mongo_env.MONGO_OUTBOX_ENABLED.Bool(); mongoOutbox {
+		logger.Fatalf("MONGO_OUTBOX_ENABLED is only supported on AWS, where key value stores are kept in MongoDB")
+	}
+
+	// Loaded first, the cluster's certificates may be secrets
+	membraneOpts.SecretManagerPlugin, err = key_vault.New()
+	if err != nil {
+		logger.Errorf("Failed to load secret plugin: %s", err.Error())
+	}
+
+	// The cluster connection is only injected when the stack uses it
+	var mongoDatabase *mongo.Database
+	if mongo_env.MONGO_CLUSTER_CONNECTION_STRING.String() != "" {
+		mongoDatabase, err = mongo_service.Connect(context.TODO(), mongo_service.WithSecrets(membraneOpts.SecretManagerPlugin))
+		if err != nil {
+			logger.Fatalf("There was an error connecting to the mongo cluster: %v", err)
+		}
+	} else if mongoQueues {
+		// Plugins served from the cluster can't be created without it
+		logger.Fatalf("MONGO_QUEUES_ENABLED requires MONGO_CLUSTER_CONNECTION_STRING")
+	} else if mongoStorage {
+		logger.Fatalf("MONGO_STORAGE_ENABLED requires MONGO_CLUSTER_CONNECTION_STRING")
+	} else if mongoSnapshots {
+		logger.Fatalf("MONGO_SNAPSHOTS_ENABLED requires MONGO_CLUSTER_CONNECTION_STRING")
+	}
+
 	membraneOpts.ApiPlugin = api.NewAzureApiGatewayProvider(provider)
 
 	membraneOpts.KeyValuePlugin, err = aztables_service.New()
@@ -63,19 +98,29 @@ func main() {
 		logger.Errorf("Failed to load gateway plugin: %s", err.Error())
 	}
 
-	membraneOpts.StoragePlugin, err = azblob_service.New()
-	if err != nil {
-		logger.Errorf("Failed to load storage plugin: %s", err.Error())
+	if mongoStorage {
+		// Presigned urls are unavailable as this gateway doesn't serve the presign route
+		membraneOpts.StoragePlugin, err = mongo_service.NewStorage(mongoDatabase)
+		if err != nil {
+			logger.Errorf("Failed to load storage plugin: %s", err.Error())
+		}
+	} else {
+		membraneOpts.StoragePlugin, err = azblob_service.New()
+		if err != nil {
+			logger.Errorf("Failed to load storage plugin: %s", err.Error())
+		}
 	}
 
-	membraneOpts.QueuesPlugin, err = azqueue_service.New()
-	if err != nil {
-		logger.Errorf("Failed to load queue plugin: %s", err.Error())
-	}
-
-	membraneOpts.SecretManagerPlugin, err = key_vault.New()
-	if err != nil {
-		logger.Errorf("Failed to load secret plugin: %s", err.Error())
+	if mongoQueues {
+		membraneOpts.QueuesPlugin, err = mongo_service.NewQueues(mongoDatabase)
+		if err != nil {
+			logger.Errorf("Failed to load queue plugin: %s", err.Error())
+		}
+	} else {
+		membraneOpts.QueuesPlugin, err = azqueue_service.New()
+		if err != nil {
+			logger.Errorf("Failed to load queue plugin: %s", err.Error())
+		}
 	}
 
 	membraneOpts.ResourcesPlugin = provider
@@ -85,10 +130,32 @@ func main() {
 		logger.Fatalf("There was an error initializing the membrane server: %v", err)
 	}
 
+	startOpts := []membrane.MembraneStartOptions{}
+	if mongoDatabase != nil {
+		// Serve the extension services alongside the membrane
+		extensionServer, err := mongo_service.NewExtensionServer(mongoDatabase, membraneOpts.StoragePlugin)
+		if err != nil {
+			logger.Fatalf("There was an error initialising the mongo extension services: %v", err)
+		}
+
+		startOpts = append(startOpts, membrane.WithGrpcServer(extensionServer))
+	}
+
+	// Export key value stores to the snapshot bucket on schedule
+	var snapshotScheduler *mongo_service.SnapshotScheduler
+	if mongoSnapshots {
+		snapshotScheduler, err = mongo_service.NewSnapshotScheduler(mongoDatabase, membraneOpts.StoragePlugin)
+		if err != nil {
+			logger.Fatalf("There was an error initializing the mongo snapshot scheduler: %v", err)
+		}
+
+		snapshotScheduler.Start()
+	}
+
 	errChan := make(chan error)
 	// Start the Membrane server
 	go func(chan error) {
-		errChan <- m.Start()
+		errChan <- m.Start(startOpts...)
 	}(errChan)
 
 	select {
@@ -99,4 +166,14 @@ func main() {
 	}
 
 	m.Stop()
+
+	if snapshotScheduler != nil {
+		snapshotScheduler.Stop()
+	}
+
+	if mongoDatabase != nil {
+		if err := mongoDatabase.Client().Disconnect(context.Background()); err != nil {
+			logger.Errorf("unable to disconnect from the mongo cluster: %v", err)
+		}
+	}
 }
@@ -0,0 +1,253 @@
+//go:build atlas
+
+// Tests of the features that need Atlas Search, run against the Atlas local container with `make test-atlas`
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	searchpb "github.com/nitrictech/mongodb-provider/common/proto/search/v1"
+	vectorspb "github.com/nitrictech/mongodb-provider/common/proto/vectors/v1"
+	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// How long search indexes are waited for, Atlas builds them in the background
+const atlasIndexTimeout = 2 * time.Minute
+
+// A server of the given stores in a database of its own, which is dropped when the test ends
+func atlasServer(t *testing.T, stores map[string]StoreSettings) *MongoDBServer {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_ATLAS_LOCAL_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017/?directConnection=true"
+	}
+
+	opts := []Option{WithURI(uri), WithDatabase(fmt.Sprintf("atlas-test-%d", time.Now().UnixNano()))}
+	for name, settings := range stores {
+		opts = append(opts, WithStore(name, settings))
+	}
+
+	server, err := NewWithOptions(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("unable to connect to %s: %v", uri, err)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Database().Drop(context.Background())
+		server.Disconnect(context.Background())
+	})
+
+	return server
+}
+
+// Wait for the runtime to create the declared search indexes of a store and for Atlas to build them
+func waitForSearchIndexes(t *testing.T, server *MongoDBServer, store string, settings StoreSettings) {
+	t.Helper()
+
+	ctx := context.Background()
+	deadline := time.Now().Add(atlasIndexTimeout)
+
+	for {
+		queryable := map[string]bool{}
+
+		cursor, err := server.getCollectionHandle(store).SearchIndexes().List(ctx, nil)
+		if err == nil {
+			var indexes []struct {
+				Name      string `bson:"name"`
+				Queryable bool   `bson:"queryable"`
+			}
+			if err := cursor.All(ctx, &indexes); err != nil {
+				t.Fatalf("unable to list search indexes: %v", err)
+			}
+
+			for _, index := range indexes {
+				queryable[index.Name] = index.Queryable
+			}
+		}
+
+		ready := true
+		for _, index := range settings.searchIndexes() {
+			ready = ready && queryable[index.Name]
+		}
+
+		if ready {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("search indexes of store %s weren't built within %s, last error: %v", store, atlasIndexTimeout, err)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+func setValue(t *testing.T, server *MongoDBServer, store string, key string, content map[string]interface{}) {
+	t.Helper()
+
+	value, err := structpb.NewStruct(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = server.SetValue(context.Background(), &kvstorepb.KvStoreSetValueRequest{
+		Ref:     &kvstorepb.ValueRef{Store: store, Key: key},
+		Content: value,
+	})
+	if err != nil {
+		t.Fatalf("unable to set %s: %v", key, err)
+	}
+}
+
+func TestAtlasVectors(t *testing.T) {
+	settings := StoreSettings{
+		Vectors: map[string]VectorSettings{
+			"embedding": {Dimensions: 3, Filters: []string{"kind"}},
+		},
+	}
+
+	server := atlasServer(t, map[string]StoreSettings{"articles": settings})
+	vectors := NewVectors(server)
+	ctx := context.Background()
+
+	values := map[string][]float64{
+		"north": {0, 1, 0},
+		"east":  {1, 0, 0},
+		"west":  {-1, 0.1, 0},
+	}
+
+	for key, embedding := range values {
+		setValue(t, server, "articles", key, map[string]interface{}{"kind": "compass"})
+
+		_, err := vectors.Upsert(ctx, &vectorspb.VectorsUpsertRequest{Store: "articles", Key: key, Vector: "embedding", Values: embedding})
+		if err != nil {
+			t.Fatalf("unable to upsert vector of %s: %v", key, err)
+		}
+	}
+
+	setValue(t, server, "articles", "other", map[string]interface{}{"kind": "other"})
+	_, err := vectors.Upsert(ctx, &vectorspb.VectorsUpsertRequest{Store: "articles", Key: "other", Vector: "embedding", Values: []float64{0.9, 0.1, 0}})
+	if err != nil {
+		t.Fatalf("unable to upsert vector of other: %v", err)
+	}
+
+	waitForSearchIndexes(t, server, "articles", settings)
+
+	req := &vectorspb.VectorsQueryRequest{
+		Store:  "articles",
+		Vector: "embedding",
+		Values: []float64{0.9, 0.2, 0},
+		TopK:   2,
+		Filters: []*documentspb.Filter{{
+			Path:      "kind",
+			Condition: &documentspb.Filter_Equals{Equals: structpb.NewStringValue("compass")},
+		}},
+	}
+
+	// Values are indexed shortly after they are written
+	deadline := time.Now().Add(atlasIndexTimeout)
+	for {
+		res, err := vectors.Query(ctx, req)
+		if err != nil {
+			t.Fatalf("unable to query vectors: %v", err)
+		}
+
+		if len(res.Matches) == 2 {
+			if res.Matches[0].Key != "east" || res.Matches[1].Key != "north" {
+				t.Fatalf("expected matches east and north, got %s and %s", res.Matches[0].Key, res.Matches[1].Key)
+			}
+
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 matches, got %d", len(res.Matches))
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+func TestAtlasSearch(t *testing.T) {
+	settings := StoreSettings{
+		Search: &SearchSettings{
+			Analyzer: "lucene.english",
+			Fields:   []SearchFieldSettings{{Path: "subject"}, {Path: "customer.name", Analyzer: "lucene.standard"}},
+			Facets:   []string{"status"},
+		},
+	}
+
+	server := atlasServer(t, map[string]StoreSettings{"tickets": settings})
+	search := NewSearch(server)
+
+	setValue(t, server, "tickets", "1", map[string]interface{}{
+		"subject":  "Printer is jammed",
+		"status":   "open",
+		"customer": map[string]interface{}{"name": "Ada"},
+	})
+	setValue(t, server, "tickets", "2", map[string]interface{}{
+		"subject":  "Printers out of toner",
+		"status":   "closed",
+		"customer": map[string]interface{}{"name": "Grace"},
+	})
+	setValue(t, server, "tickets", "3", map[string]interface{}{
+		"subject":  "Password reset",
+		"status":   "open",
+		"customer": map[string]interface{}{"name": "Linus"},
+	})
+
+	waitForSearchIndexes(t, server, "tickets", settings)
+
+	// The misspelling is only matched fuzzily
+	req := &searchpb.SearchQueryRequest{
+		Store:     "tickets",
+		Text:      "printr",
+		Fuzzy:     true,
+		Highlight: true,
+		Facets:    []string{"status"},
+	}
+
+	// Values are indexed shortly after they are written
+	deadline := time.Now().Add(atlasIndexTimeout)
+	for {
+		res, err := search.Query(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unable to search: %v", err)
+		}
+
+		if res.Total == 2 {
+			keys := map[string]bool{}
+			for _, match := range res.Matches {
+				keys[match.Key] = true
+			}
+
+			if !keys["1"] || !keys["2"] {
+				t.Fatalf("expected matches 1 and 2, got %v", keys)
+			}
+
+			counts := map[string]int64{}
+			for _, bucket := range res.Facets["status"].GetBuckets() {
+				counts[bucket.Value] = bucket.Count
+			}
+
+			if counts["open"] != 1 || counts["closed"] != 1 {
+				t.Fatalf("expected one open and one closed match, got %v", counts)
+			}
+
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 matches, got %d", res.Total)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,278 @@
+package common
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// Approximate bookkeeping memory of a cache entry on top of its key and content
+	cacheEntryOverhead = 128
+	// How long to wait before reopening a failed invalidation change stream
+	cacheWatchRetryInterval = 5 * time.Second
+)
+
+type cacheKey struct {
+	store string
+	key   string
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	content *structpb.Struct
+	size    int64
+}
+
+// valueCache is a least recently used cache of values, bounded by the approximate memory of its entries.
+//
+// Concurrent misses for the same key share a single read. Entries are invalidated by writes through
+// this runtime and by a change stream on the cached stores, so writes by other runtimes are seen shortly after.
+type valueCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+	size    int64
+	// Keys being read, set when they are invalidated during the read so the stale value isn't cached
+	loading map[cacheKey]bool
+
+	loads singleflight.Group
+
+	hits      metric.Int64Counter
+	misses    metric.Int64Counter
+	evictions metric.Int64Counter
+}
+
+func newValueCache(maxBytes int64) *valueCache {
+	meter := otel.Meter("github.com/nitrictech/mongodb-provider")
+
+	// Counters are no-ops unless the runtime registers a meter provider, such as the MetricsReporter
+	hits, _ := meter.Int64Counter("kvstore.cache.hits", metric.WithDescription("Key value reads served from the cache"))
+	misses, _ := meter.Int64Counter("kvstore.cache.misses", metric.WithDescription("Key value reads not found in the cache"))
+	evictions, _ := meter.Int64Counter("kvstore.cache.evictions", metric.WithDescription("Values evicted from the cache to stay within its size"))
+
+	return &valueCache{
+		maxBytes:  maxBytes,
+		entries:   map[cacheKey]*list.Element{},
+		loading:   map[cacheKey]bool{},
+		lru:       list.New(),
+		hits:      hits,
+		misses:    misses,
+		evictions: evictions,
+	}
+}
+
+// Get a value from the cache, loading it on a miss. Callers receive their own copy of the content.
+func (c *valueCache) get(ctx context.Context, store string, key string, load func(context.Context) (*structpb.Struct, error)) (*structpb.Struct, error) {
+	k := cacheKey{store: store, key: key}
+	storeAttr := metric.WithAttributes(attribute.String("store", store))
+
+	c.mu.Lock()
+	if el, ok := c.entries[k]; ok {
+		c.lru.MoveToFront(el)
+		content := el.Value.(*cacheEntry).content
+		c.mu.Unlock()
+
+		c.hits.Add(ctx, 1, storeAttr)
+
+		return proto.Clone(content).(*structpb.Struct), nil
+	}
+	c.mu.Unlock()
+
+	c.misses.Add(ctx, 1, storeAttr)
+
+	res, err, _ := c.loads.Do(store+"\x00"+key, func() (interface{}, error) {
+		c.mu.Lock()
+		c.loading[k] = false
+		c.mu.Unlock()
+
+		// The read is shared, so it shouldn't fail because the first caller went away
+		content, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			c.mu.Lock()
+			delete(c.loading, k)
+			c.mu.Unlock()
+
+			return nil, err
+		}
+
+		c.add(k, content)
+
+		return content, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Clone(res.(*structpb.Struct)).(*structpb.Struct), nil
+}
+
+func (c *valueCache) add(k cacheKey, content *structpb.Struct) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The value may have changed while it was read
+	stale := c.loading[k]
+	delete(c.loading, k)
+	if stale {
+		return
+	}
+
+	if el, ok := c.entries[k]; ok {
+		c.remove(el)
+	}
+
+	entry := &cacheEntry{
+		key:     k,
+		content: content,
+		size:    int64(proto.Size(content) + len(k.store) + len(k.key) + cacheEntryOverhead),
+	}
+
+	// Values larger than the whole cache aren't cached
+	if entry.size > c.maxBytes {
+		return
+	}
+
+	c.entries[k] = c.lru.PushFront(entry)
+	c.size += entry.size
+
+	for c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+		c.evictions.Add(context.Background(), 1, metric.WithAttributes(attribute.String("store", k.store)))
+	}
+}
+
+// Remove an entry, the lock must be held
+func (c *valueCache) remove(el *list.Element) {
+	entry := c.lru.Remove(el).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
+
+func (c *valueCache) invalidate(store string, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := cacheKey{store: store, key: key}
+	if _, ok := c.loading[k]; ok {
+		c.loading[k] = true
+	}
+
+	if el, ok := c.entries[k]; ok {
+		c.remove(el)
+	}
+}
+
+// Invalidate every entry of a store, or of all stores when store is empty
+func (c *valueCache) invalidateStore(store string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range c.loading {
+		if store == "" || k.store == store {
+			c.loading[k] = true
+		}
+	}
+
+	for k, el := range c.entries {
+		if store == "" || k.store == store {
+			c.remove(el)
+		}
+	}
+}
+
+// Invalidate entries as the cached stores change, reopening the change stream if it fails
+func (c *valueCache) watch(ctx context.Context, db *mongo.Database, stores []string) {
+	pipeline := mongo.Pipeline{bson.D{{"$match", bson.D{
+		{"$or", bson.A{
+			bson.D{{"ns.coll", bson.D{{"$in", stores}}}},
+			// Restores rename a staging collection over the store
+			bson.D{{"to.coll", bson.D{{"$in", stores}}}},
+			// Database wide events have no collection
+			bson.D{{"operationType", bson.D{{"$in", bson.A{"dropDatabase", "invalidate"}}}}},
+		}},
+	}}}}
+
+	for {
+		err := c.watchChanges(ctx, db, pipeline)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Changes may have been missed while the stream was down
+		c.invalidateStore("")
+		if err != nil {
+			logger.Errorf("cache invalidation stream failed, retrying in %s: %v", cacheWatchRetryInterval, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cacheWatchRetryInterval):
+		}
+	}
+}
+
+func (c *valueCache) watchChanges(ctx context.Context, db *mongo.Database, pipeline mongo.Pipeline) error {
+	changes, err := db.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer changes.Close(ctx)
+
+	// Anything cached before the stream opened may already be stale
+	c.invalidateStore("")
+
+	for changes.Next(ctx) {
+		var change struct {
+			OperationType string `bson:"operationType"`
+			Ns            struct {
+				Coll string `bson:"coll"`
+			} `bson:"ns"`
+			To struct {
+				Coll string `bson:"coll"`
+			} `bson:"to"`
+			DocumentKey struct {
+				Id bson.RawValue `bson:"_id"`
+			} `bson:"documentKey"`
+		}
+		if err := changes.Decode(&change); err != nil {
+			return err
+		}
+
+		switch change.OperationType {
+		case "insert", "update", "replace", "delete":
+			if key, ok := change.DocumentKey.Id.StringValueOK(); ok {
+				c.invalidate(change.Ns.Coll, key)
+			}
+
+			// Interop stores cache values by the keys of their _ids, which differ for typed _ids
+			if key, ok := interopKey(change.DocumentKey.Id); ok {
+				c.invalidate(change.Ns.Coll, key)
+			}
+		default:
+			// Drops, renames and invalidations affect every value of the store
+			c.invalidateStore(change.Ns.Coll)
+
+			// A collection renamed over a store replaces its values
+			if change.To.Coll != "" {
+				c.invalidateStore(change.To.Coll)
+			}
+		}
+	}
+
+	return changes.Err()
+}
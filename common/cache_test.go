@@ -0,0 +1,193 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func testContent(t *testing.T, value string) *structpb.Struct {
+	t.Helper()
+
+	content, err := structpb.NewStruct(map[string]interface{}{"value": value})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return content
+}
+
+// A load that counts how many times it was called
+func countingLoad(content *structpb.Struct, loads *int) func(context.Context) (*structpb.Struct, error) {
+	return func(context.Context) (*structpb.Struct, error) {
+		*loads++
+		return content, nil
+	}
+}
+
+func TestValueCacheLru(t *testing.T) {
+	ctx := context.Background()
+	content := testContent(t, "v")
+
+	// Room for two entries of the same size
+	entrySize := int64(proto.Size(content) + len("orders") + len("a") + cacheEntryOverhead)
+	cache := newValueCache(2*entrySize + entrySize/2)
+
+	loads := 0
+	for _, key := range []string{"a", "b", "a"} {
+		if _, err := cache.get(ctx, "orders", key, countingLoad(content, &loads)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if loads != 2 {
+		t.Fatalf("expected the second read of a to be served from the cache, got %d loads", loads)
+	}
+
+	// a was used more recently, so b is evicted
+	if _, err := cache.get(ctx, "orders", "c", countingLoad(content, &loads)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.entries[cacheKey{store: "orders", key: "b"}]; ok {
+		t.Fatal("expected b to be evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.entries[cacheKey{store: "orders", key: key}]; !ok {
+			t.Fatalf("expected %s to be cached", key)
+		}
+	}
+
+	if cache.size > cache.maxBytes {
+		t.Fatalf("cache size %d exceeds its maximum %d", cache.size, cache.maxBytes)
+	}
+}
+
+func TestValueCacheLargeValue(t *testing.T) {
+	cache := newValueCache(16)
+
+	if _, err := cache.get(context.Background(), "orders", "a", countingLoad(testContent(t, "v"), new(int))); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cache.entries) != 0 || cache.size != 0 {
+		t.Fatal("expected a value larger than the cache not to be cached")
+	}
+}
+
+func TestValueCacheReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	cache := newValueCache(1 << 20)
+
+	first, err := cache.get(ctx, "orders", "a", countingLoad(testContent(t, "v"), new(int)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Fields["value"] = structpb.NewStringValue("changed")
+
+	second, err := cache.get(ctx, "orders", "a", countingLoad(testContent(t, "other"), new(int)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second.Fields["value"].GetStringValue() != "v" {
+		t.Fatalf("expected the cached value to be unchanged, got %v", second.Fields["value"])
+	}
+}
+
+func TestValueCacheInvalidateWhileLoading(t *testing.T) {
+	ctx := context.Background()
+	cache := newValueCache(1 << 20)
+
+	// The value is written while it is read, so the read may be stale and isn't cached
+	_, err := cache.get(ctx, "orders", "a", func(context.Context) (*structpb.Struct, error) {
+		cache.invalidate("orders", "a")
+		return testContent(t, "stale"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.entries[cacheKey{store: "orders", key: "a"}]; ok {
+		t.Fatal("expected a value read across an invalidation not to be cached")
+	}
+
+	loads := 0
+	content, err := cache.get(ctx, "orders", "a", countingLoad(testContent(t, "fresh"), &loads))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loads != 1 || content.Fields["value"].GetStringValue() != "fresh" {
+		t.Fatalf("expected the value to be read again, got %v after %d loads", content.Fields["value"], loads)
+	}
+}
+
+func TestValueCacheInvalidateOtherKeyWhileLoading(t *testing.T) {
+	ctx := context.Background()
+	cache := newValueCache(1 << 20)
+
+	// Writes to other keys, even of other stores, don't affect the value being read
+	_, err := cache.get(ctx, "orders", "a", func(context.Context) (*structpb.Struct, error) {
+		cache.invalidate("orders", "b")
+		cache.invalidateStore("users")
+		return testContent(t, "v"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.entries[cacheKey{store: "orders", key: "a"}]; !ok {
+		t.Fatal("expected a value read across invalidations of other keys to be cached")
+	}
+
+	// Invalidating the whole store does affect it
+	_, err = cache.get(ctx, "orders", "c", func(context.Context) (*structpb.Struct, error) {
+		cache.invalidateStore("orders")
+		return testContent(t, "stale"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.entries[cacheKey{store: "orders", key: "c"}]; ok {
+		t.Fatal("expected a value read across an invalidation of its store not to be cached")
+	}
+
+	if len(cache.loading) != 0 {
+		t.Fatalf("expected no keys to be left loading, got %v", cache.loading)
+	}
+}
+
+func TestValueCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	cache := newValueCache(1 << 20)
+
+	for _, k := range []cacheKey{{"orders", "a"}, {"orders", "b"}, {"users", "a"}} {
+		if _, err := cache.get(ctx, k.store, k.key, countingLoad(testContent(t, "v"), new(int))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache.invalidate("orders", "a")
+	if _, ok := cache.entries[cacheKey{"orders", "a"}]; ok {
+		t.Fatal("expected orders a to be invalidated")
+	}
+
+	cache.invalidateStore("orders")
+	if _, ok := cache.entries[cacheKey{"orders", "b"}]; ok {
+		t.Fatal("expected every value of orders to be invalidated")
+	}
+
+	if _, ok := cache.entries[cacheKey{"users", "a"}]; !ok {
+		t.Fatal("expected the values of other stores to be kept")
+	}
+
+	cache.invalidateStore("")
+	if len(cache.entries) != 0 || cache.size != 0 || cache.lru.Len() != 0 {
+		t.Fatal("expected every value to be invalidated")
+	}
+}
@@ -0,0 +1,376 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cappedpb "github.com/nitrictech/mongodb-provider/common/proto/capped/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// Mongo requires a size for capped collections, stores that only limit their documents are given this one
+	defaultCappedMaxBytes = 1 << 30
+
+	defaultCappedLimit = 100
+	maxCappedLimit     = 1000
+	// How long a tail waits to reopen its cursor when it ends, e.g. because the store is empty
+	cappedTailRetryInterval = time.Second
+
+	// A tailable cursor's position was dropped before it was read
+	mongoErrCappedPositionLost = 136
+	// A value of a capped store was replaced with one of a different size
+	mongoErrCappedDocumentSize = 10003
+)
+
+// CappedSettings declare a key value store as capped, it drops its oldest values when it is full
+type CappedSettings struct {
+	// The size of the store, defaults to 1 GiB
+	MaxBytes int64 `json:"max-bytes,omitempty"`
+	// The number of values of the store, unlimited when zero
+	MaxDocuments int64 `json:"max-documents,omitempty"`
+}
+
+func (c CappedSettings) maxBytes() int64 {
+	if c.MaxBytes == 0 {
+		return defaultCappedMaxBytes
+	}
+
+	return c.MaxBytes
+}
+
+func (c CappedSettings) validate() error {
+	if c.MaxBytes < 0 || c.MaxDocuments < 0 {
+		return fmt.Errorf("max-bytes and max-documents must not be negative")
+	}
+
+	if c.MaxBytes == 0 && c.MaxDocuments == 0 {
+		return fmt.Errorf("requires max-bytes or max-documents")
+	}
+
+	return nil
+}
+
+// Create a capped collection, or check the existing collection is capped and update its limits
+func ensureCapped(ctx context.Context, coll *mongo.Collection, settings CappedSettings) error {
+	ns := coll.Database().Name() + "." + coll.Name()
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(settings.maxBytes())
+	if settings.MaxDocuments > 0 {
+		opts.SetMaxDocuments(settings.MaxDocuments)
+	}
+
+	spec, err := createCollection(ctx, coll, opts)
+	if err != nil || spec == nil {
+		return err
+	}
+
+	if capped, _ := spec.Options.Lookup("capped").BooleanOK(); !capped {
+		return fmt.Errorf("%s exists and isn't a capped collection", ns)
+	}
+
+	// Mongo rounds sizes up to a multiple of 256 bytes
+	size, _ := spec.Options.Lookup("size").AsInt64OK()
+	max, _ := spec.Options.Lookup("max").AsInt64OK()
+	if size == (settings.maxBytes()+255)/256*256 && max == settings.MaxDocuments {
+		return nil
+	}
+
+	err = coll.Database().RunCommand(ctx, bson.D{
+		{"collMod", coll.Name()},
+		{"cappedSize", settings.maxBytes()},
+		{"cappedMax", settings.MaxDocuments},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("unable to update the limits of %s: %w", ns, err)
+	}
+
+	logger.Infof("capped %s: limits updated", ns)
+
+	return nil
+}
+
+// Capped stores can't delete values, mongo only drops them oldest first
+func (k *MongoDBServer) cappedDeleteErr(newErr grpc_errors.ScopedErrorFactory, store string) error {
+	if _, ok := k.collections.cappedOf(store); !ok {
+		return nil
+	}
+
+	return newErr(
+		codes.FailedPrecondition,
+		fmt.Sprintf("store %s is capped, its values can't be deleted, they are dropped oldest first when it is full", store),
+		fmt.Errorf("capped store"),
+	)
+}
+
+// MongoCappedServer reads the capped stores of MongoDBServer in the order their keys were first set
+type MongoCappedServer struct {
+	kv *MongoDBServer
+}
+
+var _ cappedpb.CappedServer = &MongoCappedServer{}
+
+// The collection of a capped store, the tenant is given as a key prefix would be in key tenancy mode
+func (c *MongoCappedServer) scope(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, store string, tenant string) (*mongo.Collection, string, error) {
+	if _, ok := c.kv.collections.cappedOf(store); !ok {
+		return nil, "", newErr(
+			codes.FailedPrecondition,
+			"capped store not declared",
+			fmt.Errorf("store %s isn't a capped store", store),
+		)
+	}
+
+	tenantPrefix := ""
+	if c.kv.tenancy == TenancyModeKey {
+		tenantPrefix = tenant + tenantKeySeparator
+	}
+
+	coll, _, err := c.kv.scopedCollection(ctx, store, tenantPrefix)
+	if err != nil {
+		return nil, "", newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := c.kv.quotas.allow(ctx, store); err != nil {
+		return nil, "", quotaErr(newErr, err)
+	}
+
+	return coll, tenantPrefix, nil
+}
+
+// cappedReader reads a capped store in natural order, which is the order keys were first set.
+//
+// Positions are keys, so values are read after a key by skipping those before it.
+// Values are dropped oldest first, so when the key has been dropped every remaining value is after it.
+type cappedReader struct {
+	coll         *mongo.Collection
+	tenantPrefix string
+	after        string
+	skipping     bool
+}
+
+// Start reading from the position, a tailable cursor waits for new values at the end of the store
+func (r *cappedReader) open(ctx context.Context, tailable bool) (*mongo.Cursor, error) {
+	r.skipping = false
+	if r.after != "" {
+		count, err := r.coll.CountDocuments(ctx, bson.D{{"_id", r.after}})
+		if err != nil {
+			return nil, err
+		}
+
+		r.skipping = count > 0
+	}
+
+	opts := options.Find().SetSort(bson.D{{"$natural", 1}})
+	if tailable {
+		opts.SetCursorType(options.TailableAwait)
+	}
+
+	return r.coll.Find(ctx, bson.D{}, opts)
+}
+
+// The entry of the current document, nil while skipping to the position
+func (r *cappedReader) entry(cursor *mongo.Cursor) (*cappedpb.CappedEntry, error) {
+	key := keyString(cursor.Current.Lookup("_id"))
+
+	if r.skipping {
+		r.skipping = key != r.after
+		return nil, nil
+	}
+
+	content, err := contentFromDocument(cursor.Current)
+	if err != nil {
+		return nil, err
+	}
+
+	r.after = key
+
+	return &cappedpb.CappedEntry{
+		Key:     r.tenantPrefix + key,
+		Content: content,
+	}, nil
+}
+
+// The key within the store of a position given by a caller
+func (r *cappedReader) position(after string) error {
+	if after == "" || r.tenantPrefix == "" {
+		r.after = after
+		return nil
+	}
+
+	if len(after) < len(r.tenantPrefix) || after[:len(r.tenantPrefix)] != r.tenantPrefix {
+		return fmt.Errorf("the key must start with the tenant")
+	}
+
+	r.after = after[len(r.tenantPrefix):]
+
+	return nil
+}
+
+// Read a page of values, oldest first
+func (c *MongoCappedServer) Read(ctx context.Context, req *cappedpb.CappedReadRequest) (*cappedpb.CappedReadResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoCappedServer.Read")
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultCappedLimit
+	}
+
+	if limit < 0 || limit > maxCappedLimit {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("limit must be between 1 and %d", maxCappedLimit),
+			fmt.Errorf("limit %d", req.Limit),
+		)
+	}
+
+	coll, tenantPrefix, err := c.scope(ctx, newErr, req.Store, req.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &cappedReader{coll: coll, tenantPrefix: tenantPrefix}
+	if err := reader.position(req.After); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid after key",
+			err,
+		)
+	}
+
+	cursor, err := reader.open(ctx, false)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read store %s", req.Store),
+			err,
+		)
+	}
+	defer cursor.Close(ctx)
+
+	res := &cappedpb.CappedReadResponse{
+		Entries: []*cappedpb.CappedEntry{},
+	}
+
+	for len(res.Entries) < limit && cursor.Next(ctx) {
+		entry, err := reader.entry(cursor)
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"unable to convert value to pb struct",
+				err,
+			)
+		}
+
+		if entry != nil {
+			res.Entries = append(res.Entries, entry)
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read store %s", req.Store),
+			err,
+		)
+	}
+
+	return res, nil
+}
+
+// Stream the values after a key, then each new key as it is set.
+//
+// Values set again under an existing key keep their position, so they aren't streamed again.
+func (c *MongoCappedServer) Tail(req *cappedpb.CappedTailRequest, stream cappedpb.Capped_TailServer) error {
+	newErr := grpc_errors.ErrorsWithScope("MongoCappedServer.Tail")
+	ctx := stream.Context()
+
+	coll, tenantPrefix, err := c.scope(ctx, newErr, req.Store, req.Tenant)
+	if err != nil {
+		return err
+	}
+
+	reader := &cappedReader{coll: coll, tenantPrefix: tenantPrefix}
+	if err := reader.position(req.After); err != nil {
+		return newErr(
+			codes.InvalidArgument,
+			"invalid after key",
+			err,
+		)
+	}
+
+	for {
+		cursor, err := reader.open(ctx, true)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to tail store %s", req.Store),
+				err,
+			)
+		}
+
+		for cursor.Next(ctx) {
+			entry, err := reader.entry(cursor)
+			if err != nil {
+				cursor.Close(ctx)
+				return newErr(
+					codes.Internal,
+					"unable to convert value to pb struct",
+					err,
+				)
+			}
+
+			if entry == nil {
+				continue
+			}
+
+			if err := stream.Send(entry); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+		}
+
+		err = cursor.Err()
+		cursor.Close(context.Background())
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Cursors end when the store is empty, or when they fall behind values being dropped and lose their position
+		var serverErr mongo.ServerError
+		if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCappedPositionLost)) {
+			return newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to tail store %s", req.Store),
+				err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cappedTailRetryInterval):
+		}
+	}
+}
+
+func NewCapped(kv *MongoDBServer) *MongoCappedServer {
+	return &MongoCappedServer{
+		kv: kv,
+	}
+}
@@ -0,0 +1,174 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cappedpb "github.com/nitrictech/mongodb-provider/common/proto/capped/v1"
+	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCappedSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings CappedSettings
+		err      bool
+	}{
+		{name: "size", settings: CappedSettings{MaxBytes: 1 << 20}},
+		{name: "documents", settings: CappedSettings{MaxDocuments: 100}},
+		{name: "no limits", settings: CappedSettings{}, err: true},
+		{name: "negative size", settings: CappedSettings{MaxBytes: -1, MaxDocuments: 100}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.settings.validate(); test.err != (err != nil) {
+				t.Fatalf("expected error to be %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestCappedRejected(t *testing.T) {
+	ctx := context.Background()
+	server := &MongoDBServer{collections: &storeCollections{capped: map[string]CappedSettings{"events": {MaxDocuments: 3}}}}
+	capped := NewCapped(server)
+
+	// Mongo only drops the values of capped stores oldest first
+	_, err := server.DeleteKey(ctx, &kvstorepb.KvStoreDeleteKeyRequest{Ref: &kvstorepb.ValueRef{Store: "events", Key: "a"}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected deleting from a capped store to be FailedPrecondition, got %v", err)
+	}
+
+	_, err = capped.Read(ctx, &cappedpb.CappedReadRequest{Store: "orders"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected reading a store that isn't capped to be FailedPrecondition, got %v", err)
+	}
+
+	_, err = capped.Read(ctx, &cappedpb.CappedReadRequest{Store: "events", Limit: maxCappedLimit + 1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected a limit above the maximum to be InvalidArgument, got %v", err)
+	}
+}
+
+func TestCappedReaderPosition(t *testing.T) {
+	reader := &cappedReader{tenantPrefix: "acme/"}
+
+	if err := reader.position("acme/b"); err != nil || reader.after != "b" {
+		t.Fatalf("expected the key within the tenant, got %q, %v", reader.after, err)
+	}
+
+	if err := reader.position("other/b"); err == nil {
+		t.Fatal("expected a key of another tenant to be rejected")
+	}
+}
+
+// A tail stream that ends once it has received enough entries
+type cappedTailStream struct {
+	testStream[*cappedpb.CappedEntry]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	want   int
+}
+
+func (s *cappedTailStream) Send(entry *cappedpb.CappedEntry) error {
+	s.sent = append(s.sent, entry)
+	if len(s.sent) == s.want {
+		s.cancel()
+	}
+
+	return nil
+}
+
+func (s *cappedTailStream) Context() context.Context {
+	return s.ctx
+}
+
+func cappedKeys(entries []*cappedpb.CappedEntry) string {
+	keys := []string{}
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+
+	return fmt.Sprint(keys)
+}
+
+func TestCapped(t *testing.T) {
+	ctx := context.Background()
+	server := testServer(t, WithStore("events", StoreSettings{Capped: &CappedSettings{MaxDocuments: 3}}))
+	capped := NewCapped(server)
+
+	set := func(key string, value string) {
+		t.Helper()
+
+		_, err := server.SetValue(ctx, &kvstorepb.KvStoreSetValueRequest{Ref: &kvstorepb.ValueRef{Store: "events", Key: key}, Content: testContent(t, value)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		set(key, "v")
+	}
+
+	// Setting a key again keeps its position
+	set("c", "w")
+
+	tests := []struct {
+		name string
+		req  *cappedpb.CappedReadRequest
+		keys string
+	}{
+		{name: "oldest first", req: &cappedpb.CappedReadRequest{Store: "events"}, keys: "[c d e]"},
+		{name: "after a key", req: &cappedpb.CappedReadRequest{Store: "events", After: "c"}, keys: "[d e]"},
+		{name: "after a dropped key", req: &cappedpb.CappedReadRequest{Store: "events", After: "a"}, keys: "[c d e]"},
+		{name: "limit", req: &cappedpb.CappedReadRequest{Store: "events", Limit: 1}, keys: "[c]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := capped.Read(ctx, test.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if keys := cappedKeys(res.Entries); keys != test.keys {
+				t.Fatalf("expected %s, got %s", test.keys, keys)
+			}
+		})
+	}
+
+	res, err := capped.Read(ctx, &cappedpb.CappedReadRequest{Store: "events", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := res.Entries[0].Content.Fields["value"].GetStringValue(); value != "w" {
+		t.Fatalf("expected the latest value of c, got %q", value)
+	}
+
+	// Tails stream the values after the key and then new keys as they are set
+	tailCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	stream := &cappedTailStream{ctx: tailCtx, cancel: cancel, want: 2}
+	done := make(chan error)
+	go func() {
+		done <- capped.Tail(&cappedpb.CappedTailRequest{Store: "events", After: "d"}, stream)
+	}()
+
+	set("f", "v")
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := cappedKeys(stream.sent); keys != "[e f]" {
+		t.Fatalf("expected e and then the new key f, got %s", keys)
+	}
+}
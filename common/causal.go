@@ -0,0 +1,198 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata key of the causal consistency token, requests carry the last token the caller received
+// and responses return it advanced past their operation as a trailer
+const CausalTokenMetadataKey = "x-nitric-causal-token"
+
+// The times of the last operation a caller has seen on a cluster
+type causalTimes struct {
+	ClusterTime   bson.Raw            `bson:"clusterTime"`
+	OperationTime primitive.Timestamp `bson:"operationTime"`
+}
+
+// causalToken holds the times of each cluster stores are routed to, by target name, the stack's cluster has no name.
+//
+// Cluster times are signed by their cluster, so each is only given to sessions of that cluster.
+type causalToken struct {
+	Targets map[string]causalTimes `bson:"targets"`
+}
+
+func decodeCausalToken(encoded string) (causalToken, error) {
+	token := causalToken{}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, err
+	}
+
+	if err := bson.Unmarshal(raw, &token); err != nil {
+		return token, err
+	}
+
+	if token.Targets == nil {
+		token.Targets = map[string]causalTimes{}
+	}
+
+	return token, nil
+}
+
+func (t causalToken) encode() (string, error) {
+	raw, err := bson.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// causalSession runs the operations of a request after those the caller has seen, nil when causal consistency is disabled
+type causalSession struct {
+	session mongo.Session
+	target  string
+	token   causalToken
+	// Whether the caller gave a token, so reads mustn't be served from caches that may predate it
+	resumed bool
+}
+
+// The name of the target a store is routed to, empty for the stack's cluster
+func (k *MongoDBServer) targetOf(store string) string {
+	db, ok := k.storeDatabases[store]
+	if !ok {
+		return ""
+	}
+
+	for name, client := range k.targetClients {
+		if client == db.Client() {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Run a request's operations on a store in a causally consistent session.
+//
+// The returned context carries the session and the returned collection reads and writes with majority concern,
+// which causal consistency requires to hold across elections and reads from secondaries.
+func (k *MongoDBServer) causalScope(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, store string, coll *mongo.Collection) (context.Context, *mongo.Collection, *causalSession, error) {
+	if !k.causal {
+		return ctx, coll, nil, nil
+	}
+
+	causal := &causalSession{
+		target: k.targetOf(store),
+		token:  causalToken{Targets: map[string]causalTimes{}},
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(CausalTokenMetadataKey)
+	if len(tokens) > 1 {
+		return nil, nil, nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("requests must include at most one %s metadata value", CausalTokenMetadataKey),
+			fmt.Errorf("%d causal tokens", len(tokens)),
+		)
+	}
+
+	if len(tokens) == 1 {
+		token, err := decodeCausalToken(tokens[0])
+		if err != nil {
+			return nil, nil, nil, newErr(
+				codes.InvalidArgument,
+				"invalid causal consistency token",
+				err,
+			)
+		}
+
+		causal.token = token
+		causal.resumed = true
+	}
+
+	coll, err := coll.Clone(options.Collection().SetReadConcern(readconcern.Majority()).SetWriteConcern(writeconcern.Majority()))
+	if err != nil {
+		return nil, nil, nil, newErr(
+			codes.Internal,
+			"unable to configure the store for causal consistency",
+			err,
+		)
+	}
+
+	session, err := coll.Database().Client().StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return nil, nil, nil, newErr(
+			codes.Internal,
+			"unable to start a causally consistent session",
+			err,
+		)
+	}
+
+	if times, ok := causal.token.Targets[causal.target]; ok {
+		err = session.AdvanceClusterTime(times.ClusterTime)
+		if err == nil {
+			err = session.AdvanceOperationTime(&times.OperationTime)
+		}
+
+		if err != nil {
+			session.EndSession(ctx)
+			return nil, nil, nil, newErr(
+				codes.InvalidArgument,
+				"invalid causal consistency token",
+				err,
+			)
+		}
+	}
+
+	causal.session = session
+
+	return mongo.NewSessionContext(ctx, session), coll, causal, nil
+}
+
+// Whether reads must go to the cluster rather than a cache
+func (c *causalSession) bypassCache() bool {
+	return c != nil && c.resumed
+}
+
+// End the session, returning the token advanced past its operations to the caller
+func (c *causalSession) end(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	defer c.session.EndSession(ctx)
+
+	// Sessions that didn't reach the cluster leave the caller's token as it was
+	operationTime := c.session.OperationTime()
+	if operationTime == nil {
+		return
+	}
+
+	c.token.Targets[c.target] = causalTimes{
+		ClusterTime:   c.session.ClusterTime(),
+		OperationTime: *operationTime,
+	}
+
+	encoded, err := c.token.encode()
+	if err != nil {
+		return
+	}
+
+	// The stream of a request may already be closed, in which case the caller can't be given the token
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(CausalTokenMetadataKey, encoded))
+}
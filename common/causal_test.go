@@ -0,0 +1,68 @@
+package common
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCausalTokenRoundTrip(t *testing.T) {
+	clusterTime, err := bson.Marshal(bson.D{{"clusterTime", primitive.Timestamp{T: 1700000000, I: 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := causalToken{Targets: map[string]causalTimes{
+		"":          {ClusterTime: clusterTime, OperationTime: primitive.Timestamp{T: 1700000000, I: 2}},
+		"analytics": {ClusterTime: clusterTime, OperationTime: primitive.Timestamp{T: 1700000100, I: 1}},
+	}}
+
+	encoded, err := token.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeCausalToken(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(decoded.Targets))
+	}
+
+	for name, times := range token.Targets {
+		got := decoded.Targets[name]
+		if !got.OperationTime.Equal(times.OperationTime) {
+			t.Fatalf("target %q: expected operation time %v, got %v", name, times.OperationTime, got.OperationTime)
+		}
+
+		if string(got.ClusterTime) != string(times.ClusterTime) {
+			t.Fatalf("target %q: expected cluster time %v, got %v", name, times.ClusterTime, got.ClusterTime)
+		}
+	}
+}
+
+func TestDecodeCausalToken(t *testing.T) {
+	// A token without targets decodes to an empty token that can be added to
+	encoded, err := causalToken{}.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := decodeCausalToken(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.Targets == nil {
+		t.Fatal("expected the targets of the token to be initialised")
+	}
+
+	for _, invalid := range []string{"not base64!", "AAAA"} {
+		if _, err := decodeCausalToken(invalid); err == nil {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// storeCollections creates the collections of time-series and capped stores before they are written to,
+// since mongo would otherwise create them as regular collections
+type storeCollections struct {
+	timeSeries map[string]TimeSeriesSettings
+	capped     map[string]CappedSettings
+
+	// Collections that have been created or checked, by namespace
+	ensured sync.Map
+}
+
+func (c *storeCollections) timeSeriesOf(store string) (TimeSeriesSettings, bool) {
+	if c == nil {
+		return TimeSeriesSettings{}, false
+	}
+
+	settings, ok := c.timeSeries[store]
+
+	return settings, ok
+}
+
+func (c *storeCollections) cappedOf(store string) (CappedSettings, bool) {
+	if c == nil {
+		return CappedSettings{}, false
+	}
+
+	settings, ok := c.capped[store]
+
+	return settings, ok
+}
+
+// Create a store's collection, or check the existing collection matches its declaration
+func (c *storeCollections) ensure(ctx context.Context, store string, coll *mongo.Collection) error {
+	if c == nil {
+		return nil
+	}
+
+	ns := coll.Database().Name() + "." + coll.Name()
+	if _, ok := c.ensured.Load(ns); ok {
+		return nil
+	}
+
+	var err error
+	if settings, ok := c.timeSeriesOf(store); ok {
+		err = ensureTimeSeries(ctx, coll, settings)
+	} else if settings, ok := c.cappedOf(store); ok {
+		err = ensureCapped(ctx, coll, settings)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.ensured.Store(ns, true)
+
+	return nil
+}
+
+// Create the collections of the declared stores that aren't kept per tenant
+func (c *storeCollections) ensureAll(ctx context.Context, k *MongoDBServer) {
+	if c == nil || k.tenancy != TenancyModeNone {
+		return
+	}
+
+	stores := []string{}
+	for store := range c.timeSeries {
+		stores = append(stores, store)
+	}
+	for store := range c.capped {
+		stores = append(stores, store)
+	}
+
+	for _, store := range stores {
+		if err := c.ensure(ctx, store, k.getCollectionHandle(store)); err != nil {
+			logger.Errorf("unable to create store %s: %v", store, err)
+		}
+	}
+}
+
+// Create a collection, returning the specification of the collection when it already exists
+func createCollection(ctx context.Context, coll *mongo.Collection, opts *options.CreateCollectionOptions) (*mongo.CollectionSpecification, error) {
+	var serverErr mongo.ServerError
+
+	err := coll.Database().CreateCollection(ctx, coll.Name(), opts)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrNamespaceExists)) {
+		return nil, err
+	}
+
+	specs, err := coll.Database().ListCollectionSpecifications(ctx, bson.D{{"name", coll.Name()}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(specs) != 1 {
+		return nil, errors.New("the collection exists but wasn't listed")
+	}
+
+	return specs[0], nil
+}
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// Values are compressed with zstd
+	CompressionZstd = "zstd"
+
+	// Field holding the zstd compressed bson content of a compressed value
+	compressedField = "_zstd"
+	// Values smaller than this many bytes of bson are stored uncompressed by default
+	defaultCompressionThreshold = 4096
+)
+
+// The encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// errCompressedValue is returned when a change can't be made to a value because it is stored compressed
+var errCompressedValue = fmt.Errorf("the value is stored compressed")
+
+// valueCompressor compresses the values of stores with compression enabled before they are stored
+type valueCompressor struct {
+	// Values of a store are compressed when their bson is at least its threshold
+	thresholds map[string]int
+
+	originalBytes metric.Int64Counter
+	storedBytes   metric.Int64Counter
+}
+
+func newValueCompressor(thresholds map[string]int) *valueCompressor {
+	meter := otel.Meter("github.com/nitrictech/mongodb-provider")
+
+	// Counters are no-ops unless the runtime registers a meter provider, such as the MetricsReporter
+	originalBytes, _ := meter.Int64Counter("kvstore.compression.original_bytes", metric.WithUnit("By"), metric.WithDescription("Size of values before they were compressed"))
+	storedBytes, _ := meter.Int64Counter("kvstore.compression.stored_bytes", metric.WithUnit("By"), metric.WithDescription("Size of values after they were compressed"))
+
+	return &valueCompressor{
+		thresholds:    thresholds,
+		originalBytes: originalBytes,
+		storedBytes:   storedBytes,
+	}
+}
+
+// The fields a value of a store is stored as, its content or the compressed content when it is large enough
+func (c *valueCompressor) stored(ctx context.Context, store string, content *structpb.Struct) (map[string]interface{}, error) {
+	fields := content.AsMap()
+
+	// No store has compression enabled
+	if c == nil {
+		return fields, nil
+	}
+
+	threshold, ok := c.thresholds[store]
+	if !ok {
+		return fields, nil
+	}
+
+	b, err := bson.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < threshold {
+		return fields, nil
+	}
+
+	compressed := zstdEncoder.EncodeAll(b, nil)
+
+	storeAttr := metric.WithAttributes(attribute.String("store", store))
+	c.originalBytes.Add(ctx, int64(len(b)), storeAttr)
+	c.storedBytes.Add(ctx, int64(len(compressed)), storeAttr)
+
+	return map[string]interface{}{
+		compressedField: primitive.Binary{Data: compressed},
+	}, nil
+}
+
+// The content fields of a compressed value
+func decompressFields(value interface{}) (bson.D, error) {
+	compressed, ok := value.(primitive.Binary)
+	if !ok {
+		return nil, fmt.Errorf("compressed content is %T, not binary", value)
+	}
+
+	b, err := zstdDecoder.DecodeAll(compressed.Data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields bson.D
+	if err := bson.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestValueCompressorStored(t *testing.T) {
+	compressor := newValueCompressor(map[string]int{"documents": 1024})
+
+	small, err := structpb.NewStruct(map[string]interface{}{"title": "short"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := structpb.NewStruct(map[string]interface{}{"title": "long", "body": strings.Repeat("lorem ipsum ", 200)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		compressor *valueCompressor
+		store      string
+		content    *structpb.Struct
+		compressed bool
+	}{
+		{name: "below threshold", compressor: compressor, store: "documents", content: small},
+		{name: "above threshold", compressor: compressor, store: "documents", content: large, compressed: true},
+		{name: "store without compression", compressor: compressor, store: "orders", content: large},
+		{name: "no compressed stores", store: "documents", content: large},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, err := test.compressor.stored(context.Background(), test.store, test.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, compressed := fields[compressedField]
+			if compressed != test.compressed {
+				t.Fatalf("expected compressed to be %v, got fields %v", test.compressed, fields)
+			}
+
+			if compressed && len(fields) != 1 {
+				t.Fatalf("expected only the compressed field, got %d fields", len(fields))
+			}
+
+			// The stored document reads back as the original content
+			doc := bson.M{"_id": "key", "_revision": int64(1)}
+			for key, value := range fields {
+				doc[key] = value
+			}
+
+			raw, err := bson.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := contentFromDocument(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !proto.Equal(content, test.content) {
+				t.Fatalf("expected content %v, got %v", test.content, content)
+			}
+		})
+	}
+}
+
+func TestValueCompressorSavings(t *testing.T) {
+	reporter := newMetricsReporter(time.Hour)
+	otel.SetMeterProvider(reporter)
+
+	compressor := newValueCompressor(map[string]int{"documents": 1024})
+
+	large, err := structpb.NewStruct(map[string]interface{}{"body": strings.Repeat("lorem ipsum ", 200)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := compressor.stored(context.Background(), "documents", large)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, err := bson.Marshal(large.AsMap())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := int64(len(fields[compressedField].(primitive.Binary).Data))
+
+	if total := reporter.Total("kvstore.compression.original_bytes{store=documents}"); total != int64(len(original)) {
+		t.Fatalf("expected %d original bytes, got %d", len(original), total)
+	}
+
+	if total := reporter.Total("kvstore.compression.stored_bytes{store=documents}"); total != stored || stored >= int64(len(original)) {
+		t.Fatalf("expected %d stored bytes, fewer than the original, got %d", stored, total)
+	}
+}
+
+func TestDecompressFieldsInvalid(t *testing.T) {
+	if _, err := decompressFields("not binary"); err == nil {
+		t.Fatal("expected content that isn't binary to be rejected")
+	}
+
+	raw, err := bson.Marshal(bson.M{compressedField: bson.M{"not": "compressed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := contentFromDocument(raw); err == nil {
+		t.Fatal("expected a document with invalid compressed content to be rejected")
+	}
+}
@@ -0,0 +1,465 @@
+package common
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nitrictech/mongodb-provider/common/env"
+	secretspb "github.com/nitrictech/nitric/core/pkg/proto/secrets/v1"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultDatabase    = "nitric"
+	defaultCacheSizeMb = 64
+)
+
+// Config configures a MongoDBServer, it is built from options with NewConfig.
+//
+// The environment is one source of options, see WithEnv, the others set the config directly.
+type Config struct {
+	// Connection string of the stack's cluster, unused when Client is set
+	URI string
+	// Connected client of the stack's cluster, used instead of connecting to URI
+	Client *mongo.Client
+	// Database of the stores that aren't routed to a target, defaults to nitric
+	Database string
+	// Credentials of the stack's cluster when they aren't in URI
+	Auth *options.Credential
+	// TLS configuration of the stack's cluster, the connection string decides when both it and TLSSettings are nil
+	TLS *tls.Config
+	// TLS settings of the stack's cluster, read when it is connected to, can't be combined with TLS
+	TLSSettings *TLSSettings
+	// Reads the certificates of TLS settings given as secrets
+	Secrets secretspb.SecretManagerServer
+	// Connection pool of each cluster
+	Pool PoolSettings
+	// Stable API version of the clients, defaults to version 1, no version is declared when nil
+	ServerAPI *options.ServerAPIOptions
+	// Settings per store name, stores that aren't listed use the zero settings
+	Stores map[string]StoreSettings
+	// Clusters and databases stores can be routed to, by target name
+	Targets map[string]TargetSettings
+	// One of the TenancyMode values
+	Tenancy string
+	// Run key value operations in causally consistent sessions
+	CausalConsistency bool
+	// Report the changes to declared store indexes without making them
+	IndexesDryRun bool
+	// Memory in megabytes of the value cache shared by cached stores, defaults to 64
+	CacheSizeMb int
+	// Name of the service the server serves, used to apply per service store quotas
+	ServiceName string
+}
+
+// PoolSettings configure the connection pool of each cluster, zero values use the driver's defaults
+type PoolSettings struct {
+	MinSize uint64
+	MaxSize uint64
+	// Connections that can be established at once
+	MaxConnecting uint64
+	// Idle connections are closed after this long
+	MaxIdleTime time.Duration
+}
+
+// Option sets part of a Config
+type Option func(*Config) error
+
+// Build a validated Config from the defaults and options, later options override earlier ones
+func NewConfig(opts ...Option) (*Config, error) {
+	config := &Config{
+		Database:    defaultDatabase,
+		ServerAPI:   options.ServerAPI(options.ServerAPIVersion1),
+		Stores:      map[string]StoreSettings{},
+		Targets:     map[string]TargetSettings{},
+		CacheSizeMb: defaultCacheSizeMb,
+	}
+
+	for _, opt := range opts {
+		if err := opt(config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// WithEnv reads the settings the runtime is deployed with from the MONGO_ environment variables
+func WithEnv() Option {
+	return func(c *Config) error {
+		if uri := env.MONGO_CLUSTER_CONNECTION_STRING.String(); uri != "" {
+			c.URI = uri
+		}
+
+		stores, err := storeSettingsFromEnv()
+		if err != nil {
+			return err
+		}
+
+		for name, store := range stores {
+			c.Stores[name] = store
+		}
+
+		targets, err := targetSettingsFromEnv()
+		if err != nil {
+			return err
+		}
+
+		for name, target := range targets {
+			c.Targets[name] = target
+		}
+
+		tlsSettings, err := tlsSettingsFromEnv()
+		if err != nil {
+			return err
+		}
+
+		if tlsSettings != nil {
+			c.TLSSettings = tlsSettings
+		}
+
+		c.Tenancy = env.MONGO_TENANCY_MODE.String()
+		c.ServiceName = env.MONGO_SERVICE_NAME.String()
+
+		if c.CausalConsistency, err = env.MONGO_CAUSAL_CONSISTENCY.Bool(); err != nil {
+			return fmt.Errorf("invalid MONGO_CAUSAL_CONSISTENCY %q", env.MONGO_CAUSAL_CONSISTENCY.String())
+		}
+
+		if c.IndexesDryRun, err = env.MONGO_INDEXES_DRY_RUN.Bool(); err != nil {
+			return fmt.Errorf("invalid MONGO_INDEXES_DRY_RUN %q", env.MONGO_INDEXES_DRY_RUN.String())
+		}
+
+		if c.CacheSizeMb, err = env.MONGO_CACHE_SIZE_MB.Int(); err != nil {
+			return fmt.Errorf("invalid MONGO_CACHE_SIZE_MB %q", env.MONGO_CACHE_SIZE_MB.String())
+		}
+
+		return nil
+	}
+}
+
+// WithURI sets the connection string of the stack's cluster
+func WithURI(uri string) Option {
+	return func(c *Config) error {
+		c.URI = uri
+		return nil
+	}
+}
+
+// WithClient serves the stores from an already connected client, which the server doesn't disconnect
+func WithClient(client *mongo.Client) Option {
+	return func(c *Config) error {
+		c.Client = client
+		return nil
+	}
+}
+
+// WithDatabase sets the database of the stores that aren't routed to a target
+func WithDatabase(database string) Option {
+	return func(c *Config) error {
+		c.Database = database
+		return nil
+	}
+}
+
+// WithAuth sets the credentials of the stack's cluster
+func WithAuth(credential options.Credential) Option {
+	return func(c *Config) error {
+		c.Auth = &credential
+		return nil
+	}
+}
+
+// WithTLS sets the TLS configuration of the stack's cluster
+func WithTLS(config *tls.Config) Option {
+	return func(c *Config) error {
+		c.TLS = config
+		return nil
+	}
+}
+
+// WithTLSSettings sets the CA, client certificate and revocation lists of the stack's cluster
+func WithTLSSettings(settings TLSSettings) Option {
+	return func(c *Config) error {
+		c.TLSSettings = &settings
+		return nil
+	}
+}
+
+// WithSecrets reads the certificates of TLS settings given as secrets from a secret manager
+func WithSecrets(secrets secretspb.SecretManagerServer) Option {
+	return func(c *Config) error {
+		c.Secrets = secrets
+		return nil
+	}
+}
+
+// WithPool sets the connection pool of each cluster
+func WithPool(pool PoolSettings) Option {
+	return func(c *Config) error {
+		c.Pool = pool
+		return nil
+	}
+}
+
+// WithServerAPI sets the Stable API version of the clients, nil to not declare one
+func WithServerAPI(serverAPI *options.ServerAPIOptions) Option {
+	return func(c *Config) error {
+		c.ServerAPI = serverAPI
+		return nil
+	}
+}
+
+// WithStore sets the settings of a store, replacing any it had
+func WithStore(name string, settings StoreSettings) Option {
+	return func(c *Config) error {
+		c.Stores[name] = settings
+		return nil
+	}
+}
+
+// WithTarget sets a cluster and database stores can be routed to
+func WithTarget(name string, settings TargetSettings) Option {
+	return func(c *Config) error {
+		c.Targets[name] = settings
+		return nil
+	}
+}
+
+// WithTenancy sets how the tenant of key value requests is determined, one of the TenancyMode values
+func WithTenancy(mode string) Option {
+	return func(c *Config) error {
+		c.Tenancy = mode
+		return nil
+	}
+}
+
+// WithCausalConsistency runs key value operations in causally consistent sessions
+func WithCausalConsistency(enabled bool) Option {
+	return func(c *Config) error {
+		c.CausalConsistency = enabled
+		return nil
+	}
+}
+
+// WithIndexesDryRun reports the changes to declared store indexes without making them
+func WithIndexesDryRun(dryRun bool) Option {
+	return func(c *Config) error {
+		c.IndexesDryRun = dryRun
+		return nil
+	}
+}
+
+// WithCacheSize sets the memory in megabytes of the value cache shared by cached stores
+func WithCacheSize(sizeMb int) Option {
+	return func(c *Config) error {
+		c.CacheSizeMb = sizeMb
+		return nil
+	}
+}
+
+// WithServiceName sets the name of the service the server serves, used to apply per service store quotas
+func WithServiceName(name string) Option {
+	return func(c *Config) error {
+		c.ServiceName = name
+		return nil
+	}
+}
+
+const (
+	// Mongo doesn't allow these characters in database names
+	invalidDatabaseChars = "/\\. \"$"
+	// Mongo database names must be less than 64 bytes
+	maxDatabaseLength = 63
+)
+
+func validateDatabase(database string) error {
+	if database == "" || len(database) > maxDatabaseLength || strings.ContainsAny(database, invalidDatabaseChars) {
+		return fmt.Errorf("invalid database %q, databases must be 1 to %d characters without any of %q", database, maxDatabaseLength, invalidDatabaseChars)
+	}
+
+	return nil
+}
+
+// Mechanisms that authenticate without a username
+var usernamelessAuthMechanisms = map[string]bool{
+	"MONGODB-X509": true,
+	"MONGODB-AWS":  true,
+}
+
+// Validate every setting of the config
+func (c *Config) Validate() error {
+	if c.Client == nil {
+		if c.URI == "" {
+			return fmt.Errorf("a connection string, such as MONGO_CLUSTER_CONNECTION_STRING, or a client is required")
+		}
+
+		opts := c.clientOptions(c.URI)
+		if c.Auth != nil {
+			opts.SetAuth(*c.Auth)
+		}
+
+		if err := opts.Validate(); err != nil {
+			return fmt.Errorf("invalid connection string: %w", err)
+		}
+	}
+
+	if err := validateDatabase(c.Database); err != nil {
+		return err
+	}
+
+	if c.Auth != nil && c.Auth.Username == "" && !usernamelessAuthMechanisms[strings.ToUpper(c.Auth.AuthMechanism)] {
+		return fmt.Errorf("auth mechanism %q requires a username", c.Auth.AuthMechanism)
+	}
+
+	if c.TLSSettings != nil {
+		if c.TLS != nil {
+			return fmt.Errorf("tls settings can't be combined with a tls config")
+		}
+
+		if err := c.validateTLS(*c.TLSSettings); err != nil {
+			return fmt.Errorf("invalid tls settings: %w", err)
+		}
+	}
+
+	if c.Pool.MaxSize > 0 && c.Pool.MinSize > c.Pool.MaxSize {
+		return fmt.Errorf("the minimum pool size %d exceeds the maximum %d", c.Pool.MinSize, c.Pool.MaxSize)
+	}
+
+	if c.Pool.MaxIdleTime < 0 {
+		return fmt.Errorf("the pool's max idle time must not be negative")
+	}
+
+	if c.Tenancy != TenancyModeNone && c.Tenancy != TenancyModeMetadata && c.Tenancy != TenancyModeKey {
+		return fmt.Errorf("unknown tenancy mode %s", c.Tenancy)
+	}
+
+	if c.Tenancy != TenancyModeNone {
+		if err := ValidateTenantDatabase(c.Database); err != nil {
+			return err
+		}
+	}
+
+	for name, target := range c.Targets {
+		if target.ConnectionString == "" {
+			return fmt.Errorf("target %s has no connection string", name)
+		}
+
+		if err := c.clientOptions(target.ConnectionString).Validate(); err != nil {
+			return fmt.Errorf("target %s has an invalid connection string: %w", name, err)
+		}
+
+		if target.Database != "" {
+			if err := validateDatabase(target.Database); err != nil {
+				return fmt.Errorf("target %s: %w", name, err)
+			}
+
+			if c.Tenancy != TenancyModeNone {
+				if err := ValidateTenantDatabase(target.Database); err != nil {
+					return fmt.Errorf("target %s: %w", name, err)
+				}
+			}
+		}
+
+		if target.TLS != nil {
+			if err := c.validateTLS(*target.TLS); err != nil {
+				return fmt.Errorf("target %s has invalid tls settings: %w", name, err)
+			}
+		}
+	}
+
+	cached := false
+	for name, store := range c.Stores {
+		if err := store.validate(c); err != nil {
+			return fmt.Errorf("store %s: %w", name, err)
+		}
+
+		cached = cached || store.Cache
+	}
+
+	if cached && c.CacheSizeMb <= 0 {
+		return fmt.Errorf("the cache size must be positive, it is %d MB", c.CacheSizeMb)
+	}
+
+	return nil
+}
+
+func (c *Config) validateTLS(settings TLSSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	if settings.usesSecrets() && c.Secrets == nil {
+		return fmt.Errorf("certificates given as secrets require a secret manager")
+	}
+
+	return nil
+}
+
+// The options of a client connecting to a cluster, without its credentials and TLS settings
+func (c *Config) clientOptions(uri string) *options.ClientOptions {
+	opts := options.Client().ApplyURI(uri)
+
+	if c.ServerAPI != nil {
+		opts.SetServerAPIOptions(c.ServerAPI)
+	}
+
+	if c.Pool.MinSize > 0 {
+		opts.SetMinPoolSize(c.Pool.MinSize)
+	}
+
+	if c.Pool.MaxSize > 0 {
+		opts.SetMaxPoolSize(c.Pool.MaxSize)
+	}
+
+	if c.Pool.MaxConnecting > 0 {
+		opts.SetMaxConnecting(c.Pool.MaxConnecting)
+	}
+
+	if c.Pool.MaxIdleTime > 0 {
+		opts.SetMaxConnIdleTime(c.Pool.MaxIdleTime)
+	}
+
+	return opts
+}
+
+// The options of the client connecting to the stack's cluster, reading the certificates of its TLS settings
+func (c *Config) clusterOptions(ctx context.Context) (*options.ClientOptions, error) {
+	opts := c.clientOptions(c.URI)
+
+	if c.Auth != nil {
+		opts.SetAuth(*c.Auth)
+	}
+
+	if c.TLS != nil {
+		opts.SetTLSConfig(c.TLS)
+	}
+
+	if c.TLSSettings != nil {
+		if err := c.TLSSettings.apply(ctx, opts, c.Secrets); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+// The options of the client connecting to a target, reading the certificates of its TLS settings
+func (c *Config) targetOptions(ctx context.Context, target TargetSettings) (*options.ClientOptions, error) {
+	opts := c.clientOptions(target.ConnectionString)
+
+	if target.TLS != nil {
+		if err := target.TLS.apply(ctx, opts, c.Secrets); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+const testURI = "mongodb://localhost:27017"
+
+func TestNewConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// Part of the expected error, the config is valid when empty
+		err string
+	}{
+		{
+			name: "defaults",
+			opts: []Option{WithURI(testURI)},
+		},
+		{
+			name: "no connection string",
+			err:  "a connection string",
+		},
+		{
+			name: "invalid database",
+			opts: []Option{WithURI(testURI), WithDatabase("a.b")},
+			err:  "invalid database",
+		},
+		{
+			name: "database too long for tenancy",
+			opts: []Option{WithURI(testURI), WithDatabase("nitric-production"), WithTenancy(TenancyModeMetadata)},
+			err:  "too long for tenancy",
+		},
+		{
+			name: "target database too long for tenancy",
+			opts: []Option{
+				WithURI(testURI),
+				WithTenancy(TenancyModeKey),
+				WithTarget("analytics", TargetSettings{ConnectionString: testURI, Database: "analytics-warehouse"}),
+			},
+			err: "target analytics: database \"analytics-warehouse\" is too long for tenancy",
+		},
+		{
+			name: "unknown tenancy mode",
+			opts: []Option{WithURI(testURI), WithTenancy("header")},
+			err:  "unknown tenancy mode",
+		},
+		{
+			name: "target without connection string",
+			opts: []Option{WithURI(testURI), WithTarget("analytics", TargetSettings{})},
+			err:  "target analytics has no connection string",
+		},
+		{
+			name: "tls secrets without secret manager",
+			opts: []Option{WithURI(testURI), WithTLSSettings(TLSSettings{CA: "secret:ca"})},
+			err:  "require a secret manager",
+		},
+		{
+			name: "cache without size",
+			opts: []Option{WithURI(testURI), WithCacheSize(0), WithStore("orders", StoreSettings{Cache: true})},
+			err:  "cache size must be positive",
+		},
+		{
+			name: "store routed to unknown target",
+			opts: []Option{WithURI(testURI), WithStore("orders", StoreSettings{Target: "analytics"})},
+			err:  "store orders: routed to unknown target analytics",
+		},
+		{
+			name: "store routed to target",
+			opts: []Option{
+				WithURI(testURI),
+				WithTarget("analytics", TargetSettings{ConnectionString: testURI}),
+				WithStore("orders", StoreSettings{Target: "analytics"}),
+			},
+		},
+		{
+			name: "cached store with tenancy",
+			opts: []Option{WithURI(testURI), WithTenancy(TenancyModeKey), WithStore("orders", StoreSettings{Cache: true})},
+			err:  "can't be cached with tenancy enabled",
+		},
+		{
+			name: "negative quota",
+			opts: []Option{WithURI(testURI), WithStore("orders", StoreSettings{Quota: &QuotaSettings{MaxDocuments: -1}})},
+			err:  "quota is invalid",
+		},
+		{
+			name: "unknown compression",
+			opts: []Option{WithURI(testURI), WithStore("orders", StoreSettings{Compression: "gzip"})},
+			err:  "unknown compression gzip",
+		},
+		{
+			name: "compressed store with indexes",
+			opts: []Option{WithURI(testURI), WithStore("orders", StoreSettings{
+				Compression: CompressionZstd,
+				Indexes:     []IndexSettings{{Keys: []IndexKey{{Field: "status"}}}},
+			})},
+			err: "compressed stores can't have indexes",
+		},
+		{
+			name: "index on reserved field",
+			opts: []Option{WithURI(testURI), WithStore("orders", StoreSettings{
+				Indexes: []IndexSettings{{Keys: []IndexKey{{Field: "_revision"}}}},
+			})},
+			err: "reserved",
+		},
+		{
+			name: "search without fields",
+			opts: []Option{WithURI(testURI), WithStore("tickets", StoreSettings{Search: &SearchSettings{}})},
+			err:  "search is invalid",
+		},
+		{
+			name: "cached time-series store",
+			opts: []Option{WithURI(testURI), WithStore("metrics", StoreSettings{Cache: true, TimeSeries: &TimeSeriesSettings{}})},
+			err:  "time-series stores can't be cached",
+		},
+		{
+			name: "capped time-series store",
+			opts: []Option{WithURI(testURI), WithStore("metrics", StoreSettings{TimeSeries: &TimeSeriesSettings{}, Capped: &CappedSettings{MaxDocuments: 100}})},
+			err:  "capped stores can't be time-series stores",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewConfig(test.opts...)
+
+			if test.err == "" {
+				if err != nil {
+					t.Fatalf("expected a valid config, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
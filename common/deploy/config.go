@@ -2,12 +2,338 @@ package deploy
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
+	mongo_service "github.com/nitrictech/mongodb-provider/common"
+	"github.com/robfig/cron/v3"
 )
 
+type MongoQueueConfig struct {
+	VisibilityTimeout   int `mapstructure:"visibility-timeout" json:"visibility-timeout,omitempty"`
+	MaxDeliveryAttempts int `mapstructure:"max-delivery-attempts" json:"max-delivery-attempts,omitempty"`
+}
+
+type MongoQueuesConfig struct {
+	Enabled bool
+	// Settings per queue name, the "default" entry applies to any queue not listed
+	Config map[string]*MongoQueueConfig
+}
+
+type MongoStorageConfig struct {
+	Enabled bool
+	// Public base url of the http gateway that serves presigned urls
+	PresignUrl string `mapstructure:"presign-url"`
+}
+
+type MongoOutboxConfig struct {
+	Enabled bool
+}
+
+type MongoSnapshotsConfig struct {
+	Enabled bool
+	// The Nitric bucket snapshots are written to
+	Bucket string
+	// Cron expression for when snapshots are taken
+	Schedule string
+	// Archive format, jsonl (default) or bson
+	Format string
+	// The key value stores to snapshot, defaults to all of them
+	Stores []string
+}
+
+type MongoStoreConfig struct {
+	// Serve reads of the store from the in-process cache
+	Cache bool `mapstructure:"cache" json:"cache,omitempty"`
+	// Map the _ids and values of documents written by other clients
+	Interop bool `mapstructure:"interop" json:"interop,omitempty"`
+	// Name of the target the store is kept in, defaults to the stack's cluster
+	Target string `mapstructure:"target" json:"target,omitempty"`
+	// Limits on the use of the store
+	Quota *MongoQuotaConfig `mapstructure:"quota" json:"quota,omitempty"`
+	// Compress large values, zstd or empty
+	Compression string `mapstructure:"compression" json:"compression,omitempty"`
+	// Values with at least this many bytes are compressed, defaults to 4096
+	CompressionThreshold int `mapstructure:"compression-threshold" json:"compression-threshold,omitempty"`
+	// Secondary indexes of the store's values
+	Indexes []*MongoIndexConfig `mapstructure:"indexes" json:"indexes,omitempty"`
+	// Vectors of the store's values by name, indexed for vector search
+	Vectors map[string]*MongoVectorConfig `mapstructure:"vectors" json:"vectors,omitempty"`
+	// Text fields of the store's values indexed for full-text search
+	Search *MongoSearchConfig `mapstructure:"search" json:"search,omitempty"`
+	// GeoJSON fields of the store's values, each is indexed with a 2dsphere index
+	Geo []string `mapstructure:"geo" json:"geo,omitempty"`
+	// Keep the store's measurements in a time-series collection
+	TimeSeries *MongoTimeSeriesConfig `mapstructure:"timeseries" json:"timeseries,omitempty"`
+	// Keep the store's values in a capped collection that drops its oldest values when it is full
+	Capped *MongoCappedConfig `mapstructure:"capped" json:"capped,omitempty"`
+}
+
+type MongoCappedConfig struct {
+	// The size of the store, defaults to 1 GiB
+	MaxBytes int64 `mapstructure:"max-bytes" json:"max-bytes,omitempty"`
+	// The number of values of the store, unlimited when zero
+	MaxDocuments int64 `mapstructure:"max-documents" json:"max-documents,omitempty"`
+}
+
+func (c *MongoCappedConfig) validate() error {
+	if c.MaxBytes < 0 || c.MaxDocuments < 0 {
+		return fmt.Errorf("max-bytes and max-documents must not be negative")
+	}
+
+	if c.MaxBytes == 0 && c.MaxDocuments == 0 {
+		return fmt.Errorf("requires max-bytes or max-documents")
+	}
+
+	return nil
+}
+
+type MongoTimeSeriesConfig struct {
+	// Defaults to time
+	TimeField string `mapstructure:"time-field" json:"time-field,omitempty"`
+	// Defaults to meta
+	MetaField string `mapstructure:"meta-field" json:"meta-field,omitempty"`
+	// seconds, minutes or hours, defaults to seconds
+	Granularity string `mapstructure:"granularity" json:"granularity,omitempty"`
+	// Measurements are removed this many seconds after their time, they are kept when zero
+	ExpireAfterSeconds int64 `mapstructure:"expire-after-seconds" json:"expire-after-seconds,omitempty"`
+}
+
+func (t *MongoTimeSeriesConfig) validate() error {
+	for _, field := range []string{t.TimeField, t.MetaField} {
+		if strings.ContainsAny(field, ".$") || strings.HasPrefix(field, "_") {
+			return fmt.Errorf("field %s must not contain '.' or '$' or start with '_'", field)
+		}
+	}
+
+	if t.TimeField != "" && t.TimeField == t.MetaField {
+		return fmt.Errorf("the time and meta fields must differ")
+	}
+
+	if t.Granularity != "" && t.Granularity != "seconds" && t.Granularity != "minutes" && t.Granularity != "hours" {
+		return fmt.Errorf("granularity must be seconds, minutes or hours")
+	}
+
+	if t.ExpireAfterSeconds < 0 {
+		return fmt.Errorf("expire-after-seconds must not be negative")
+	}
+
+	return nil
+}
+
+type MongoSearchFieldConfig struct {
+	Path     string `mapstructure:"path" json:"path"`
+	Analyzer string `mapstructure:"analyzer" json:"analyzer,omitempty"`
+}
+
+type MongoSearchConfig struct {
+	// Analyzer of the text fields, defaults to lucene.standard
+	Analyzer string                    `mapstructure:"analyzer" json:"analyzer,omitempty"`
+	Fields   []*MongoSearchFieldConfig `mapstructure:"fields" json:"fields"`
+	// String fields queries can count and filter on
+	Facets []string `mapstructure:"facets" json:"facets,omitempty"`
+}
+
+func (s *MongoSearchConfig) validate() error {
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("requires at least one field")
+	}
+
+	paths := []string{}
+	for _, field := range s.Fields {
+		if field == nil {
+			return fmt.Errorf("fields should not be empty")
+		}
+
+		paths = append(paths, field.Path)
+	}
+
+	for _, path := range append(paths, s.Facets...) {
+		if path == "" || strings.HasPrefix(path, "$") || strings.HasPrefix(path, "_") {
+			return fmt.Errorf("field %q must not be empty or start with '$' or '_'", path)
+		}
+	}
+
+	return nil
+}
+
+type MongoVectorConfig struct {
+	Dimensions int `mapstructure:"dimensions" json:"dimensions"`
+	// cosine, euclidean or dotProduct, defaults to cosine
+	Similarity string `mapstructure:"similarity" json:"similarity,omitempty"`
+	// Fields of the values queries can filter on
+	Filters []string `mapstructure:"filters" json:"filters,omitempty"`
+}
+
+func (v *MongoVectorConfig) validate() error {
+	if v.Dimensions < 1 || v.Dimensions > 8192 {
+		return fmt.Errorf("dimensions must be between 1 and 8192")
+	}
+
+	if v.Similarity != "" && v.Similarity != "cosine" && v.Similarity != "euclidean" && v.Similarity != "dotProduct" {
+		return fmt.Errorf("similarity must be cosine, euclidean or dotProduct")
+	}
+
+	for _, filter := range v.Filters {
+		if filter == "" || strings.HasPrefix(filter, "$") || strings.HasPrefix(filter, "_") {
+			return fmt.Errorf("filter field %q must not be empty or start with '$' or '_'", filter)
+		}
+	}
+
+	return nil
+}
+
+type MongoIndexKeyConfig struct {
+	Field      string `mapstructure:"field" json:"field"`
+	Descending bool   `mapstructure:"descending" json:"descending,omitempty"`
+}
+
+type MongoIndexConfig struct {
+	// Defaults to the name mongo generates from the keys
+	Name   string                 `mapstructure:"name" json:"name,omitempty"`
+	Keys   []*MongoIndexKeyConfig `mapstructure:"keys" json:"keys"`
+	Unique bool                   `mapstructure:"unique" json:"unique,omitempty"`
+	Sparse bool                   `mapstructure:"sparse" json:"sparse,omitempty"`
+	// Partial filter expression, only values matching it are indexed
+	Partial   map[string]interface{} `mapstructure:"partial" json:"partial,omitempty"`
+	Collation map[string]interface{} `mapstructure:"collation" json:"collation,omitempty"`
+}
+
+func (i *MongoIndexConfig) validate() error {
+	if len(i.Keys) == 0 {
+		return fmt.Errorf("requires at least one key")
+	}
+
+	for _, key := range i.Keys {
+		if key == nil || key.Field == "" {
+			return fmt.Errorf("keys require a field")
+		}
+
+		if strings.HasPrefix(key.Field, "$") || strings.HasPrefix(key.Field, "_") {
+			return fmt.Errorf("key field %s must not start with '$' or '_'", key.Field)
+		}
+	}
+
+	if i.Collation != nil && i.Collation["locale"] == nil {
+		return fmt.Errorf("collation requires a locale")
+	}
+
+	return nil
+}
+
+type MongoQuotaConfig struct {
+	OpsPerSecond float64 `mapstructure:"ops-per-second" json:"ops-per-second,omitempty"`
+	Burst        float64 `mapstructure:"burst" json:"burst,omitempty"`
+	MaxDocuments int64   `mapstructure:"max-documents" json:"max-documents,omitempty"`
+	MaxBytes     int64   `mapstructure:"max-bytes" json:"max-bytes,omitempty"`
+	// Limits on the share of each calling service, by service name
+	Services map[string]*MongoQuotaConfig `mapstructure:"services" json:"services,omitempty"`
+}
+
+func (q *MongoQuotaConfig) validate() error {
+	if q.OpsPerSecond < 0 || q.Burst < 0 || q.MaxDocuments < 0 || q.MaxBytes < 0 {
+		return fmt.Errorf("must not contain negative values")
+	}
+
+	for name, service := range q.Services {
+		if service == nil {
+			return fmt.Errorf("service %s should not be empty", name)
+		}
+
+		if len(service.Services) > 0 {
+			return fmt.Errorf("service %s can't have service limits", name)
+		}
+
+		if err := service.validate(); err != nil {
+			return fmt.Errorf("service %s %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+type MongoTargetConfig struct {
+	// Connection string of an existing deployment, a dedicated cluster is provisioned when it is empty
+	ConnectionString string `mapstructure:"connection-string"`
+	// Database the target's stores are kept in, defaults to nitric
+	Database string
+	// Instance size of the provisioned cluster, defaults to M10
+	InstanceSize string `mapstructure:"instance-size"`
+	// TLS settings of an existing deployment with a private CA or client certificates
+	TLS *MongoTLSConfig `mapstructure:"tls,omitempty"`
+}
+
+// Certificates, keys and CRLs are PEM given inline, as file:<path> in the service's image or as secret:<name> of the stack's secrets
+type MongoTLSConfig struct {
+	// CA certificates trusted as well as the system's
+	CA string `mapstructure:"ca" json:"ca,omitempty"`
+	// Client certificate presented to the cluster, it may include the key
+	Certificate string `mapstructure:"certificate" json:"certificate,omitempty"`
+	// Key of the client certificate, must be a secret
+	Key string `mapstructure:"key" json:"key,omitempty"`
+	// Revocation lists, certificates of the cluster they revoke are rejected
+	CRL string `mapstructure:"crl" json:"crl,omitempty"`
+	// Don't ask OCSP responders whether the cluster's certificates are revoked
+	DisableOCSPEndpointCheck bool `mapstructure:"disable-ocsp-endpoint-check" json:"disable-ocsp-endpoint-check,omitempty"`
+}
+
+// The settings the runtime is given, as MONGO_TLS_CONFIG or a target's tls
+func (t *MongoTLSConfig) settings() mongo_service.TLSSettings {
+	return mongo_service.TLSSettings{
+		CA:                       t.CA,
+		Certificate:              t.Certificate,
+		Key:                      t.Key,
+		CRL:                      t.CRL,
+		DisableOCSPEndpointCheck: t.DisableOCSPEndpointCheck,
+	}
+}
+
+func (t *MongoTLSConfig) validate() error {
+	// The runtime's rules, so settings it would reject fail the deployment instead
+	if err := t.settings().Validate(); err != nil {
+		return err
+	}
+
+	// Keys would otherwise be kept in the environment of every service
+	if t.Key != "" && !strings.HasPrefix(t.Key, "secret:") {
+		return fmt.Errorf("key must be a secret:<name>")
+	}
+
+	if strings.HasPrefix(t.Certificate, "-----BEGIN") && strings.Contains(t.Certificate, "PRIVATE KEY") {
+		return fmt.Errorf("certificate must not include its key, give the key as a secret")
+	}
+
+	return nil
+}
+
+type MongoCacheConfig struct {
+	// Memory of the cache shared by cached stores, in megabytes
+	MaxSizeMb int `mapstructure:"max-size-mb"`
+}
+
+type MongoTenancyConfig struct {
+	// How the tenant of key value requests is determined, metadata or key
+	Mode string
+}
+
 type MongoDBConfig struct {
-	OrgId string `mapstructure:"orgId"`
+	OrgId     string                `mapstructure:"orgId"`
+	Queues    *MongoQueuesConfig    `mapstructure:"queues,omitempty"`
+	Storage   *MongoStorageConfig   `mapstructure:"storage,omitempty"`
+	Outbox    *MongoOutboxConfig    `mapstructure:"outbox,omitempty"`
+	Snapshots *MongoSnapshotsConfig `mapstructure:"snapshots,omitempty"`
+	// Settings per key value store name
+	Stores map[string]*MongoStoreConfig `mapstructure:"stores,omitempty"`
+	// Clusters and databases stores can be routed to, by name
+	Targets map[string]*MongoTargetConfig `mapstructure:"targets,omitempty"`
+	Cache   *MongoCacheConfig             `mapstructure:"cache,omitempty"`
+	Tenancy *MongoTenancyConfig           `mapstructure:"tenancy,omitempty"`
+	// TLS settings of the stack's cluster, such as a client certificate for X.509 authentication
+	TLS *MongoTLSConfig `mapstructure:"tls,omitempty"`
+	// Report the changes to store indexes instead of making them
+	IndexesDryRun bool `mapstructure:"indexes-dry-run"`
+	// Run key value operations in causally consistent sessions, resumed from the token of each request
+	CausalConsistency bool `mapstructure:"causal-consistency"`
 }
 
 func ConfigFromAttributes(attributes map[string]interface{}) (*MongoDBConfig, error) {
@@ -21,5 +347,220 @@ func ConfigFromAttributes(attributes map[string]interface{}) (*MongoDBConfig, er
 		return nil, fmt.Errorf("invalid configuration: require an organisation id")
 	}
 
+	if config.Queues == nil {
+		config.Queues = &MongoQueuesConfig{}
+	}
+
+	if config.Queues.Config == nil {
+		config.Queues.Config = map[string]*MongoQueueConfig{}
+	}
+
+	if config.Storage == nil {
+		config.Storage = &MongoStorageConfig{}
+	}
+
+	if config.Outbox == nil {
+		config.Outbox = &MongoOutboxConfig{}
+	}
+
+	if config.Snapshots == nil {
+		config.Snapshots = &MongoSnapshotsConfig{}
+	}
+
+	if config.Stores == nil {
+		config.Stores = map[string]*MongoStoreConfig{}
+	}
+
+	if config.Cache == nil {
+		config.Cache = &MongoCacheConfig{}
+	}
+
+	if config.Cache.MaxSizeMb < 0 {
+		return nil, fmt.Errorf("invalid configuration: cache max-size-mb must not be negative")
+	}
+
+	if config.Cache.MaxSizeMb == 0 {
+		config.Cache.MaxSizeMb = 64
+	}
+
+	if config.Tenancy == nil {
+		config.Tenancy = &MongoTenancyConfig{}
+	}
+
+	if config.Tenancy.Mode != "" && config.Tenancy.Mode != "metadata" && config.Tenancy.Mode != "key" {
+		return nil, fmt.Errorf("invalid configuration: tenancy mode must be metadata or key")
+	}
+
+	if config.Tenancy.Mode != "" && config.Snapshots.Enabled {
+		return nil, fmt.Errorf("invalid configuration: snapshots can't be taken with tenancy enabled")
+	}
+
+	if config.Tenancy.Mode != "" && config.Outbox.Enabled {
+		return nil, fmt.Errorf("invalid configuration: the outbox can't be used with tenancy enabled")
+	}
+
+	if config.TLS != nil {
+		if err := config.TLS.validate(); err != nil {
+			return nil, fmt.Errorf("invalid configuration: tls %w", err)
+		}
+	}
+
+	if config.Targets == nil {
+		config.Targets = map[string]*MongoTargetConfig{}
+	}
+
+	for name, targetConfig := range config.Targets {
+		if targetConfig == nil {
+			return nil, fmt.Errorf("invalid configuration: target config %s should not be empty", name)
+		}
+
+		if targetConfig.Database == "" {
+			targetConfig.Database = "nitric"
+		}
+
+		if config.Tenancy.Mode != "" {
+			if err := mongo_service.ValidateTenantDatabase(targetConfig.Database); err != nil {
+				return nil, fmt.Errorf("invalid configuration: target %s %w", name, err)
+			}
+		}
+
+		if targetConfig.ConnectionString == "" && targetConfig.InstanceSize == "" {
+			targetConfig.InstanceSize = "M10"
+		}
+
+		if targetConfig.TLS != nil {
+			// Provisioned clusters are reached with the certificates of Atlas
+			if targetConfig.ConnectionString == "" {
+				return nil, fmt.Errorf("invalid configuration: target %s can only have tls settings with a connection-string", name)
+			}
+
+			if err := targetConfig.TLS.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: target %s tls %w", name, err)
+			}
+		}
+	}
+
+	for name, storeConfig := range config.Stores {
+		if storeConfig == nil {
+			return nil, fmt.Errorf("invalid configuration: store config %s should not be empty", name)
+		}
+
+		if storeConfig.Cache && config.Tenancy.Mode != "" {
+			return nil, fmt.Errorf("invalid configuration: store %s can't be cached with tenancy enabled", name)
+		}
+
+		if storeConfig.Compression != "" && storeConfig.Compression != "zstd" {
+			return nil, fmt.Errorf("invalid configuration: store %s compression must be zstd", name)
+		}
+
+		if storeConfig.CompressionThreshold < 0 {
+			return nil, fmt.Errorf("invalid configuration: store %s compression-threshold must not be negative", name)
+		}
+
+		if storeConfig.Compression != "" && (len(storeConfig.Indexes) > 0 || len(storeConfig.Geo) > 0 || len(storeConfig.Vectors) > 0 || storeConfig.Search != nil) {
+			return nil, fmt.Errorf("invalid configuration: compressed store %s can't have indexes, geo fields, vectors or search", name)
+		}
+
+		names := map[string]bool{}
+		for i, index := range storeConfig.Indexes {
+			if index == nil {
+				return nil, fmt.Errorf("invalid configuration: store %s index %d should not be empty", name, i)
+			}
+
+			if err := index.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: store %s index %d %w", name, i, err)
+			}
+
+			if index.Name != "" && names[index.Name] {
+				return nil, fmt.Errorf("invalid configuration: store %s declares index %s more than once", name, index.Name)
+			}
+			names[index.Name] = true
+		}
+
+		for vector, vectorConfig := range storeConfig.Vectors {
+			if vectorConfig == nil {
+				return nil, fmt.Errorf("invalid configuration: store %s vector %s should not be empty", name, vector)
+			}
+
+			if vector == "" || strings.ContainsAny(vector, ".$") {
+				return nil, fmt.Errorf("invalid configuration: store %s vector name %q must not be empty or contain '.' or '$'", name, vector)
+			}
+
+			if err := vectorConfig.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: store %s vector %s %w", name, vector, err)
+			}
+		}
+
+		for _, field := range storeConfig.Geo {
+			if field == "" || strings.HasPrefix(field, "$") || strings.HasPrefix(field, "_") {
+				return nil, fmt.Errorf("invalid configuration: store %s geo field %q must not be empty or start with '$' or '_'", name, field)
+			}
+		}
+
+		if storeConfig.TimeSeries != nil {
+			if err := storeConfig.TimeSeries.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: store %s timeseries %w", name, err)
+			}
+
+			if storeConfig.Cache || storeConfig.Compression != "" || len(storeConfig.Vectors) > 0 || storeConfig.Search != nil {
+				return nil, fmt.Errorf("invalid configuration: time-series store %s can't be cached, compressed or searched", name)
+			}
+		}
+
+		if storeConfig.Capped != nil {
+			if err := storeConfig.Capped.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: store %s capped %w", name, err)
+			}
+
+			if storeConfig.TimeSeries != nil {
+				return nil, fmt.Errorf("invalid configuration: capped store %s can't be a time-series store", name)
+			}
+		}
+
+		if storeConfig.Search != nil {
+			if err := storeConfig.Search.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: store %s search %w", name, err)
+			}
+		}
+
+		if storeConfig.Quota != nil {
+			if err := storeConfig.Quota.validate(); err != nil {
+				return nil, fmt.Errorf("invalid configuration: store %s quota %w", name, err)
+			}
+		}
+
+		if _, ok := config.Targets[storeConfig.Target]; storeConfig.Target != "" && !ok {
+			return nil, fmt.Errorf("invalid configuration: store %s is routed to unknown target %s", name, storeConfig.Target)
+		}
+	}
+
+	if config.Snapshots.Enabled {
+		if config.Snapshots.Bucket == "" {
+			return nil, fmt.Errorf("invalid configuration: snapshots require a bucket")
+		}
+
+		if _, err := cron.ParseStandard(config.Snapshots.Schedule); err != nil {
+			return nil, fmt.Errorf("invalid configuration: snapshot schedule %q: %w", config.Snapshots.Schedule, err)
+		}
+
+		if config.Snapshots.Format == "" {
+			config.Snapshots.Format = "jsonl"
+		}
+
+		if config.Snapshots.Format != "jsonl" && config.Snapshots.Format != "bson" {
+			return nil, fmt.Errorf("invalid configuration: snapshot format must be jsonl or bson")
+		}
+	}
+
+	for name, queueConfig := range config.Queues.Config {
+		if queueConfig == nil {
+			return nil, fmt.Errorf("invalid configuration: queue config %s should not be empty", name)
+		}
+
+		if queueConfig.VisibilityTimeout < 0 || queueConfig.MaxDeliveryAttempts < 0 {
+			return nil, fmt.Errorf("invalid configuration: queue config %s must not contain negative values", name)
+		}
+	}
+
 	return config, nil
 }
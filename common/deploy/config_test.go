@@ -0,0 +1,108 @@
+package deploy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigFromAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		err        string
+	}{
+		{
+			name:       "org id only",
+			attributes: map[string]interface{}{"orgId": "org"},
+		},
+		{
+			name:       "no org id",
+			attributes: map[string]interface{}{},
+			err:        "require an organisation id",
+		},
+		{
+			name: "outbox with tenancy",
+			attributes: map[string]interface{}{
+				"orgId":   "org",
+				"outbox":  map[string]interface{}{"enabled": true},
+				"tenancy": map[string]interface{}{"mode": "key"},
+			},
+			err: "the outbox can't be used with tenancy enabled",
+		},
+		{
+			name: "compressed store with search",
+			attributes: map[string]interface{}{
+				"orgId": "org",
+				"stores": map[string]interface{}{
+					"tickets": map[string]interface{}{
+						"compression": "zstd",
+						"search":      map[string]interface{}{"fields": []interface{}{map[string]interface{}{"path": "subject"}}},
+					},
+				},
+			},
+			err: "compressed store tickets can't have indexes, geo fields, vectors or search",
+		},
+		{
+			name: "store routed to unknown target",
+			attributes: map[string]interface{}{
+				"orgId":  "org",
+				"stores": map[string]interface{}{"orders": map[string]interface{}{"target": "analytics"}},
+			},
+			err: "routed to unknown target analytics",
+		},
+		{
+			name: "tls key that isn't a secret",
+			attributes: map[string]interface{}{
+				"orgId": "org",
+				"tls":   map[string]interface{}{"certificate": "file:/etc/client.pem", "key": "file:/etc/client.key"},
+			},
+			err: "key must be a secret",
+		},
+		{
+			name: "tls key without certificate",
+			attributes: map[string]interface{}{
+				"orgId": "org",
+				"tls":   map[string]interface{}{"key": "secret:client-key"},
+			},
+			err: "a key requires a certificate",
+		},
+		{
+			name: "target tls with a provisioned cluster",
+			attributes: map[string]interface{}{
+				"orgId": "org",
+				"targets": map[string]interface{}{
+					"analytics": map[string]interface{}{"tls": map[string]interface{}{"ca": "file:/etc/ca.pem"}},
+				},
+			},
+			err: "can only have tls settings with a connection-string",
+		},
+		{
+			name: "target database too long for tenancy",
+			attributes: map[string]interface{}{
+				"orgId":   "org",
+				"tenancy": map[string]interface{}{"mode": "metadata"},
+				"targets": map[string]interface{}{
+					"analytics": map[string]interface{}{"connection-string": "mongodb://localhost:27017", "database": "analytics-warehouse"},
+				},
+			},
+			err: "target analytics database \"analytics-warehouse\" is too long for tenancy",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ConfigFromAttributes(test.attributes)
+
+			if test.err == "" {
+				if err != nil {
+					t.Fatalf("expected a valid config, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
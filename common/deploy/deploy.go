@@ -1,8 +1,11 @@
 package deploy
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 
 	"github.com/nitrictech/nitric/cloud/common/deploy/pulumix"
 	deploymentspb "github.com/nitrictech/nitric/core/pkg/proto/deployments/v1"
@@ -32,7 +35,27 @@ func (p *MongoDBProvider) Pre(ctx *pulumi.Context, resources []*pulumix.NitricPu
 		return ok
 	})
 
-	if len(databases) > 0 {
+	// Queues are only served from the cluster when enabled in the stack config
+	queues := lo.Filter(resources, func(res *pulumix.NitricPulumiResource[any], idx int) bool {
+		_, ok := res.Config.(*deploymentspb.Resource_Queue)
+		return ok && p.MongoDBConfig.Queues.Enabled
+	})
+
+	// Buckets are only served from GridFS when enabled in the stack config
+	buckets := lo.Filter(resources, func(res *pulumix.NitricPulumiResource[any], idx int) bool {
+		_, ok := res.Config.(*deploymentspb.Resource_Bucket)
+		return ok && p.MongoDBConfig.Storage.Enabled
+	})
+
+	// The outbox commits writes and events in the cluster, so it needs one even without key value stores
+	outbox := p.MongoDBConfig.Outbox.Enabled
+
+	// The outbox's transaction only includes key value stores kept in the cluster, which they only are on AWS
+	if outbox && p.Provider != "AWS" {
+		return fmt.Errorf("invalid configuration: the outbox is only supported on AWS")
+	}
+
+	if len(databases) > 0 || len(queues) > 0 || len(buckets) > 0 || outbox {
 		project, err := mongodb.NewProject(ctx, projectName, &mongodb.ProjectArgs{
 			Name:  pulumi.String(projectName),
 			OrgId: pulumi.String(p.MongoDBConfig.OrgId),
@@ -96,6 +119,58 @@ func (p *MongoDBProvider) Pre(ctx *pulumi.Context, resources []*pulumix.NitricPu
 			return uri[14:]
 		}).(pulumi.StringOutput)
 
+		queuesConfig, err := json.Marshal(p.MongoDBConfig.Queues.Config)
+		if err != nil {
+			return err
+		}
+
+		storesConfig, err := json.Marshal(p.MongoDBConfig.Stores)
+		if err != nil {
+			return err
+		}
+
+		if len(databases) > 0 {
+			if err := p.searchIndexes(ctx, project, cluster, databases); err != nil {
+				return err
+			}
+		}
+
+		var targetsConfig pulumi.StringOutput
+		if len(databases) > 0 && len(p.MongoDBConfig.Targets) > 0 {
+			targetsConfig, err = p.targets(ctx, project, user, dbMasterPassword, region)
+			if err != nil {
+				return err
+			}
+		}
+
+		var tlsConfig []byte
+		if p.MongoDBConfig.TLS != nil {
+			tlsConfig, err = json.Marshal(p.MongoDBConfig.TLS.settings())
+			if err != nil {
+				return err
+			}
+		}
+
+		var snapshotsConfig []byte
+		if p.MongoDBConfig.Snapshots.Enabled && len(databases) > 0 {
+			snapshotsConfig, err = p.snapshotSettings(resources, databases)
+			if err != nil {
+				return err
+			}
+		}
+
+		// generate a key for signing presigned storage urls
+		var storageSigningKey *random.RandomPassword
+		if len(buckets) > 0 {
+			storageSigningKey, err = random.NewRandomPassword(ctx, "storage-signing-key", &random.RandomPasswordArgs{
+				Length:  pulumi.Int(32),
+				Special: pulumi.Bool(false),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		// append the mongodb environment variables to all the services
 		for _, res := range resources {
 			config, ok := res.Config.(*pulumix.NitricPulumiServiceConfig)
@@ -104,8 +179,55 @@ func (p *MongoDBProvider) Pre(ctx *pulumi.Context, resources []*pulumix.NitricPu
 				clusterUrl := pulumi.Sprintf("mongodb+srv://%s:%s@%s/?retryWrites=true&w=majority", user.Username, dbMasterPassword.Result, clusterUrl)
 
 				config.SetEnv("MONGO_CLUSTER_CONNECTION_STRING", clusterUrl)
+
+				if tlsConfig != nil {
+					config.SetEnv("MONGO_TLS_CONFIG", pulumi.String(string(tlsConfig)))
+				}
+
 				config.SetEnv("MONGODB_ATLAS_PRIVATE_KEY", nil)
 				config.SetEnv("MONGODB_ATLAS_PUBLIC_KEY", nil)
+
+				if len(databases) > 0 {
+					config.SetEnv("MONGO_SERVICE_NAME", pulumi.String(res.Id.Name))
+					config.SetEnv("MONGO_STORES_CONFIG", pulumi.String(string(storesConfig)))
+					config.SetEnv("MONGO_CACHE_SIZE_MB", pulumi.String(strconv.Itoa(p.MongoDBConfig.Cache.MaxSizeMb)))
+
+					if len(p.MongoDBConfig.Targets) > 0 {
+						config.SetEnv("MONGO_TARGETS_CONFIG", targetsConfig)
+					}
+
+					if p.MongoDBConfig.IndexesDryRun {
+						config.SetEnv("MONGO_INDEXES_DRY_RUN", pulumi.String("true"))
+					}
+
+					if p.MongoDBConfig.CausalConsistency {
+						config.SetEnv("MONGO_CAUSAL_CONSISTENCY", pulumi.String("true"))
+					}
+
+					if p.MongoDBConfig.Tenancy.Mode != "" {
+						config.SetEnv("MONGO_TENANCY_MODE", pulumi.String(p.MongoDBConfig.Tenancy.Mode))
+					}
+				}
+
+				if len(queues) > 0 {
+					config.SetEnv("MONGO_QUEUES_ENABLED", pulumi.String("true"))
+					config.SetEnv("MONGO_QUEUES_CONFIG", pulumi.String(string(queuesConfig)))
+				}
+
+				if len(buckets) > 0 {
+					config.SetEnv("MONGO_STORAGE_ENABLED", pulumi.String("true"))
+					config.SetEnv("MONGO_STORAGE_PRESIGN_URL", pulumi.String(p.MongoDBConfig.Storage.PresignUrl))
+					config.SetEnv("MONGO_STORAGE_SIGNING_KEY", storageSigningKey.Result)
+				}
+
+				if outbox {
+					config.SetEnv("MONGO_OUTBOX_ENABLED", pulumi.String("true"))
+				}
+
+				if snapshotsConfig != nil {
+					config.SetEnv("MONGO_SNAPSHOTS_ENABLED", pulumi.String("true"))
+					config.SetEnv("MONGO_SNAPSHOTS_CONFIG", pulumi.String(string(snapshotsConfig)))
+				}
 			}
 		}
 	}
@@ -113,6 +235,125 @@ func (p *MongoDBProvider) Pre(ctx *pulumi.Context, resources []*pulumix.NitricPu
 	return nil
 }
 
+// Provision the targets without a connection string as dedicated clusters in the project,
+// returning the runtime target settings with every target's connection string
+func (p *MongoDBProvider) targets(ctx *pulumi.Context, project *mongodb.Project, user *mongodb.DatabaseUser, password *random.RandomPassword, region string) (pulumi.StringOutput, error) {
+	names := lo.Keys(p.MongoDBConfig.Targets)
+	sort.Strings(names)
+
+	connectionStrings := []interface{}{}
+	for _, name := range names {
+		target := p.MongoDBConfig.Targets[name]
+
+		if target.ConnectionString != "" {
+			connectionStrings = append(connectionStrings, pulumi.String(target.ConnectionString))
+			continue
+		}
+
+		clusterName := fmt.Sprintf("nitric-%s", name)
+
+		cluster, err := mongodbatlas.NewCluster(ctx, clusterName, &mongodbatlas.ClusterArgs{
+			ProjectId:                project.ID(),
+			Name:                     pulumi.String(clusterName),
+			AutoScalingDiskGbEnabled: pulumi.Bool(true),
+			ProviderRegionName:       pulumi.String(region),
+			MongoDbMajorVersion:      pulumi.String("7.0"),
+			ProviderName:             pulumi.String(p.Provider),
+			ProviderInstanceSizeName: pulumi.String(target.InstanceSize),
+		})
+		if err != nil {
+			return pulumi.StringOutput{}, err
+		}
+
+		// The database user has access to every cluster in the project
+		connectionStrings = append(connectionStrings, pulumi.Sprintf("mongodb+srv://%s:%s@%s/?retryWrites=true&w=majority", user.Username, password.Result, cluster.SrvAddress.ApplyT(func(uri string) string {
+			return uri[14:]
+		}).(pulumi.StringOutput)))
+	}
+
+	return pulumi.All(connectionStrings...).ApplyT(func(args []interface{}) (string, error) {
+		targets := map[string]map[string]interface{}{}
+		for i, name := range names {
+			targets[name] = map[string]interface{}{
+				"connectionString": args[i].(string),
+				"database":         p.MongoDBConfig.Targets[name].Database,
+			}
+
+			if tls := p.MongoDBConfig.Targets[name].TLS; tls != nil {
+				targets[name]["tls"] = tls.settings()
+			}
+		}
+
+		config, err := json.Marshal(targets)
+		return string(config), err
+	}).(pulumi.StringOutput), nil
+}
+
+// The runtime snapshot settings, checking the snapshot bucket and stores are declared by the stack
+func (p *MongoDBProvider) snapshotSettings(resources []*pulumix.NitricPulumiResource[any], databases []*pulumix.NitricPulumiResource[any]) ([]byte, error) {
+	// Snapshots export the stores kept in the cluster, which they only are on AWS
+	if p.Provider != "AWS" {
+		return nil, fmt.Errorf("invalid configuration: snapshots are only supported on AWS")
+	}
+
+	snapshots := p.MongoDBConfig.Snapshots
+
+	bucketDeclared := lo.ContainsBy(resources, func(res *pulumix.NitricPulumiResource[any]) bool {
+		_, ok := res.Config.(*deploymentspb.Resource_Bucket)
+		return ok && res.Id.Name == snapshots.Bucket
+	})
+	if !bucketDeclared {
+		return nil, fmt.Errorf("snapshot bucket %s is not declared by any service", snapshots.Bucket)
+	}
+
+	storeNames := lo.Map(databases, func(res *pulumix.NitricPulumiResource[any], idx int) string {
+		return res.Id.Name
+	})
+
+	// Time-series stores hold measurements rather than values, and restores would replace capped stores with uncapped collections,
+	// so neither can be snapshot
+	isTimeSeries := func(store string) bool {
+		storeConfig, ok := p.MongoDBConfig.Stores[store]
+		return ok && storeConfig.TimeSeries != nil
+	}
+	isCapped := func(store string) bool {
+		storeConfig, ok := p.MongoDBConfig.Stores[store]
+		return ok && storeConfig.Capped != nil
+	}
+
+	stores := snapshots.Stores
+	if len(stores) == 0 {
+		stores = lo.Reject(storeNames, func(store string, idx int) bool {
+			return isTimeSeries(store) || isCapped(store)
+		})
+	}
+
+	for _, store := range stores {
+		if !lo.Contains(storeNames, store) {
+			return nil, fmt.Errorf("snapshot store %s is not declared by any service", store)
+		}
+
+		if isTimeSeries(store) {
+			return nil, fmt.Errorf("snapshot store %s is a time-series store, only key value stores can be snapshot", store)
+		}
+
+		if isCapped(store) {
+			return nil, fmt.Errorf("snapshot store %s is a capped store, restoring it would remove its limits", store)
+		}
+
+		if storeConfig, ok := p.MongoDBConfig.Stores[store]; ok && storeConfig.Target != "" {
+			return nil, fmt.Errorf("snapshot store %s is routed to target %s, only stores in the stack's cluster can be snapshot", store, storeConfig.Target)
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"bucket":   snapshots.Bucket,
+		"schedule": snapshots.Schedule,
+		"format":   snapshots.Format,
+		"stores":   stores,
+	})
+}
+
 func (p *MongoDBProvider) MongoConfig() (auto.ConfigMap, error) {
 	publicKey := os.Getenv("MONGODB_ATLAS_PUBLIC_KEY")
 	if publicKey == "" {
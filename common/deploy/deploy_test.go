@@ -0,0 +1,33 @@
+package deploy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnapshotSettingsProvider(t *testing.T) {
+	config, err := ConfigFromAttributes(map[string]interface{}{
+		"orgId":     "org",
+		"snapshots": map[string]interface{}{"enabled": true, "bucket": "backups", "schedule": "@daily"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, provider := range []string{"GCP", "AZURE"} {
+		p := &MongoDBProvider{MongoDBConfig: config, Provider: provider}
+
+		_, err := p.snapshotSettings(nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "only supported on AWS") {
+			t.Fatalf("expected snapshots on %s to be rejected, got %v", provider, err)
+		}
+	}
+
+	// On AWS the bucket is checked next
+	p := &MongoDBProvider{MongoDBConfig: config, Provider: "AWS"}
+
+	_, err = p.snapshotSettings(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "snapshot bucket backups is not declared") {
+		t.Fatalf("expected the undeclared bucket to be rejected, got %v", err)
+	}
+}
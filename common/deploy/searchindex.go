@@ -0,0 +1,149 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	mongo_service "github.com/nitrictech/mongodb-provider/common"
+	"github.com/nitrictech/nitric/cloud/common/deploy/pulumix"
+	mongodb "github.com/pulumi/pulumi-mongodbatlas/sdk/v2/go/mongodbatlas"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/samber/lo"
+)
+
+// Database of the stores kept in the stack's cluster, the runtime's default
+const clusterDatabase = "nitric"
+
+// SearchIndex is an Atlas Search or Vector Search index.
+//
+// The pinned mongodbatlas sdk predates the search index resource, so it is registered by its type token
+// with the provider plugin the deployment installs.
+type SearchIndex struct {
+	pulumi.CustomResourceState
+
+	IndexId pulumi.StringOutput `pulumi:"indexId"`
+	Status  pulumi.StringOutput `pulumi:"status"`
+}
+
+type searchIndexArgs struct {
+	ProjectId      string `pulumi:"projectId"`
+	ClusterName    string `pulumi:"clusterName"`
+	Database       string `pulumi:"database"`
+	CollectionName string `pulumi:"collectionName"`
+	Name           string `pulumi:"name"`
+	// search or vectorSearch
+	Type            *string `pulumi:"type"`
+	Analyzer        *string `pulumi:"analyzer"`
+	MappingsDynamic *bool   `pulumi:"mappingsDynamic"`
+	// JSON encoded field mappings of a search index
+	MappingsFields *string `pulumi:"mappingsFields"`
+	// JSON encoded fields of a vectorSearch index
+	Fields *string `pulumi:"fields"`
+}
+
+type SearchIndexArgs struct {
+	ProjectId       pulumi.StringInput
+	ClusterName     pulumi.StringInput
+	Database        pulumi.StringInput
+	CollectionName  pulumi.StringInput
+	Name            pulumi.StringInput
+	Type            pulumi.StringPtrInput
+	Analyzer        pulumi.StringPtrInput
+	MappingsDynamic pulumi.BoolPtrInput
+	MappingsFields  pulumi.StringPtrInput
+	Fields          pulumi.StringPtrInput
+}
+
+func (SearchIndexArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*searchIndexArgs)(nil)).Elem()
+}
+
+func NewSearchIndex(ctx *pulumi.Context, name string, args *SearchIndexArgs, opts ...pulumi.ResourceOption) (*SearchIndex, error) {
+	var resource SearchIndex
+	err := ctx.RegisterResource("mongodbatlas:index/searchIndex:SearchIndex", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resource, nil
+}
+
+// The runtime settings of a store, so its search indexes are declared exactly as the runtime declares them
+func storeSettings(storeConfig *MongoStoreConfig) (mongo_service.StoreSettings, error) {
+	settings := mongo_service.StoreSettings{}
+
+	body, err := json.Marshal(storeConfig)
+	if err != nil {
+		return settings, err
+	}
+
+	err = json.Unmarshal(body, &settings)
+
+	return settings, err
+}
+
+// Provision the search indexes of the stores kept in the stack's cluster.
+//
+// Stores routed to a target or kept per tenant are indexed by the runtime when it starts, it leaves the indexes
+// provisioned here unchanged as their definitions match.
+func (p *MongoDBProvider) searchIndexes(ctx *pulumi.Context, project *mongodb.Project, cluster *mongodb.Cluster, databases []*pulumix.NitricPulumiResource[any]) error {
+	if p.MongoDBConfig.Tenancy.Mode != "" {
+		return nil
+	}
+
+	names := lo.Map(databases, func(res *pulumix.NitricPulumiResource[any], idx int) string {
+		return res.Id.Name
+	})
+	sort.Strings(names)
+
+	for _, name := range names {
+		storeConfig, ok := p.MongoDBConfig.Stores[name]
+		if !ok || storeConfig.Target != "" {
+			continue
+		}
+
+		settings, err := storeSettings(storeConfig)
+		if err != nil {
+			return err
+		}
+
+		indexes, err := settings.AtlasSearchIndexes()
+		if err != nil {
+			return fmt.Errorf("unable to declare the search indexes of store %s: %w", name, err)
+		}
+
+		for _, index := range indexes {
+			args := &SearchIndexArgs{
+				ProjectId:      project.ID(),
+				ClusterName:    cluster.Name,
+				Database:       pulumi.String(clusterDatabase),
+				CollectionName: pulumi.String(name),
+				Name:           pulumi.String(index.Name),
+				Type:           pulumi.StringPtr(index.Type),
+			}
+
+			if index.Fields != "" {
+				args.Fields = pulumi.StringPtr(index.Fields)
+			}
+
+			// Only the declared fields of search indexes are mapped
+			if index.MappingsFields != "" {
+				args.MappingsDynamic = pulumi.BoolPtr(false)
+				args.MappingsFields = pulumi.StringPtr(index.MappingsFields)
+			}
+
+			if index.Analyzer != "" {
+				args.Analyzer = pulumi.StringPtr(index.Analyzer)
+			}
+
+			_, err := NewSearchIndex(ctx, fmt.Sprintf("%s-%s", name, index.Name), args)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
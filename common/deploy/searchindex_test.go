@@ -0,0 +1,56 @@
+package deploy
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStoreSearchIndexes(t *testing.T) {
+	settings, err := storeSettings(&MongoStoreConfig{
+		Vectors: map[string]*MongoVectorConfig{"embedding": {Dimensions: 3, Filters: []string{"kind"}}},
+		Search: &MongoSearchConfig{
+			Analyzer: "lucene.english",
+			Fields:   []*MongoSearchFieldConfig{{Path: "customer.name"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexes, err := settings.AtlasSearchIndexes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(indexes) != 2 {
+		t.Fatalf("expected a vector and a search index, got %d indexes", len(indexes))
+	}
+
+	vector, search := indexes[0], indexes[1]
+
+	if vector.Name != "vector-embedding" || vector.Type != "vectorSearch" || vector.MappingsFields != "" {
+		t.Fatalf("unexpected vector index %+v", vector)
+	}
+
+	var fields []map[string]interface{}
+	if err := json.Unmarshal([]byte(vector.Fields), &fields); err != nil {
+		t.Fatalf("vector fields aren't json: %v", err)
+	}
+
+	if len(fields) != 2 || fields[0]["path"] != "_vectors.embedding" || fields[0]["numDimensions"] != float64(3) || fields[1]["path"] != "kind" {
+		t.Fatalf("unexpected vector fields %s", vector.Fields)
+	}
+
+	if search.Name != "search" || search.Type != "search" || search.Analyzer != "lucene.english" || search.Fields != "" {
+		t.Fatalf("unexpected search index %+v", search)
+	}
+
+	var mappings map[string]interface{}
+	if err := json.Unmarshal([]byte(search.MappingsFields), &mappings); err != nil {
+		t.Fatalf("search mappings aren't json: %v", err)
+	}
+
+	if _, ok := mappings["customer"]; !ok {
+		t.Fatalf("expected the customer field to be mapped, got %s", search.MappingsFields)
+	}
+}
@@ -0,0 +1,414 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+)
+
+// MongoDocumentsServer queries the documents stored by MongoDBServer by their content.
+//
+// Queries are built from a fixed set of conditions with literal values, so callers can't run arbitrary operators.
+// Compressed values can't be matched by their content, so they are never returned.
+type MongoDocumentsServer struct {
+	kv *MongoDBServer
+}
+
+var _ documentspb.DocumentsServer = &MongoDocumentsServer{}
+
+// queryCursor is the position after the last value of a page
+type queryCursor struct {
+	// Identifies the store, filters and sort the cursor was created for
+	Query string `bson:"q"`
+	// The sort field values of the last value, followed by its key
+	Values bson.A `bson:"v"`
+}
+
+// queryResult is the shape documents are read in, so the sort values are kept apart from the content
+type queryResult struct {
+	Id      bson.RawValue `bson:"_id"`
+	Sort    bson.A        `bson:"sort"`
+	Content bson.Raw      `bson:"content"`
+}
+
+// Identify the parts of a query a cursor depends on
+func queryFingerprint(req *documentspb.DocumentsQueryRequest) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(&documentspb.DocumentsQueryRequest{
+		Store:   req.Store,
+		Filters: req.Filters,
+		Sort:    req.Sort,
+		Tenant:  req.Tenant,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func encodeQueryCursor(cursor queryCursor) (string, error) {
+	b, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeQueryCursor(encoded string) (queryCursor, error) {
+	cursor := queryCursor{}
+
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+
+	if err := bson.Unmarshal(b, &cursor); err != nil {
+		return cursor, err
+	}
+
+	return cursor, nil
+}
+
+// The mongo condition of a filter
+func filterCondition(filter *documentspb.Filter) (bson.D, error) {
+	if err := validateFieldPath(filter.Path); err != nil {
+		return nil, err
+	}
+
+	var condition bson.D
+
+	switch c := filter.Condition.(type) {
+	case *documentspb.Filter_Equals:
+		if c.Equals == nil {
+			return nil, fmt.Errorf("filter on %s requires a value", filter.Path)
+		}
+
+		condition = bson.D{{"$eq", c.Equals.AsInterface()}}
+	case *documentspb.Filter_Range:
+		if c.Range.GetGt() != nil {
+			condition = append(condition, bson.E{"$gt", c.Range.Gt.AsInterface()})
+		}
+		if c.Range.GetGte() != nil {
+			condition = append(condition, bson.E{"$gte", c.Range.Gte.AsInterface()})
+		}
+		if c.Range.GetLt() != nil {
+			condition = append(condition, bson.E{"$lt", c.Range.Lt.AsInterface()})
+		}
+		if c.Range.GetLte() != nil {
+			condition = append(condition, bson.E{"$lte", c.Range.Lte.AsInterface()})
+		}
+
+		if len(condition) == 0 {
+			return nil, fmt.Errorf("range filter on %s requires a bound", filter.Path)
+		}
+	case *documentspb.Filter_In:
+		values := bson.A{}
+		for _, value := range c.In.GetValues() {
+			values = append(values, value.AsInterface())
+		}
+
+		condition = bson.D{{"$in", values}}
+	case *documentspb.Filter_Exists:
+		condition = bson.D{{"$exists", c.Exists}}
+	default:
+		return nil, fmt.Errorf("filter on %s requires a condition", filter.Path)
+	}
+
+	return bson.D{{filter.Path, condition}}, nil
+}
+
+// Condition matching the values after a cursor in the sort order.
+//
+// Values are compared with aggregation expressions, which order values of different types the same way as sorting.
+func cursorCondition(sort bson.D, cursor queryCursor) (bson.D, error) {
+	if len(cursor.Values) != len(sort) {
+		return nil, fmt.Errorf("the cursor doesn't match the sort")
+	}
+
+	after := bson.A{}
+	for i, field := range sort {
+		conditions := bson.A{}
+
+		// Sorting treats missing fields as null
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, bson.D{{"$eq", bson.A{
+				bson.D{{"$ifNull", bson.A{"$" + sort[j].Key, nil}}},
+				bson.D{{"$literal", cursor.Values[j]}},
+			}}})
+		}
+
+		operator := "$gt"
+		if field.Value == -1 {
+			operator = "$lt"
+		}
+
+		conditions = append(conditions, bson.D{{operator, bson.A{
+			bson.D{{"$ifNull", bson.A{"$" + field.Key, nil}}},
+			bson.D{{"$literal", cursor.Values[i]}},
+		}}})
+
+		after = append(after, bson.D{{"$and", conditions}})
+	}
+
+	return bson.D{{"$expr", bson.D{{"$or", after}}}}, nil
+}
+
+// Check projected fields don't overlap, mongo rejects projections of a field and one inside it
+func validateProjection(paths []string) error {
+	for i, path := range paths {
+		if err := validateFieldPath(path); err != nil {
+			return err
+		}
+
+		for _, other := range paths[i+1:] {
+			if path == other || strings.HasPrefix(other, path+".") || strings.HasPrefix(path, other+".") {
+				return fmt.Errorf("projected fields %s and %s overlap", path, other)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Find the values of a store that match every filter
+func (d *MongoDocumentsServer) Query(ctx context.Context, req *documentspb.DocumentsQueryRequest) (*documentspb.DocumentsQueryResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoDocumentsServer.Query")
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultQueryLimit
+	}
+
+	if limit < 0 || limit > maxQueryLimit || req.Offset < 0 {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("limit must be between 1 and %d and offset must not be negative", maxQueryLimit),
+			fmt.Errorf("limit %d, offset %d", req.Limit, req.Offset),
+		)
+	}
+
+	// The cursor is already the position after the previous page, an offset would skip values past it
+	if req.Cursor != "" && req.Offset != 0 {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"offset can't be combined with a cursor",
+			fmt.Errorf("offset %d", req.Offset),
+		)
+	}
+
+	if err := d.kv.timeSeriesErr(newErr, req.Store); err != nil {
+		return nil, err
+	}
+
+	// Compressed values are stored as binary, so their content can't be matched
+	conditions := bson.A{bson.D{{compressedField, bson.D{{"$exists", false}}}}}
+	for _, filter := range req.Filters {
+		condition, err := filterCondition(filter)
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid filter",
+				err,
+			)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	sort := bson.D{}
+	for _, field := range req.Sort {
+		if err := validateFieldPath(field.Path); err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid sort",
+				err,
+			)
+		}
+
+		direction := 1
+		if field.Descending {
+			direction = -1
+		}
+
+		sort = append(sort, bson.E{field.Path, direction})
+	}
+	// Values that sort equally are ordered by key, so every value has a distinct position for cursors
+	sort = append(sort, bson.E{"_id", 1})
+
+	if err := validateProjection(req.Projection); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid projection",
+			err,
+		)
+	}
+
+	fingerprint, err := queryFingerprint(req)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to identify query",
+			err,
+		)
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeQueryCursor(req.Cursor)
+		if err == nil && cursor.Query != fingerprint {
+			err = fmt.Errorf("the cursor was created for a different query")
+		}
+
+		var condition bson.D
+		if err == nil {
+			condition, err = cursorCondition(sort, cursor)
+		}
+
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid cursor",
+				err,
+			)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	sortValues := bson.A{}
+	for _, field := range sort {
+		sortValues = append(sortValues, bson.D{{"$ifNull", bson.A{"$" + field.Key, nil}}})
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{"$match", bson.D{{"$and", conditions}}}},
+		bson.D{{"$sort", sort}},
+		bson.D{{"$skip", req.Offset}},
+		// One more than the limit is read to tell whether there is another page
+		bson.D{{"$limit", limit + 1}},
+		bson.D{{"$replaceWith", bson.D{
+			{"_id", "$_id"},
+			{"sort", sortValues},
+			{"content", "$$ROOT"},
+		}}},
+	}
+
+	if len(req.Projection) > 0 {
+		projection := bson.D{{"_id", 1}, {"sort", 1}}
+		for _, path := range req.Projection {
+			projection = append(projection, bson.E{"content." + path, 1})
+		}
+
+		pipeline = append(pipeline, bson.D{{"$project", projection}})
+	}
+
+	// The tenant is given as a key prefix would be in key tenancy mode
+	tenantPrefix := ""
+	if d.kv.tenancy == TenancyModeKey {
+		tenantPrefix = req.Tenant + tenantKeySeparator
+	}
+
+	coll, _, err := d.kv.scopedCollection(ctx, req.Store, tenantPrefix)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := d.kv.quotas.allow(ctx, req.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to query store %s", req.Store),
+			err,
+		)
+	}
+
+	var results []queryResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to query store %s", req.Store),
+			err,
+		)
+	}
+
+	res := &documentspb.DocumentsQueryResponse{
+		Documents: []*documentspb.Document{},
+	}
+
+	for i, result := range results {
+		if i == limit {
+			next, err := encodeQueryCursor(queryCursor{
+				Query:  fingerprint,
+				Values: results[i-1].Sort,
+			})
+			if err != nil {
+				return nil, newErr(
+					codes.Internal,
+					"unable to create cursor",
+					err,
+				)
+			}
+
+			res.NextCursor = next
+			break
+		}
+
+		// Documents that can't be reached with a key aren't values of the store
+		key, ok := d.kv.storeKey(req.Store, result.Id)
+		if !ok {
+			continue
+		}
+
+		// Values without any of the projected fields have no content
+		content := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		if result.Content != nil {
+			content, err = d.kv.storeContent(req.Store, result.Content)
+		}
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"unable to convert value to pb struct",
+				err,
+			)
+		}
+
+		res.Documents = append(res.Documents, &documentspb.Document{
+			Key:     tenantPrefix + key,
+			Content: content,
+		})
+	}
+
+	return res, nil
+}
+
+func NewDocuments(kv *MongoDBServer) *MongoDocumentsServer {
+	return &MongoDocumentsServer{
+		kv: kv,
+	}
+}
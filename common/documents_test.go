@@ -0,0 +1,108 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestDocumentsQueryInvalid(t *testing.T) {
+	documents := NewDocuments(&MongoDBServer{
+		collections: &storeCollections{timeSeries: map[string]TimeSeriesSettings{"metrics": {}}},
+	})
+
+	tests := []struct {
+		name string
+		req  *documentspb.DocumentsQueryRequest
+		code codes.Code
+	}{
+		{
+			name: "negative offset",
+			req:  &documentspb.DocumentsQueryRequest{Store: "orders", Offset: -1},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "limit above maximum",
+			req:  &documentspb.DocumentsQueryRequest{Store: "orders", Limit: maxQueryLimit + 1},
+			code: codes.InvalidArgument,
+		},
+		{
+			// The cursor already skips the previous pages
+			name: "offset with cursor",
+			req:  &documentspb.DocumentsQueryRequest{Store: "orders", Offset: 10, Cursor: "cursor"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "time-series store",
+			req:  &documentspb.DocumentsQueryRequest{Store: "metrics"},
+			code: codes.FailedPrecondition,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := documents.Query(context.Background(), test.req)
+			if status.Code(err) != test.code {
+				t.Fatalf("expected %s, got %v", test.code, err)
+			}
+		})
+	}
+}
+
+func TestDocumentsQueryPages(t *testing.T) {
+	ctx := context.Background()
+	server := testServer(t)
+	documents := NewDocuments(server)
+
+	for i := 0; i < 5; i++ {
+		content, err := structpb.NewStruct(map[string]interface{}{"total": float64(i % 2)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = server.SetValue(ctx, &kvstorepb.KvStoreSetValueRequest{Ref: &kvstorepb.ValueRef{Store: "orders", Key: fmt.Sprint(i)}, Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sort := []*documentspb.Sort{{Path: "total", Descending: true}}
+
+	// Cursors continue after the last value of each page, so every value is returned once
+	keys := []string{}
+	cursor := ""
+	for {
+		res, err := documents.Query(ctx, &documentspb.DocumentsQueryRequest{Store: "orders", Sort: sort, Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, doc := range res.Documents {
+			keys = append(keys, doc.Key)
+		}
+
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+
+	if fmt.Sprint(keys) != "[1 3 0 2 4]" {
+		t.Fatalf("expected the values by total and then key, got %v", keys)
+	}
+
+	res, err := documents.Query(ctx, &documentspb.DocumentsQueryRequest{Store: "orders", Sort: sort, Limit: 2, Offset: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Documents) != 2 || res.Documents[0].Key != "2" || res.Documents[1].Key != "4" {
+		t.Fatalf("expected the values after the first 3, got %v", res.Documents)
+	}
+}
@@ -0,0 +1,60 @@
+package env
+
+import "github.com/nitrictech/nitric/core/pkg/env"
+
+// MONGO_CLUSTER_CONNECTION_STRING - The connection string for the Atlas cluster, injected at deploy time
+var MONGO_CLUSTER_CONNECTION_STRING = env.GetEnv("MONGO_CLUSTER_CONNECTION_STRING", "")
+
+// MONGO_QUEUES_ENABLED - Serve Nitric queues from the Atlas cluster instead of the cloud queue service
+var MONGO_QUEUES_ENABLED = env.GetEnv("MONGO_QUEUES_ENABLED", "false")
+
+// MONGO_QUEUES_CONFIG - JSON encoded map of queue name to queue settings, the "default" entry applies to unlisted queues
+var MONGO_QUEUES_CONFIG = env.GetEnv("MONGO_QUEUES_CONFIG", "{}")
+
+// MONGO_STORAGE_ENABLED - Serve Nitric buckets from GridFS instead of the cloud object store
+var MONGO_STORAGE_ENABLED = env.GetEnv("MONGO_STORAGE_ENABLED", "false")
+
+// MONGO_STORAGE_PRESIGN_URL - Public base url of the gateway that serves presigned storage urls
+var MONGO_STORAGE_PRESIGN_URL = env.GetEnv("MONGO_STORAGE_PRESIGN_URL", "")
+
+// MONGO_STORAGE_SIGNING_KEY - Key used to sign presigned storage url tokens
+var MONGO_STORAGE_SIGNING_KEY = env.GetEnv("MONGO_STORAGE_SIGNING_KEY", "")
+
+// MONGO_OUTBOX_ENABLED - Serve the transactional outbox extension and relay its events to Nitric topics
+var MONGO_OUTBOX_ENABLED = env.GetEnv("MONGO_OUTBOX_ENABLED", "false")
+
+// MONGO_SNAPSHOTS_ENABLED - Take scheduled snapshots of key value stores
+var MONGO_SNAPSHOTS_ENABLED = env.GetEnv("MONGO_SNAPSHOTS_ENABLED", "false")
+
+// MONGO_SNAPSHOTS_CONFIG - JSON encoded snapshot settings, the bucket, schedule, format and stores
+var MONGO_SNAPSHOTS_CONFIG = env.GetEnv("MONGO_SNAPSHOTS_CONFIG", "{}")
+
+// MONGO_STORES_CONFIG - JSON encoded map of key value store name to store settings
+var MONGO_STORES_CONFIG = env.GetEnv("MONGO_STORES_CONFIG", "{}")
+
+// MONGO_CACHE_SIZE_MB - Memory in megabytes of the value cache shared by cached stores
+var MONGO_CACHE_SIZE_MB = env.GetEnv("MONGO_CACHE_SIZE_MB", "64")
+
+// MONGO_TARGETS_CONFIG - JSON encoded map of target name to the connection string and database stores can be routed to
+var MONGO_TARGETS_CONFIG = env.GetEnv("MONGO_TARGETS_CONFIG", "{}")
+
+// MONGO_TENANCY_MODE - How the tenant of key value requests is determined, metadata or key, tenancy is disabled when empty
+var MONGO_TENANCY_MODE = env.GetEnv("MONGO_TENANCY_MODE", "")
+
+// MONGO_ADMIN_ADDRESS - Address the tenant administration service listens on, apart from the membrane's service address
+var MONGO_ADMIN_ADDRESS = env.GetEnv("MONGO_ADMIN_ADDRESS", "localhost:50052")
+
+// MONGO_SERVICE_NAME - Name of the Nitric service the runtime serves, used to apply per service store quotas
+var MONGO_SERVICE_NAME = env.GetEnv("MONGO_SERVICE_NAME", "")
+
+// MONGO_INDEXES_DRY_RUN - Report the changes to declared store indexes without making them
+var MONGO_INDEXES_DRY_RUN = env.GetEnv("MONGO_INDEXES_DRY_RUN", "false")
+
+// MONGO_CAUSAL_CONSISTENCY - Run key value operations in causally consistent sessions, resumed from the x-nitric-causal-token metadata of each request
+var MONGO_CAUSAL_CONSISTENCY = env.GetEnv("MONGO_CAUSAL_CONSISTENCY", "false")
+
+// MONGO_TLS_CONFIG - JSON encoded TLS settings of the cluster, the CA, client certificate and key and revocation lists
+var MONGO_TLS_CONFIG = env.GetEnv("MONGO_TLS_CONFIG", "")
+
+// MONGO_METRICS_INTERVAL - How often the runtime logs its cache and compression counters, disabled when 0
+var MONGO_METRICS_INTERVAL = env.GetEnv("MONGO_METRICS_INTERVAL", "1m")
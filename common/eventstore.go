@@ -0,0 +1,444 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	eventstorepb "github.com/nitrictech/mongodb-provider/common/proto/eventstore/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errWrongExpectedVersion = errors.New("wrong expected version")
+
+type eventDocument struct {
+	// The global position doubles as the id so events are stored in global order
+	Position  int64     `bson:"_id"`
+	Stream    string    `bson:"stream"`
+	Version   int64     `bson:"version"`
+	Type      string    `bson:"type"`
+	Data      []byte    `bson:"data"`
+	Metadata  []byte    `bson:"metadata"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// MongoEventStoreServer stores append-only event streams in the "eventstore.events" collection.
+//
+// Every event is given a version within its stream and a position across all streams.
+// Positions are drawn from a counter in "eventstore.positions" inside the append transaction,
+// so concurrent appends are serialized and positions follow commit order without gaps.
+type MongoEventStoreServer struct {
+	db *mongo.Database
+
+	indexed atomic.Bool
+}
+
+var _ eventstorepb.EventStoreServer = &MongoEventStoreServer{}
+
+func (e *MongoEventStoreServer) getCollectionHandle() *mongo.Collection {
+	return e.db.Collection("eventstore.events")
+}
+
+func (e *MongoEventStoreServer) getPositionsCollectionHandle() *mongo.Collection {
+	return e.db.Collection("eventstore.positions")
+}
+
+func (e *MongoEventStoreServer) ensureIndexes(ctx context.Context) error {
+	if e.indexed.Load() {
+		return nil
+	}
+
+	// A stream can't have two events with the same version, which guards against concurrent appends
+	_, err := e.getCollectionHandle().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"stream", 1}, {"version", 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	e.indexed.Store(true)
+
+	return nil
+}
+
+func (doc *eventDocument) toEvent() (*eventstorepb.Event, error) {
+	data := &structpb.Struct{}
+	if err := proto.Unmarshal(doc.Data, data); err != nil {
+		return nil, err
+	}
+
+	metadata := &structpb.Struct{}
+	if err := proto.Unmarshal(doc.Metadata, metadata); err != nil {
+		return nil, err
+	}
+
+	return &eventstorepb.Event{
+		Stream:    doc.Stream,
+		Version:   doc.Version,
+		Position:  doc.Position,
+		Type:      doc.Type,
+		Data:      data,
+		Metadata:  metadata,
+		CreatedAt: timestamppb.New(doc.CreatedAt),
+	}, nil
+}
+
+// The version of the latest event in a stream, 0 if the stream doesn't exist
+func (e *MongoEventStoreServer) currentVersion(ctx context.Context, stream string) (int64, error) {
+	var doc eventDocument
+
+	err := e.getCollectionHandle().FindOne(
+		ctx,
+		bson.D{{"stream", stream}},
+		options.FindOne().SetSort(bson.D{{"version", -1}}).SetProjection(bson.D{{"version", 1}}),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	return doc.Version, err
+}
+
+// Reserve count positions, returning the last of them
+func (e *MongoEventStoreServer) reservePositions(ctx context.Context, count int64) (int64, error) {
+	var counter struct {
+		Position int64 `bson:"position"`
+	}
+
+	err := e.getPositionsCollectionHandle().FindOneAndUpdate(
+		ctx,
+		bson.D{{"_id", "all"}},
+		bson.D{{"$inc", bson.D{{"position", count}}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+
+	return counter.Position, err
+}
+
+// Append events to the end of a stream
+func (e *MongoEventStoreServer) Append(ctx context.Context, req *eventstorepb.EventStoreAppendRequest) (*eventstorepb.EventStoreAppendResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoEventStoreServer.Append")
+
+	if req.Stream == "" || len(req.Events) == 0 {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"a stream and at least one event are required",
+			fmt.Errorf("invalid append request"),
+		)
+	}
+
+	if err := e.ensureIndexes(ctx); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to create event store indexes",
+			err,
+		)
+	}
+
+	events := make([]*eventDocument, 0, len(req.Events))
+	for _, event := range req.Events {
+		data, err := proto.Marshal(event.Data)
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"unable to marshal event data",
+				err,
+			)
+		}
+
+		metadata, err := proto.Marshal(event.Metadata)
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"unable to marshal event metadata",
+				err,
+			)
+		}
+
+		events = append(events, &eventDocument{
+			Stream:   req.Stream,
+			Type:     event.Type,
+			Data:     data,
+			Metadata: metadata,
+		})
+	}
+
+	session, err := e.db.Client().StartSession()
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to start session",
+			err,
+		)
+	}
+	defer session.EndSession(ctx)
+
+	res, err := session.WithTransaction(ctx, func(txCtx mongo.SessionContext) (interface{}, error) {
+		version, err := e.currentVersion(txCtx, req.Stream)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.ExpectedVersion != nil && *req.ExpectedVersion != version {
+			return nil, fmt.Errorf("%w: stream is at version %d", errWrongExpectedVersion, version)
+		}
+
+		last, err := e.reservePositions(txCtx, int64(len(events)))
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		docs := make([]interface{}, 0, len(events))
+		for i, event := range events {
+			event.Version = version + int64(i) + 1
+			event.Position = last - int64(len(events)) + int64(i) + 1
+			event.CreatedAt = now
+
+			docs = append(docs, event)
+		}
+
+		if _, err := e.getCollectionHandle().InsertMany(txCtx, docs); err != nil {
+			return nil, err
+		}
+
+		return &eventstorepb.EventStoreAppendResponse{
+			Version:  version + int64(len(events)),
+			Position: last,
+		}, nil
+	})
+	if errors.Is(err, errWrongExpectedVersion) || mongo.IsDuplicateKeyError(err) {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("stream %s is not at the expected version", req.Stream),
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to append to stream %s", req.Stream),
+			err,
+		)
+	}
+
+	return res.(*eventstorepb.EventStoreAppendResponse), nil
+}
+
+// Find events in the given direction starting from a value of the ordering field (inclusive), 0 starts at either end
+func (e *MongoEventStoreServer) find(ctx context.Context, filter bson.D, field string, from int64, direction eventstorepb.Direction, maxCount int64) (*mongo.Cursor, error) {
+	order := 1
+	op := "$gte"
+	if direction == eventstorepb.Direction_Backwards {
+		order = -1
+		op = "$lte"
+	}
+
+	if from > 0 {
+		filter = append(filter, bson.E{field, bson.D{{op, from}}})
+	}
+
+	opts := options.Find().SetSort(bson.D{{field, order}})
+	if maxCount > 0 {
+		opts.SetLimit(maxCount)
+	}
+
+	return e.getCollectionHandle().Find(ctx, filter, opts)
+}
+
+// Send each event of a cursor with the given send function
+func sendEvents(ctx context.Context, cursor *mongo.Cursor, send func(*eventstorepb.Event) error) error {
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc eventDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		event, err := doc.toEvent()
+		if err != nil {
+			return err
+		}
+
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// Read the events of a stream from a version
+func (e *MongoEventStoreServer) ReadStream(req *eventstorepb.EventStoreReadStreamRequest, stream eventstorepb.EventStore_ReadStreamServer) error {
+	newErr := grpc_errors.ErrorsWithScope("MongoEventStoreServer.ReadStream")
+
+	cursor, err := e.find(stream.Context(), bson.D{{"stream", req.Stream}}, "version", req.FromVersion, req.Direction, req.MaxCount)
+	if err != nil {
+		return newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read stream %s", req.Stream),
+			err,
+		)
+	}
+
+	err = sendEvents(stream.Context(), cursor, func(event *eventstorepb.Event) error {
+		return stream.Send(&eventstorepb.EventStoreReadStreamResponse{
+			Event: event,
+		})
+	})
+	if err != nil {
+		return newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read stream %s", req.Stream),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Read the events of all streams in global order from a position
+func (e *MongoEventStoreServer) ReadAll(req *eventstorepb.EventStoreReadAllRequest, stream eventstorepb.EventStore_ReadAllServer) error {
+	newErr := grpc_errors.ErrorsWithScope("MongoEventStoreServer.ReadAll")
+
+	cursor, err := e.find(stream.Context(), bson.D{}, "_id", req.FromPosition, req.Direction, req.MaxCount)
+	if err != nil {
+		return newErr(
+			codes.Internal,
+			"unable to read events",
+			err,
+		)
+	}
+
+	err = sendEvents(stream.Context(), cursor, func(event *eventstorepb.Event) error {
+		return stream.Send(&eventstorepb.EventStoreReadAllResponse{
+			Event: event,
+		})
+	})
+	if err != nil {
+		return newErr(
+			codes.Internal,
+			"unable to read events",
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Receive events as they are appended, starting after a global position
+func (e *MongoEventStoreServer) Subscribe(req *eventstorepb.EventStoreSubscribeRequest, stream eventstorepb.EventStore_SubscribeServer) error {
+	newErr := grpc_errors.ErrorsWithScope("MongoEventStoreServer.Subscribe")
+
+	ctx := stream.Context()
+
+	match := bson.D{{"operationType", "insert"}}
+	filter := bson.D{}
+	if req.Stream != "" {
+		match = append(match, bson.E{"fullDocument.stream", req.Stream})
+		filter = append(filter, bson.E{"stream", req.Stream})
+	}
+
+	// Watch before catching up so no event is missed between the two, events seen in both are skipped by position
+	changes, err := e.getCollectionHandle().Watch(ctx, mongo.Pipeline{bson.D{{"$match", match}}})
+	if err != nil {
+		return newErr(
+			codes.Internal,
+			"unable to watch for events",
+			err,
+		)
+	}
+	defer changes.Close(ctx)
+
+	var last int64
+	send := func(event *eventstorepb.Event) error {
+		if event.Position <= last {
+			return nil
+		}
+		last = event.Position
+
+		return stream.Send(&eventstorepb.EventStoreSubscribeResponse{
+			Event: event,
+		})
+	}
+
+	if req.AfterPosition != nil {
+		cursor, err := e.find(ctx, filter, "_id", *req.AfterPosition+1, eventstorepb.Direction_Forwards, 0)
+		if err != nil {
+			return newErr(
+				codes.Internal,
+				"unable to read events",
+				err,
+			)
+		}
+
+		if err := sendEvents(ctx, cursor, send); err != nil {
+			return newErr(
+				codes.Internal,
+				"unable to read events",
+				err,
+			)
+		}
+	}
+
+	for changes.Next(ctx) {
+		var change struct {
+			FullDocument eventDocument `bson:"fullDocument"`
+		}
+		if err := changes.Decode(&change); err != nil {
+			return newErr(
+				codes.Internal,
+				"unable to decode event",
+				err,
+			)
+		}
+
+		event, err := change.FullDocument.toEvent()
+		if err != nil {
+			return newErr(
+				codes.Internal,
+				"unable to decode event",
+				err,
+			)
+		}
+
+		if err := send(event); err != nil {
+			return newErr(
+				codes.Internal,
+				"failed to send event",
+				err,
+			)
+		}
+	}
+
+	// The change stream ends when the subscriber disconnects
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err := changes.Err(); err != nil {
+		return newErr(
+			codes.Internal,
+			"event subscription failed",
+			err,
+		)
+	}
+
+	return nil
+}
+
+func NewEventStore(db *mongo.Database) *MongoEventStoreServer {
+	return &MongoEventStoreServer{
+		db: db,
+	}
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	eventstorepb "github.com/nitrictech/mongodb-provider/common/proto/eventstore/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestEventStoreAppendValidation(t *testing.T) {
+	// Requests are validated before the cluster is used
+	events := NewEventStore(nil)
+
+	_, err := events.Append(context.Background(), &eventstorepb.EventStoreAppendRequest{Events: []*eventstorepb.EventData{{Type: "placed"}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an append without a stream to be InvalidArgument, got %v", err)
+	}
+
+	_, err = events.Append(context.Background(), &eventstorepb.EventStoreAppendRequest{Stream: "order-1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an append without events to be InvalidArgument, got %v", err)
+	}
+}
+
+func appendEvents(t *testing.T, events *MongoEventStoreServer, stream string, expected *int64, types ...string) (*eventstorepb.EventStoreAppendResponse, error) {
+	t.Helper()
+
+	data := []*eventstorepb.EventData{}
+	for _, eventType := range types {
+		content, err := structpb.NewStruct(map[string]interface{}{"type": eventType})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data = append(data, &eventstorepb.EventData{Type: eventType, Data: content})
+	}
+
+	return events.Append(context.Background(), &eventstorepb.EventStoreAppendRequest{Stream: stream, Events: data, ExpectedVersion: expected})
+}
+
+func TestEventStoreAppendRead(t *testing.T) {
+	events := NewEventStore(testDatabase(t))
+
+	res, err := appendEvents(t, events, "order-1", proto.Int64(0), "placed", "paid")
+	if err != nil {
+		t.Fatalf("unable to append to a new stream: %v", err)
+	}
+
+	if res.Version != 2 || res.Position != 2 {
+		t.Fatalf("expected version 2 at position 2, got %+v", res)
+	}
+
+	if _, err := appendEvents(t, events, "order-2", nil, "placed"); err != nil {
+		t.Fatalf("unable to append without an expected version: %v", err)
+	}
+
+	// Optimistic concurrency rejects appends from a stale version
+	_, err = appendEvents(t, events, "order-1", proto.Int64(1), "shipped")
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected an append at a stale version to be FailedPrecondition, got %v", err)
+	}
+
+	res, err = appendEvents(t, events, "order-1", proto.Int64(2), "shipped")
+	if err != nil {
+		t.Fatalf("unable to append at the current version: %v", err)
+	}
+
+	if res.Version != 3 || res.Position != 4 {
+		t.Fatalf("expected version 3 at position 4, got %+v", res)
+	}
+
+	forwards := &testStream[*eventstorepb.EventStoreReadStreamResponse]{}
+	if err := events.ReadStream(&eventstorepb.EventStoreReadStreamRequest{Stream: "order-1", FromVersion: 2}, forwards); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(forwards.sent) != 2 || forwards.sent[0].Event.Type != "paid" || forwards.sent[1].Event.Type != "shipped" {
+		t.Fatalf("expected paid then shipped from version 2, got %v", forwards.sent)
+	}
+
+	if forwards.sent[0].Event.Data.Fields["type"].GetStringValue() != "paid" {
+		t.Fatalf("unexpected event data %v", forwards.sent[0].Event.Data)
+	}
+
+	backwards := &testStream[*eventstorepb.EventStoreReadStreamResponse]{}
+	err = events.ReadStream(&eventstorepb.EventStoreReadStreamRequest{Stream: "order-1", Direction: eventstorepb.Direction_Backwards, MaxCount: 1}, backwards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backwards.sent) != 1 || backwards.sent[0].Event.Version != 3 {
+		t.Fatalf("expected the last event of the stream, got %v", backwards.sent)
+	}
+
+	all := &testStream[*eventstorepb.EventStoreReadAllResponse]{}
+	if err := events.ReadAll(&eventstorepb.EventStoreReadAllRequest{}, all); err != nil {
+		t.Fatal(err)
+	}
+
+	streams := []string{}
+	for i, res := range all.sent {
+		if res.Event.Position != int64(i+1) {
+			t.Fatalf("expected position %d, got %d", i+1, res.Event.Position)
+		}
+
+		streams = append(streams, res.Event.Stream)
+	}
+
+	if len(streams) != 4 || streams[2] != "order-2" {
+		t.Fatalf("expected the events of both streams in the order they were appended, got %v", streams)
+	}
+}
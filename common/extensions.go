@@ -0,0 +1,31 @@
+package common
+
+import (
+	"github.com/nitrictech/nitric/core/pkg/env"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+
+	eventstorepb "github.com/nitrictech/mongodb-provider/common/proto/eventstore/v1"
+	lockspb "github.com/nitrictech/mongodb-provider/common/proto/locks/v1"
+	snapshotspb "github.com/nitrictech/mongodb-provider/common/proto/snapshots/v1"
+	storagepb "github.com/nitrictech/nitric/core/pkg/proto/storage/v1"
+)
+
+// NewExtensionServer creates the grpc server for the membrane with the extension services registered
+// so they are served alongside the Nitric services. Start the membrane with membrane.WithGrpcServer.
+func NewExtensionServer(db *mongo.Database, storage storagepb.StorageServer) (*grpc.Server, error) {
+	maxWorkers, err := env.MAX_WORKERS.Int()
+	if err != nil {
+		return nil, err
+	}
+
+	// Match the options the membrane uses when it creates its own server
+	s := grpc.NewServer(grpc.MaxConcurrentStreams(uint32(maxWorkers)))
+
+	lockspb.RegisterLocksServer(s, NewLocks(db))
+	eventstorepb.RegisterEventStoreServer(s, NewEventStore(db))
+	// Snapshots are read through the storage plugin, the same way they are written
+	snapshotspb.RegisterSnapshotsServer(s, NewSnapshots(db, storage))
+
+	return s, nil
+}
@@ -0,0 +1,351 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	geopb "github.com/nitrictech/mongodb-provider/common/proto/geo/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	defaultGeoLimit = 100
+	maxGeoLimit     = 1000
+
+	// Radius of the earth in meters that mongo uses for spherical distances
+	earthRadiusMeters = 6378100
+	// Field the distance of near queries is read from, it is removed from the content
+	geoDistanceField = "_geoDistance"
+
+	// Error writing a value whose geo field isn't valid GeoJSON
+	mongoErrCannotExtractGeoKeys = 16755
+)
+
+// The GeoJSON geometry types mongo supports
+var geoJsonTypes = map[string]bool{
+	"Point":              true,
+	"LineString":         true,
+	"Polygon":            true,
+	"MultiPoint":         true,
+	"MultiLineString":    true,
+	"MultiPolygon":       true,
+	"GeometryCollection": true,
+}
+
+// MongoGeoServer finds the values stored by MongoDBServer by the GeoJSON of their declared geo fields.
+//
+// Compressed values are stored as binary, so their fields are never matched.
+type MongoGeoServer struct {
+	kv *MongoDBServer
+}
+
+var _ geopb.GeoServer = &MongoGeoServer{}
+
+// geoResult is the shape matches are read in, so the distance is kept apart from the content
+type geoResult struct {
+	Id       bson.RawValue `bson:"_id"`
+	Distance float64       `bson:"distance"`
+	Content  bson.Raw      `bson:"content"`
+}
+
+func validatePoint(point *geopb.Point) error {
+	if point == nil {
+		return fmt.Errorf("a point is required")
+	}
+
+	if point.Longitude < -180 || point.Longitude > 180 || point.Latitude < -90 || point.Latitude > 90 {
+		return fmt.Errorf("longitude must be between -180 and 180 and latitude between -90 and 90")
+	}
+
+	return nil
+}
+
+// The GeoJSON of a geometry, which must be one of the types
+func geoJson(geometry *structpb.Struct, types ...string) (map[string]interface{}, error) {
+	if geometry == nil {
+		return nil, fmt.Errorf("a geometry is required")
+	}
+
+	fields := geometry.AsMap()
+
+	t, _ := fields["type"].(string)
+	allowed := geoJsonTypes[t]
+	if len(types) > 0 {
+		allowed = false
+		for _, allowedType := range types {
+			allowed = allowed || t == allowedType
+		}
+	}
+
+	if !allowed {
+		return nil, fmt.Errorf("unsupported GeoJSON type %q", t)
+	}
+
+	return fields, nil
+}
+
+// The collection and conditions of a query, the tenant is given as a key prefix would be in key tenancy mode
+func (g *MongoGeoServer) scope(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, query *geopb.GeoQuery) (*mongo.Collection, bson.A, int, string, error) {
+	if query == nil {
+		return nil, nil, 0, "", newErr(
+			codes.InvalidArgument,
+			"a query is required",
+			fmt.Errorf("query not set"),
+		)
+	}
+
+	if !g.kv.geo[query.Store][query.Field] {
+		return nil, nil, 0, "", newErr(
+			codes.FailedPrecondition,
+			"geo field not declared",
+			fmt.Errorf("store %s has no geo field %s", query.Store, query.Field),
+		)
+	}
+
+	limit := int(query.Limit)
+	if limit == 0 {
+		limit = defaultGeoLimit
+	}
+
+	if limit < 0 || limit > maxGeoLimit {
+		return nil, nil, 0, "", newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("limit must be between 1 and %d", maxGeoLimit),
+			fmt.Errorf("limit %d", query.Limit),
+		)
+	}
+
+	conditions := bson.A{}
+	for _, filter := range query.Filters {
+		condition, err := filterCondition(filter)
+		if err != nil {
+			return nil, nil, 0, "", newErr(
+				codes.InvalidArgument,
+				"invalid filter",
+				err,
+			)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	tenantPrefix := ""
+	if g.kv.tenancy == TenancyModeKey {
+		tenantPrefix = query.Tenant + tenantKeySeparator
+	}
+
+	coll, _, err := g.kv.scopedCollection(ctx, query.Store, tenantPrefix)
+	if err != nil {
+		return nil, nil, 0, "", newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := g.kv.quotas.allow(ctx, query.Store); err != nil {
+		return nil, nil, 0, "", quotaErr(newErr, err)
+	}
+
+	return coll, conditions, limit, tenantPrefix, nil
+}
+
+// Read the matches of a query, geometries mongo can't use are the caller's error
+func (g *MongoGeoServer) matches(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, store string, tenantPrefix string, cursor *mongo.Cursor, err error) (*geopb.GeoQueryResponse, error) {
+	var results []geoResult
+	if err == nil {
+		err = cursor.All(ctx, &results)
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrBadValue) {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid geometry",
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to query store %s", store),
+			err,
+		)
+	}
+
+	res := &geopb.GeoQueryResponse{
+		Matches: []*geopb.GeoMatch{},
+	}
+
+	for _, result := range results {
+		content, err := contentFromDocument(result.Content)
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"unable to convert value to pb struct",
+				err,
+			)
+		}
+
+		res.Matches = append(res.Matches, &geopb.GeoMatch{
+			Key:      tenantPrefix + keyString(result.Id),
+			Content:  content,
+			Distance: result.Distance,
+		})
+	}
+
+	return res, nil
+}
+
+// Find values whose geo field matches a condition, ordered by key
+func (g *MongoGeoServer) find(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, query *geopb.GeoQuery, condition bson.D) (*geopb.GeoQueryResponse, error) {
+	coll, conditions, limit, tenantPrefix, err := g.scope(ctx, newErr, query)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions = append(conditions, bson.D{{query.Field, condition}})
+
+	pipeline := mongo.Pipeline{
+		bson.D{{"$match", bson.D{{"$and", conditions}}}},
+		bson.D{{"$sort", bson.D{{"_id", 1}}}},
+		bson.D{{"$limit", limit}},
+		bson.D{{"$replaceWith", bson.D{
+			{"_id", "$_id"},
+			{"content", "$$ROOT"},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+
+	return g.matches(ctx, newErr, query.Store, tenantPrefix, cursor, err)
+}
+
+// Find the values nearest to a point, nearest first
+func (g *MongoGeoServer) Near(ctx context.Context, req *geopb.GeoNearRequest) (*geopb.GeoQueryResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoGeoServer.Near")
+
+	if err := validatePoint(req.Point); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid point",
+			err,
+		)
+	}
+
+	if req.MaxDistance < 0 || req.MinDistance < 0 || (req.MaxDistance > 0 && req.MinDistance > req.MaxDistance) {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"distances must not be negative and min_distance must not exceed max_distance",
+			fmt.Errorf("min_distance %f, max_distance %f", req.MinDistance, req.MaxDistance),
+		)
+	}
+
+	coll, conditions, limit, tenantPrefix, err := g.scope(ctx, newErr, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	near := bson.D{
+		{"near", bson.D{{"type", "Point"}, {"coordinates", bson.A{req.Point.Longitude, req.Point.Latitude}}}},
+		// The field is named so stores with several geo fields use the right index
+		{"key", req.Query.Field},
+		{"distanceField", geoDistanceField},
+		{"spherical", true},
+	}
+	if req.MaxDistance > 0 {
+		near = append(near, bson.E{"maxDistance", req.MaxDistance})
+	}
+	if req.MinDistance > 0 {
+		near = append(near, bson.E{"minDistance", req.MinDistance})
+	}
+	if len(conditions) > 0 {
+		near = append(near, bson.E{"query", bson.D{{"$and", conditions}}})
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{"$geoNear", near}},
+		bson.D{{"$limit", limit}},
+		bson.D{{"$replaceWith", bson.D{
+			{"_id", "$_id"},
+			{"distance", "$" + geoDistanceField},
+			{"content", "$$ROOT"},
+		}}},
+		bson.D{{"$unset", "content." + geoDistanceField}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+
+	return g.matches(ctx, newErr, req.Query.Store, tenantPrefix, cursor, err)
+}
+
+// Find the values within a circle or polygon
+func (g *MongoGeoServer) Within(ctx context.Context, req *geopb.GeoWithinRequest) (*geopb.GeoQueryResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoGeoServer.Within")
+
+	var condition bson.D
+
+	switch shape := req.Shape.(type) {
+	case *geopb.GeoWithinRequest_Circle:
+		err := validatePoint(shape.Circle.GetCenter())
+		if err == nil && shape.Circle.Radius <= 0 {
+			err = fmt.Errorf("the radius must be positive")
+		}
+
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid circle",
+				err,
+			)
+		}
+
+		center := bson.A{shape.Circle.Center.Longitude, shape.Circle.Center.Latitude}
+		condition = bson.D{{"$geoWithin", bson.D{{"$centerSphere", bson.A{center, shape.Circle.Radius / earthRadiusMeters}}}}}
+	case *geopb.GeoWithinRequest_Geometry:
+		geometry, err := geoJson(shape.Geometry, "Polygon", "MultiPolygon")
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid geometry",
+				err,
+			)
+		}
+
+		condition = bson.D{{"$geoWithin", bson.D{{"$geometry", geometry}}}}
+	default:
+		return nil, newErr(
+			codes.InvalidArgument,
+			"a circle or geometry is required",
+			fmt.Errorf("shape not set"),
+		)
+	}
+
+	return g.find(ctx, newErr, req.Query, condition)
+}
+
+// Find the values that intersect a geometry
+func (g *MongoGeoServer) Intersects(ctx context.Context, req *geopb.GeoIntersectsRequest) (*geopb.GeoQueryResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoGeoServer.Intersects")
+
+	geometry, err := geoJson(req.Geometry)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid geometry",
+			err,
+		)
+	}
+
+	return g.find(ctx, newErr, req.Query, bson.D{{"$geoIntersects", bson.D{{"$geometry", geometry}}}})
+}
+
+func NewGeo(kv *MongoDBServer) *MongoGeoServer {
+	return &MongoGeoServer{
+		kv: kv,
+	}
+}
@@ -0,0 +1,230 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	geopb "github.com/nitrictech/mongodb-provider/common/proto/geo/v1"
+	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustStruct(t *testing.T, fields map[string]interface{}) *structpb.Struct {
+	t.Helper()
+
+	s, err := structpb.NewStruct(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestGeoJson(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry map[string]interface{}
+		types    []string
+		valid    bool
+	}{
+		{name: "point", geometry: map[string]interface{}{"type": "Point", "coordinates": []interface{}{0, 0}}, valid: true},
+		{name: "polygon of polygons", geometry: map[string]interface{}{"type": "Polygon"}, types: []string{"Polygon", "MultiPolygon"}, valid: true},
+		{name: "point of polygons", geometry: map[string]interface{}{"type": "Point"}, types: []string{"Polygon", "MultiPolygon"}},
+		{name: "unknown type", geometry: map[string]interface{}{"type": "Circle"}},
+		{name: "no type", geometry: map[string]interface{}{"coordinates": []interface{}{0, 0}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := geoJson(mustStruct(t, test.geometry), test.types...)
+			if test.valid != (err == nil) {
+				t.Fatalf("expected valid to be %v, got %v", test.valid, err)
+			}
+		})
+	}
+
+	if _, err := geoJson(nil); err == nil {
+		t.Fatal("expected a missing geometry to be rejected")
+	}
+}
+
+func TestGeoInvalid(t *testing.T) {
+	ctx := context.Background()
+	geo := NewGeo(&MongoDBServer{geo: map[string]map[string]bool{"places": {"location": true}}})
+	query := &geopb.GeoQuery{Store: "places", Field: "location"}
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "undeclared field",
+			call: func() error {
+				_, err := geo.Near(ctx, &geopb.GeoNearRequest{Query: &geopb.GeoQuery{Store: "places", Field: "area"}, Point: &geopb.Point{}})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "latitude out of range",
+			call: func() error {
+				_, err := geo.Near(ctx, &geopb.GeoNearRequest{Query: query, Point: &geopb.Point{Latitude: 91}})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "min distance beyond max distance",
+			call: func() error {
+				_, err := geo.Near(ctx, &geopb.GeoNearRequest{Query: query, Point: &geopb.Point{}, MinDistance: 10, MaxDistance: 5})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "limit above maximum",
+			call: func() error {
+				_, err := geo.Near(ctx, &geopb.GeoNearRequest{Query: &geopb.GeoQuery{Store: "places", Field: "location", Limit: maxGeoLimit + 1}, Point: &geopb.Point{}})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "circle without radius",
+			call: func() error {
+				_, err := geo.Within(ctx, &geopb.GeoWithinRequest{Query: query, Shape: &geopb.GeoWithinRequest_Circle{Circle: &geopb.Circle{Center: &geopb.Point{}}}})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "within a point",
+			call: func() error {
+				geometry := mustStruct(t, map[string]interface{}{"type": "Point", "coordinates": []interface{}{0, 0}})
+				_, err := geo.Within(ctx, &geopb.GeoWithinRequest{Query: query, Shape: &geopb.GeoWithinRequest_Geometry{Geometry: geometry}})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.call(); status.Code(err) != test.code {
+				t.Fatalf("expected %s, got %v", test.code, err)
+			}
+		})
+	}
+}
+
+func geoKeys(matches []*geopb.GeoMatch) string {
+	keys := []string{}
+	for _, match := range matches {
+		keys = append(keys, match.Key)
+	}
+
+	return fmt.Sprint(keys)
+}
+
+func TestGeoQueries(t *testing.T) {
+	ctx := context.Background()
+	server := testServer(t, WithStore("places", StoreSettings{Geo: []string{"location"}}))
+	server.indexes.reconcileAll(ctx, server)
+	geo := NewGeo(server)
+
+	places := map[string][]interface{}{
+		"dover":  {"town", 1.3134, 51.1279},
+		"london": {"city", -0.1276, 51.5072},
+		"paris":  {"city", 2.3522, 48.8566},
+	}
+
+	for key, place := range places {
+		content := mustStruct(t, map[string]interface{}{
+			"kind":     place[0],
+			"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{place[1], place[2]}},
+		})
+
+		if _, err := server.SetValue(ctx, &kvstorepb.KvStoreSetValueRequest{Ref: &kvstorepb.ValueRef{Store: "places", Key: key}, Content: content}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Values whose geo field isn't GeoJSON can't be indexed
+	invalid := mustStruct(t, map[string]interface{}{"location": map[string]interface{}{"type": "Point"}})
+	_, err := server.SetValue(ctx, &kvstorepb.KvStoreSetValueRequest{Ref: &kvstorepb.ValueRef{Store: "places", Key: "nowhere"}, Content: invalid})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid GeoJSON to be InvalidArgument, got %v", err)
+	}
+
+	query := &geopb.GeoQuery{Store: "places", Field: "location"}
+	london := &geopb.Point{Longitude: -0.1276, Latitude: 51.5072}
+
+	near, err := geo.Near(ctx, &geopb.GeoNearRequest{Query: query, Point: london})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := geoKeys(near.Matches); keys != "[london dover paris]" {
+		t.Fatalf("expected places nearest first, got %s", keys)
+	}
+
+	if near.Matches[1].Distance < 90000 || near.Matches[1].Distance > 120000 {
+		t.Fatalf("expected dover to be about 100km from london, got %fm", near.Matches[1].Distance)
+	}
+
+	if _, ok := near.Matches[0].Content.Fields[geoDistanceField]; ok {
+		t.Fatal("expected the distance to be removed from the content")
+	}
+
+	near, err = geo.Near(ctx, &geopb.GeoNearRequest{Query: query, Point: london, MaxDistance: 150000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := geoKeys(near.Matches); keys != "[london dover]" {
+		t.Fatalf("expected places within 150km, got %s", keys)
+	}
+
+	cities := &geopb.GeoQuery{Store: "places", Field: "location", Filters: []*documentspb.Filter{
+		{Path: "kind", Condition: &documentspb.Filter_Equals{Equals: structpb.NewStringValue("city")}},
+	}}
+
+	near, err = geo.Near(ctx, &geopb.GeoNearRequest{Query: cities, Point: london})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := geoKeys(near.Matches); keys != "[london paris]" {
+		t.Fatalf("expected only cities, got %s", keys)
+	}
+
+	within, err := geo.Within(ctx, &geopb.GeoWithinRequest{Query: query, Shape: &geopb.GeoWithinRequest_Circle{Circle: &geopb.Circle{Center: london, Radius: 150000}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := geoKeys(within.Matches); keys != "[dover london]" {
+		t.Fatalf("expected places within the circle by key, got %s", keys)
+	}
+
+	southEast := mustStruct(t, map[string]interface{}{
+		"type": "Polygon",
+		"coordinates": []interface{}{[]interface{}{
+			[]interface{}{-1, 50.5}, []interface{}{2, 50.5}, []interface{}{2, 52}, []interface{}{-1, 52}, []interface{}{-1, 50.5},
+		}},
+	})
+
+	intersects, err := geo.Intersects(ctx, &geopb.GeoIntersectsRequest{Query: query, Geometry: southEast})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := geoKeys(intersects.Matches); keys != "[dover london]" {
+		t.Fatalf("expected places in the polygon by key, got %s", keys)
+	}
+}
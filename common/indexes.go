@@ -0,0 +1,554 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IndexKey is a field of an index
+type IndexKey struct {
+	// Dot separated path of the field
+	Field      string `json:"field"`
+	Descending bool   `json:"descending,omitempty"`
+	// Index the field's GeoJSON with a 2dsphere index, set for the geo fields of stores
+	Geo bool `json:"-"`
+}
+
+// The value of the key in an index specification
+func (k IndexKey) value() interface{} {
+	if k.Geo {
+		return "2dsphere"
+	}
+
+	if k.Descending {
+		return int32(-1)
+	}
+
+	return int32(1)
+}
+
+// IndexSettings declare a secondary index of a key value store
+type IndexSettings struct {
+	// Defaults to the name mongo generates from the keys, e.g. customer_1_createdAt_-1
+	Name   string     `json:"name,omitempty"`
+	Keys   []IndexKey `json:"keys"`
+	Unique bool       `json:"unique,omitempty"`
+	Sparse bool       `json:"sparse,omitempty"`
+	// Only values matching the filter expression are indexed
+	Partial map[string]interface{} `json:"partial,omitempty"`
+	// Collation of string comparisons, e.g. {"locale": "en", "strength": 2}
+	Collation map[string]interface{} `json:"collation,omitempty"`
+}
+
+func (i IndexSettings) name() string {
+	if i.Name != "" {
+		return i.Name
+	}
+
+	parts := []string{}
+	for _, key := range i.Keys {
+		parts = append(parts, key.Field, fmt.Sprint(key.value()))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+func (i IndexSettings) keys() bson.D {
+	keys := bson.D{}
+	for _, key := range i.Keys {
+		keys = append(keys, bson.E{key.Field, key.value()})
+	}
+
+	return keys
+}
+
+// The createIndexes specification of the index
+func (i IndexSettings) spec() bson.D {
+	spec := bson.D{{"key", i.keys()}, {"name", i.name()}}
+
+	if i.Unique {
+		spec = append(spec, bson.E{"unique", true})
+	}
+	if i.Sparse {
+		spec = append(spec, bson.E{"sparse", true})
+	}
+	if i.Partial != nil {
+		spec = append(spec, bson.E{"partialFilterExpression", i.Partial})
+	}
+	if i.Collation != nil {
+		spec = append(spec, bson.E{"collation", i.Collation})
+	}
+
+	return spec
+}
+
+const (
+	// The index doesn't exist and is created
+	IndexActionCreate = "create"
+	// The index exists with different options, it is left unchanged
+	IndexActionDrift = "drift"
+	// The index exists but isn't declared, it is left unchanged
+	IndexActionUndeclared = "undeclared"
+	// The search index exists with a different definition and is rebuilt, it serves queries with the old one until then
+	IndexActionUpdate = "update"
+)
+
+// indexChange is a difference between the declared and existing indexes of a store
+type indexChange struct {
+	Store  string
+	Index  string
+	Action string
+	Detail string
+}
+
+// The index as listed by mongo
+type existingIndex struct {
+	Name      string                 `bson:"name"`
+	Key       bson.D                 `bson:"key"`
+	Unique    bool                   `bson:"unique"`
+	Sparse    bool                   `bson:"sparse"`
+	Partial   map[string]interface{} `bson:"partialFilterExpression"`
+	Collation map[string]interface{} `bson:"collation"`
+}
+
+// A value as json with sorted keys, so numbers are equal whatever their bson type and documents whatever their field order
+func normalisedJson(v interface{}) (string, error) {
+	b, err := bson.MarshalExtJSON(bson.D{{"v", v}}, false, false)
+	if err != nil {
+		return "", err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return "", err
+	}
+
+	normalised, err := json.Marshal(generic)
+
+	return string(normalised), err
+}
+
+func sameValue(a interface{}, b interface{}) bool {
+	aJson, aErr := normalisedJson(a)
+	bJson, bErr := normalisedJson(b)
+
+	return aErr == nil && bErr == nil && aJson == bJson
+}
+
+func sameKeys(declared IndexSettings, existing bson.D) bool {
+	if len(declared.Keys) != len(existing) {
+		return false
+	}
+
+	for i, key := range declared.keys() {
+		if existing[i].Key != key.Key || !sameValue(existing[i].Value, key.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// How an existing index differs from its declaration, empty when it doesn't
+func indexDrift(declared IndexSettings, existing existingIndex) string {
+	differences := []string{}
+
+	if !sameKeys(declared, existing.Key) {
+		differences = append(differences, "keys")
+	}
+	if declared.Unique != existing.Unique {
+		differences = append(differences, "unique")
+	}
+	if declared.Sparse != existing.Sparse {
+		differences = append(differences, "sparse")
+	}
+	if !(declared.Partial == nil && existing.Partial == nil) && !sameValue(declared.Partial, existing.Partial) {
+		differences = append(differences, "partial")
+	}
+
+	// Mongo lists every collation option, so only the declared options are compared
+	if declared.Collation == nil && existing.Collation != nil {
+		differences = append(differences, "collation")
+	} else {
+		for option, value := range declared.Collation {
+			if !sameValue(value, existing.Collation[option]) {
+				differences = append(differences, "collation")
+				break
+			}
+		}
+	}
+
+	if len(differences) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s differ from the declaration", strings.Join(differences, ", "))
+}
+
+// Compare the declared indexes of a store's collection with those it has, creating the missing ones unless dryRun is set
+func reconcileIndexes(ctx context.Context, store string, coll *mongo.Collection, declared []IndexSettings, dryRun bool) ([]indexChange, error) {
+	existing := map[string]existingIndex{}
+
+	cursor, err := coll.Indexes().List(ctx)
+	var serverErr mongo.ServerError
+	if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrNamespaceNotFound)) {
+		return nil, err
+	}
+
+	// Collections that don't exist yet have no indexes
+	if err == nil {
+		var indexes []existingIndex
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return nil, err
+		}
+
+		for _, index := range indexes {
+			existing[index.Name] = index
+		}
+	}
+
+	changes := []indexChange{}
+	create := bson.A{}
+	declaredNames := map[string]bool{"_id_": true}
+
+	for _, index := range declared {
+		name := index.name()
+		declaredNames[name] = true
+
+		if current, ok := existing[name]; ok {
+			if drift := indexDrift(index, current); drift != "" {
+				changes = append(changes, indexChange{Store: store, Index: name, Action: IndexActionDrift, Detail: drift})
+			}
+			continue
+		}
+
+		// Mongo won't create an index with the same keys as another
+		conflict := ""
+		for _, current := range existing {
+			// Indexes of the same keys can only differ by collation
+			if sameKeys(index, current.Key) && (index.Collation == nil) == (current.Collation == nil) {
+				conflict = current.Name
+			}
+		}
+
+		if conflict != "" {
+			changes = append(changes, indexChange{Store: store, Index: name, Action: IndexActionDrift, Detail: fmt.Sprintf("its keys are already indexed by %s", conflict)})
+			continue
+		}
+
+		changes = append(changes, indexChange{Store: store, Index: name, Action: IndexActionCreate})
+		create = append(create, index.spec())
+	}
+
+	for name := range existing {
+		if !declaredNames[name] {
+			changes = append(changes, indexChange{Store: store, Index: name, Action: IndexActionUndeclared})
+		}
+	}
+
+	if len(create) > 0 && !dryRun {
+		err := coll.Database().RunCommand(ctx, bson.D{
+			{"createIndexes", coll.Name()},
+			{"indexes", create},
+		}).Err()
+		if err != nil {
+			return changes, fmt.Errorf("unable to create indexes: %w", err)
+		}
+	}
+
+	return changes, nil
+}
+
+// searchIndex is an Atlas Search index of a store's collection
+type searchIndex struct {
+	Name string
+	// search or vectorSearch
+	Type       string
+	Definition bson.D
+}
+
+// The search index as listed by Atlas
+type existingSearchIndex struct {
+	Name             string                 `bson:"name"`
+	Type             string                 `bson:"type"`
+	LatestDefinition map[string]interface{} `bson:"latestDefinition"`
+}
+
+// The search indexes declared by a store's settings, its vector search indexes by vector name and then its search index
+func (s StoreSettings) searchIndexes() []searchIndex {
+	vectors := make([]string, 0, len(s.Vectors))
+	for vector := range s.Vectors {
+		vectors = append(vectors, vector)
+	}
+	sort.Strings(vectors)
+
+	indexes := []searchIndex{}
+	for _, vector := range vectors {
+		indexes = append(indexes, s.Vectors[vector].searchIndex(vector))
+	}
+
+	if s.Search != nil {
+		indexes = append(indexes, s.Search.searchIndex())
+	}
+
+	return indexes
+}
+
+// AtlasSearchIndex is a search index of a store in the form of the inputs of the Atlas search index resource
+type AtlasSearchIndex struct {
+	Name string
+	// search or vectorSearch
+	Type string
+	// Analyzer of the text fields of a search index
+	Analyzer string
+	// JSON encoded field mappings of a search index
+	MappingsFields string
+	// JSON encoded fields of a vectorSearch index
+	Fields string
+}
+
+// AtlasSearchIndexes returns the search indexes declared by a store's settings, so deployments can provision the
+// same indexes the runtime creates
+func (s StoreSettings) AtlasSearchIndexes() ([]AtlasSearchIndex, error) {
+	indexes := []AtlasSearchIndex{}
+
+	for _, index := range s.searchIndexes() {
+		body, err := bson.MarshalExtJSON(index.Definition, false, false)
+		if err != nil {
+			return nil, err
+		}
+
+		definition := struct {
+			Analyzer string          `json:"analyzer"`
+			Fields   json.RawMessage `json:"fields"`
+			Mappings struct {
+				Fields json.RawMessage `json:"fields"`
+			} `json:"mappings"`
+		}{}
+		if err := json.Unmarshal(body, &definition); err != nil {
+			return nil, err
+		}
+
+		indexes = append(indexes, AtlasSearchIndex{
+			Name:           index.Name,
+			Type:           index.Type,
+			Analyzer:       definition.Analyzer,
+			MappingsFields: string(definition.Mappings.Fields),
+			Fields:         string(definition.Fields),
+		})
+	}
+
+	return indexes, nil
+}
+
+// Compare the declared search indexes of a store's collection with those it has, creating the missing ones and updating
+// those with a different definition unless dryRun is set.
+//
+// Search indexes are built asynchronously by Atlas, so they may not serve queries until some time after they are created.
+func reconcileSearchIndexes(ctx context.Context, store string, coll *mongo.Collection, declared []searchIndex, dryRun bool) ([]indexChange, error) {
+	var serverErr mongo.ServerError
+
+	// Search indexes can't be created before their collection
+	if !dryRun {
+		err := coll.Database().CreateCollection(ctx, coll.Name())
+		if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrNamespaceExists)) {
+			return nil, err
+		}
+	}
+
+	existing := map[string]existingSearchIndex{}
+
+	cursor, err := coll.SearchIndexes().List(ctx, nil)
+	if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrNamespaceNotFound)) {
+		return nil, err
+	}
+
+	if err == nil {
+		var indexes []existingSearchIndex
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return nil, err
+		}
+
+		for _, index := range indexes {
+			existing[index.Name] = index
+		}
+	}
+
+	changes := []indexChange{}
+	declaredNames := map[string]bool{}
+
+	for _, index := range declared {
+		declaredNames[index.Name] = true
+
+		current, ok := existing[index.Name]
+		if !ok {
+			changes = append(changes, indexChange{Store: store, Index: index.Name, Action: IndexActionCreate})
+			if dryRun {
+				continue
+			}
+
+			err := coll.Database().RunCommand(ctx, bson.D{
+				{"createSearchIndexes", coll.Name()},
+				{"indexes", bson.A{bson.D{{"name", index.Name}, {"type", index.Type}, {"definition", index.Definition}}}},
+			}).Err()
+			if err != nil {
+				return changes, fmt.Errorf("unable to create search index %s: %w", index.Name, err)
+			}
+
+			continue
+		}
+
+		// The type of a search index can't be changed by updating it
+		if current.Type != "" && current.Type != index.Type {
+			changes = append(changes, indexChange{Store: store, Index: index.Name, Action: IndexActionDrift, Detail: fmt.Sprintf("it is a %s index, not %s", current.Type, index.Type)})
+			continue
+		}
+
+		if sameValue(index.Definition, current.LatestDefinition) {
+			continue
+		}
+
+		changes = append(changes, indexChange{Store: store, Index: index.Name, Action: IndexActionUpdate})
+		if dryRun {
+			continue
+		}
+
+		err := coll.Database().RunCommand(ctx, bson.D{
+			{"updateSearchIndex", coll.Name()},
+			{"name", index.Name},
+			{"definition", index.Definition},
+		}).Err()
+		if err != nil {
+			return changes, fmt.Errorf("unable to update search index %s: %w", index.Name, err)
+		}
+	}
+
+	for name := range existing {
+		if !declaredNames[name] {
+			changes = append(changes, indexChange{Store: store, Index: name, Action: IndexActionUndeclared})
+		}
+	}
+
+	return changes, nil
+}
+
+func logIndexChanges(ns string, changes []indexChange, dryRun bool) {
+	for _, change := range changes {
+		switch change.Action {
+		case IndexActionCreate:
+			if dryRun {
+				logger.Infof("index %s %s: would be created", ns, change.Index)
+			} else {
+				logger.Infof("index %s %s: created", ns, change.Index)
+			}
+		case IndexActionUpdate:
+			if dryRun {
+				logger.Infof("index %s %s: differs from the declaration, would be updated", ns, change.Index)
+			} else {
+				logger.Infof("index %s %s: differs from the declaration, updated", ns, change.Index)
+			}
+		case IndexActionDrift:
+			logger.Warnf("index %s %s: %s, it is left unchanged", ns, change.Index, change.Detail)
+		case IndexActionUndeclared:
+			logger.Infof("index %s %s: not declared, it is left unchanged", ns, change.Index)
+		}
+	}
+}
+
+// indexReconciler creates the declared indexes and search indexes of the stores served by MongoDBServer
+type indexReconciler struct {
+	indexes map[string][]IndexSettings
+	search  map[string][]searchIndex
+	dryRun  bool
+
+	// Tenant collections whose indexes have been reconciled
+	reconciled sync.Map
+}
+
+func (r *indexReconciler) reconcile(ctx context.Context, store string, coll *mongo.Collection) {
+	ns := coll.Database().Name() + "." + coll.Name()
+
+	if len(r.indexes[store]) > 0 {
+		changes, err := reconcileIndexes(ctx, store, coll, r.indexes[store], r.dryRun)
+		// When creating indexes fails, none of them were created
+		logIndexChanges(ns, changes, r.dryRun || err != nil)
+		if err != nil {
+			logger.Errorf("index reconciliation of %s failed: %v", ns, err)
+		}
+	}
+
+	if len(r.search[store]) > 0 {
+		changes, err := reconcileSearchIndexes(ctx, store, coll, r.search[store], r.dryRun)
+		// Search indexes are created one at a time, so the changes before a failure were made
+		logIndexChanges(ns, changes, r.dryRun)
+		if err != nil {
+			logger.Errorf("search index reconciliation of %s failed: %v", ns, err)
+		}
+	}
+}
+
+// The stores with declared indexes or search indexes
+func (r *indexReconciler) stores() []string {
+	stores := []string{}
+	for store := range r.indexes {
+		stores = append(stores, store)
+	}
+	for store := range r.search {
+		if _, ok := r.indexes[store]; !ok {
+			stores = append(stores, store)
+		}
+	}
+
+	return stores
+}
+
+// Reconcile the indexes of every store, and of each tenant database that exists when tenancy is enabled
+func (r *indexReconciler) reconcileAll(ctx context.Context, k *MongoDBServer) {
+	if r == nil {
+		return
+	}
+
+	for _, store := range r.stores() {
+		if k.tenancy == TenancyModeNone {
+			r.reconcile(ctx, store, k.getCollectionHandle(store))
+			continue
+		}
+
+		db := k.getDatabaseHandle(store)
+
+		names, err := db.Client().ListDatabaseNames(ctx, bson.D{{"name", bson.D{{"$regex", "^" + regexp.QuoteMeta(tenantDatabaseName(db.Name(), ""))}}}})
+		if err != nil {
+			logger.Errorf("index reconciliation of store %s failed: unable to list tenant databases: %v", store, err)
+			continue
+		}
+
+		for _, name := range names {
+			coll := db.Client().Database(name).Collection(store)
+			r.reconciled.Store(coll.Database().Name()+"."+store, true)
+			r.reconcile(ctx, store, coll)
+		}
+	}
+}
+
+// Reconcile the indexes of a tenant's collection the first time it is written to
+func (r *indexReconciler) ensureTenant(store string, coll *mongo.Collection) {
+	if r == nil || (len(r.indexes[store]) == 0 && len(r.search[store]) == 0) {
+		return
+	}
+
+	if _, loaded := r.reconciled.LoadOrStore(coll.Database().Name()+"."+store, true); loaded {
+		return
+	}
+
+	go r.reconcile(context.Background(), store, coll)
+}
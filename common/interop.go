@@ -0,0 +1,184 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Prefixes of the keys of _ids that aren't plain strings in interop stores.
+//
+// Only keys starting with $ are typed, string _ids starting with $ are given the string prefix so every key maps to one _id.
+const (
+	interopKeyPrefix    = "$"
+	interopStringPrefix = "$string:"
+	interopOidPrefix    = "$oid:"
+	interopUuidPrefix   = "$uuid:"
+	interopIntPrefix    = "$int:"
+	interopLongPrefix   = "$long:"
+	interopDoublePrefix = "$double:"
+)
+
+// The key of a document's _id in an interop store, false when the _id's type has no key
+func interopKey(id bson.RawValue) (string, bool) {
+	switch id.Type {
+	case bsontype.String:
+		key := id.StringValue()
+		if strings.HasPrefix(key, interopKeyPrefix) {
+			return interopStringPrefix + key, true
+		}
+
+		return key, true
+	case bsontype.ObjectID:
+		return interopOidPrefix + id.ObjectID().Hex(), true
+	case bsontype.Binary:
+		subtype, data := id.Binary()
+		if subtype != bson.TypeBinaryUUID || len(data) != 16 {
+			return "", false
+		}
+
+		return interopUuidPrefix + formatUuid(data), true
+	case bsontype.Int32:
+		return interopIntPrefix + strconv.FormatInt(int64(id.Int32()), 10), true
+	case bsontype.Int64:
+		return interopLongPrefix + strconv.FormatInt(id.Int64(), 10), true
+	case bsontype.Double:
+		return interopDoublePrefix + strconv.FormatFloat(id.Double(), 'g', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// The _id of a key in an interop store
+func interopId(key string) (interface{}, error) {
+	if !strings.HasPrefix(key, interopKeyPrefix) {
+		return key, nil
+	}
+
+	prefix, value, _ := strings.Cut(key, ":")
+	prefix += ":"
+
+	switch prefix {
+	case interopStringPrefix:
+		return value, nil
+	case interopOidPrefix:
+		return primitive.ObjectIDFromHex(value)
+	case interopUuidPrefix:
+		data, err := hex.DecodeString(strings.ReplaceAll(value, "-", ""))
+		if err != nil || len(data) != 16 {
+			return nil, fmt.Errorf("invalid uuid %q", value)
+		}
+
+		return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: data}, nil
+	case interopIntPrefix:
+		i, err := strconv.ParseInt(value, 10, 32)
+		return int32(i), err
+	case interopLongPrefix:
+		return strconv.ParseInt(value, 10, 64)
+	case interopDoublePrefix:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return nil, fmt.Errorf("keys starting with %q must be typed keys, such as %s<hex> or %s<string>", interopKeyPrefix, interopOidPrefix, interopStringPrefix)
+	}
+}
+
+func formatUuid(data []byte) string {
+	h := hex.EncodeToString(data)
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// The _id a key is stored under, which is the key itself unless the store is an interop store
+func (k *MongoDBServer) storeId(store string, key string) (interface{}, error) {
+	if !k.interopStores[store] {
+		return key, nil
+	}
+
+	return interopId(key)
+}
+
+// The key of a document's _id, false when the document can't be reached with a key
+func (k *MongoDBServer) storeKey(store string, id bson.RawValue) (string, bool) {
+	if !k.interopStores[store] {
+		return id.StringValueOK()
+	}
+
+	return interopKey(id)
+}
+
+// The content of a document, with the values of types json doesn't have converted to plain values in interop stores
+func (k *MongoDBServer) storeContent(store string, doc bson.Raw) (*structpb.Struct, error) {
+	if !k.interopStores[store] {
+		return contentFromDocument(doc)
+	}
+
+	var fields bson.D
+	if err := bson.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+
+	for i := range fields {
+		// Compressed content was written by the runtime, so it is decompressed as it is
+		if fields[i].Key != compressedField {
+			fields[i].Value = plainValue(fields[i].Value)
+		}
+	}
+
+	plain, err := bson.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return contentFromDocument(plain)
+}
+
+// Convert the values of documents written by other clients to the plain values json has,
+// rather than the extended json objects that keep their types.
+//
+// ObjectIds become hex strings, UUIDs canonical strings, other binary base64 strings, dates RFC 3339 strings and decimals strings.
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		for i := range v {
+			v[i].Value = plainValue(v[i].Value)
+		}
+
+		return v
+	case bson.A:
+		for i := range v {
+			v[i] = plainValue(v[i])
+		}
+
+		return v
+	case primitive.ObjectID:
+		return v.Hex()
+	case primitive.Binary:
+		if v.Subtype == bson.TypeBinaryUUID && len(v.Data) == 16 {
+			return formatUuid(v.Data)
+		}
+
+		return base64.StdEncoding.EncodeToString(v.Data)
+	case primitive.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case primitive.Timestamp:
+		return time.Unix(int64(v.T), 0).UTC().Format(time.RFC3339)
+	case primitive.Decimal128:
+		return v.String()
+	case primitive.Regex:
+		return "/" + v.Pattern + "/" + v.Options
+	case primitive.JavaScript:
+		return string(v)
+	case primitive.Symbol:
+		return string(v)
+	default:
+		return value
+	}
+}
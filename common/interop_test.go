@@ -0,0 +1,87 @@
+package common
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The raw value of an _id, as it is read from a document
+func rawId(t *testing.T, id interface{}) bson.RawValue {
+	t.Helper()
+
+	doc, err := bson.Marshal(bson.D{{"_id", id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bson.Raw(doc).Lookup("_id")
+}
+
+func TestInteropKeyRoundTrip(t *testing.T) {
+	oid, err := primitive.ObjectIDFromHex("65a1f0c2e4b0a1b2c3d4e5f6")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uuid := primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}}
+
+	tests := []struct {
+		name string
+		id   interface{}
+		key  string
+	}{
+		{name: "string", id: "order-1", key: "order-1"},
+		{name: "string starting with $", id: "$price", key: "$string:$price"},
+		{name: "object id", id: oid, key: "$oid:65a1f0c2e4b0a1b2c3d4e5f6"},
+		{name: "uuid", id: uuid, key: "$uuid:12345678-9abc-def0-1234-56789abcdef0"},
+		{name: "int", id: int32(-42), key: "$int:-42"},
+		{name: "long", id: int64(1) << 40, key: "$long:1099511627776"},
+		{name: "double", id: 2.5, key: "$double:2.5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, ok := interopKey(rawId(t, test.id))
+			if !ok {
+				t.Fatal("expected the _id to have a key")
+			}
+
+			if key != test.key {
+				t.Fatalf("expected key %q, got %q", test.key, key)
+			}
+
+			id, err := interopId(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !rawId(t, id).Equal(rawId(t, test.id)) {
+				t.Fatalf("expected _id %v, got %v", test.id, id)
+			}
+		})
+	}
+}
+
+func TestInteropKeyUnsupported(t *testing.T) {
+	unsupported := []interface{}{
+		bson.D{{"a", 1}},
+		primitive.Binary{Subtype: bson.TypeBinaryGeneric, Data: []byte{1, 2}},
+		true,
+	}
+
+	for _, id := range unsupported {
+		if key, ok := interopKey(rawId(t, id)); ok {
+			t.Fatalf("expected %v to have no key, got %q", id, key)
+		}
+	}
+}
+
+func TestInteropIdInvalid(t *testing.T) {
+	for _, key := range []string{"$oid:xyz", "$uuid:1234", "$int:99999999999", "$long:one", "$double:half", "$price", "$unknown:1"} {
+		if _, err := interopId(key); err == nil {
+			t.Fatalf("expected key %q to be rejected", key)
+		}
+	}
+}
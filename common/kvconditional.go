@@ -0,0 +1,407 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kvconditionalpb "github.com/nitrictech/mongodb-provider/common/proto/kvconditional/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// errPreconditionFailed is returned when a value doesn't match the precondition of a conditional write
+var errPreconditionFailed = fmt.Errorf("the value doesn't match the precondition")
+
+type valueVersion struct {
+	Revision int64  `bson:"_revision"`
+	Hash     string `bson:"_hash"`
+}
+
+// MongoKvConditionalServer reads and writes the documents stored by MongoDBServer conditionally,
+// using the revision and content hash (ETag) kept on each document.
+//
+// Documents written before revisions were tracked have revision 0 until they are next written.
+// Their ETag is the hash of their content, which writes compare against in the same transaction as the write.
+type MongoKvConditionalServer struct {
+	kv *MongoDBServer
+}
+
+var _ kvconditionalpb.KvConditionalServer = &MongoKvConditionalServer{}
+
+func (v valueVersion) matches(precondition *kvconditionalpb.Precondition) bool {
+	switch condition := precondition.GetCondition().(type) {
+	case *kvconditionalpb.Precondition_Revision:
+		return v.Revision == condition.Revision
+	case *kvconditionalpb.Precondition_Etag:
+		return v.Hash == condition.Etag
+	default:
+		return false
+	}
+}
+
+// Whether a value meets the precondition of a write, version is nil when the value doesn't exist.
+// Writes without a precondition always apply and revision 0 also matches a value that doesn't exist.
+func preconditionMet(version *valueVersion, precondition *kvconditionalpb.Precondition) bool {
+	if precondition.GetCondition() == nil {
+		return true
+	}
+
+	if version == nil {
+		condition, ok := precondition.GetCondition().(*kvconditionalpb.Precondition_Revision)
+		return ok && condition.Revision == 0
+	}
+
+	return version.matches(precondition)
+}
+
+// The version and content of a stored document, values written before hashes were stored are hashed on read
+func (c *MongoKvConditionalServer) documentVersion(store string, doc bson.Raw) (*valueVersion, *structpb.Struct, error) {
+	version := &valueVersion{}
+	if err := bson.Unmarshal(doc, version); err != nil {
+		return nil, nil, err
+	}
+
+	content, err := c.kv.storeContent(store, doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if version.Hash == "" {
+		version.Hash, err = contentHash(content)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return version, content, nil
+}
+
+// Check content doesn't set the fields stored alongside it, which would be mistaken for its version
+func validateConditionalContent(content *structpb.Struct) error {
+	for field := range content.GetFields() {
+		if isReservedField(field) {
+			return fmt.Errorf("field %s is reserved", field)
+		}
+	}
+
+	return nil
+}
+
+// The collection and _id of a value, the ref's key is resolved the same way as by MongoDBServer
+func (c *MongoKvConditionalServer) scope(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, ref *kvconditionalpb.ValueRef) (*mongo.Collection, string, interface{}, error) {
+	if ref == nil {
+		return nil, "", nil, newErr(
+			codes.InvalidArgument,
+			"a value ref is required",
+			fmt.Errorf("ref not set"),
+		)
+	}
+
+	if err := c.kv.timeSeriesErr(newErr, ref.Store); err != nil {
+		return nil, "", nil, err
+	}
+
+	coll, key, err := c.kv.scopedCollection(ctx, ref.Store, ref.Key)
+	if err != nil {
+		return nil, "", nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	id, err := c.kv.storeId(ref.Store, key)
+	if err != nil {
+		return nil, "", nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid key %s", ref.Key),
+			err,
+		)
+	}
+
+	if err := c.kv.quotas.allow(ctx, ref.Store); err != nil {
+		return nil, "", nil, quotaErr(newErr, err)
+	}
+
+	return coll, key, id, nil
+}
+
+// Run a write in a transaction once the value meets the precondition.
+//
+// The write returns a function releasing the quota usage it reserved, which is called if the transaction fails or is retried.
+func (c *MongoKvConditionalServer) writeIfMatch(ctx context.Context, coll *mongo.Collection, store string, id interface{}, precondition *kvconditionalpb.Precondition, write func(txCtx mongo.SessionContext, version *valueVersion) (func(), interface{}, error)) (interface{}, error) {
+	session, err := coll.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	release := func() {}
+
+	res, err := session.WithTransaction(ctx, func(txCtx mongo.SessionContext) (interface{}, error) {
+		// The usage reserved by an attempt that was retried
+		release()
+		release = func() {}
+
+		var version *valueVersion
+
+		doc, err := coll.FindOne(txCtx, bson.D{{"_id", id}}).Raw()
+		if err == nil {
+			version, _, err = c.documentVersion(store, doc)
+		} else if errors.Is(err, mongo.ErrNoDocuments) {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !preconditionMet(version, precondition) {
+			return nil, errPreconditionFailed
+		}
+
+		written, res, err := write(txCtx, version)
+		if written != nil {
+			release = written
+		}
+
+		return res, err
+	})
+	if err != nil {
+		release()
+	}
+
+	return res, err
+}
+
+// The status of a failed conditional write
+func conditionalWriteErr(newErr grpc_errors.ScopedErrorFactory, action string, ref *kvconditionalpb.ValueRef, err error) error {
+	var serverErr mongo.ServerError
+	var exceeded *quotaError
+
+	if errors.Is(err, errPreconditionFailed) {
+		return newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("%s in store %s doesn't match the precondition", ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &exceeded) {
+		return quotaErr(newErr, err)
+	} else if mongo.IsDuplicateKeyError(err) {
+		return newErr(
+			codes.AlreadyExists,
+			fmt.Sprintf("unable to %s %s in %s store, a value with the same unique index fields exists", action, ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCannotExtractGeoKeys) {
+		return newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("unable to %s %s in %s store, a geo field isn't valid GeoJSON", action, ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCappedDocumentSize) {
+		return newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("unable to %s %s in %s store, values of capped stores can only be replaced with values of the same size", action, ref.Key, ref.Store),
+			err,
+		)
+	}
+
+	return newErr(
+		codes.Internal,
+		fmt.Sprintf("unable to %s %s in %s store", action, ref.Key, ref.Store),
+		err,
+	)
+}
+
+// Get a value unless it still matches a revision or ETag the caller already has
+func (c *MongoKvConditionalServer) GetIfChanged(ctx context.Context, req *kvconditionalpb.KvConditionalGetRequest) (*kvconditionalpb.KvConditionalGetResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoKvConditionalServer.GetIfChanged")
+
+	coll, _, id, err := c.scope(ctx, newErr, req.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := coll.FindOne(ctx, bson.D{{"_id", id}}).Raw()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("key %s not found in store %s", req.Ref.Key, req.Ref.Store),
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read %s from %s store", req.Ref.Key, req.Ref.Store),
+			err,
+		)
+	}
+
+	version, content, err := c.documentVersion(req.Ref.Store, res)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to read value",
+			err,
+		)
+	}
+
+	if version.matches(req.IfNoneMatch) {
+		return &kvconditionalpb.KvConditionalGetResponse{
+			Changed:  false,
+			Revision: version.Revision,
+			Etag:     version.Hash,
+		}, nil
+	}
+
+	return &kvconditionalpb.KvConditionalGetResponse{
+		Changed:  true,
+		Content:  content,
+		Revision: version.Revision,
+		Etag:     version.Hash,
+	}, nil
+}
+
+// Set a value only if it is at the given revision or ETag
+func (c *MongoKvConditionalServer) SetIfMatch(ctx context.Context, req *kvconditionalpb.KvConditionalSetRequest) (*kvconditionalpb.KvConditionalSetResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoKvConditionalServer.SetIfMatch")
+
+	if err := validateConditionalContent(req.Content); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid value content",
+			err,
+		)
+	}
+
+	coll, key, id, err := c.scope(ctx, newErr, req.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.kv.collections.ensure(ctx, req.Ref.Store, coll); err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("unable to create the collection of %s store", req.Ref.Store),
+			err,
+		)
+	}
+
+	stored, err := c.kv.compressor.stored(ctx, req.Ref.Store, req.Content)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to compress value content",
+			err,
+		)
+	}
+
+	hash, err := contentHash(req.Content)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to hash value content",
+			err,
+		)
+	}
+
+	update, err := setStoredValueUpdate(req.Content, stored)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to hash value content",
+			err,
+		)
+	}
+
+	res, err := c.writeIfMatch(ctx, coll, req.Ref.Store, id, req.IfMatch, func(txCtx mongo.SessionContext, version *valueVersion) (func(), interface{}, error) {
+		// Quota usage is kept in the stack's cluster, outside the transaction
+		release, err := c.kv.quotas.reserve(ctx, coll, req.Ref.Store, id, stored)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = coll.UpdateOne(txCtx, bson.D{{"_id", id}}, update, options.Update().SetUpsert(true))
+		if err != nil {
+			return release, nil, err
+		}
+
+		revision := int64(1)
+		if version != nil {
+			revision = version.Revision + 1
+		}
+
+		return release, &valueVersion{Revision: revision, Hash: hash}, nil
+	})
+	if err != nil {
+		return nil, conditionalWriteErr(newErr, "set", req.Ref, err)
+	}
+
+	// Tenant databases are created by their first write, so they are indexed then
+	if c.kv.tenancy != TenancyModeNone {
+		c.kv.indexes.ensureTenant(req.Ref.Store, coll)
+	}
+
+	if c.kv.cachedStores[req.Ref.Store] {
+		c.kv.cache.invalidate(req.Ref.Store, key)
+	}
+
+	version := res.(*valueVersion)
+
+	return &kvconditionalpb.KvConditionalSetResponse{
+		Revision: version.Revision,
+		Etag:     version.Hash,
+	}, nil
+}
+
+// Delete a value only if it is at the given revision or ETag
+func (c *MongoKvConditionalServer) DeleteIfMatch(ctx context.Context, req *kvconditionalpb.KvConditionalDeleteRequest) (*kvconditionalpb.KvConditionalDeleteResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoKvConditionalServer.DeleteIfMatch")
+
+	if req.Ref != nil {
+		if err := c.kv.cappedDeleteErr(newErr, req.Ref.Store); err != nil {
+			return nil, err
+		}
+	}
+
+	coll, key, id, err := c.scope(ctx, newErr, req.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.writeIfMatch(ctx, coll, req.Ref.Store, id, req.IfMatch, func(txCtx mongo.SessionContext, version *valueVersion) (func(), interface{}, error) {
+		// A missing value that meets the precondition has nothing to delete
+		if version == nil {
+			return nil, nil, nil
+		}
+
+		release, err := c.kv.quotas.reserve(ctx, coll, req.Ref.Store, id, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = coll.DeleteOne(txCtx, bson.D{{"_id", id}})
+
+		return release, nil, err
+	})
+	if err != nil {
+		return nil, conditionalWriteErr(newErr, "delete", req.Ref, err)
+	}
+
+	if c.kv.cachedStores[req.Ref.Store] {
+		c.kv.cache.invalidate(req.Ref.Store, key)
+	}
+
+	return &kvconditionalpb.KvConditionalDeleteResponse{}, nil
+}
+
+func NewKvConditional(kv *MongoDBServer) *MongoKvConditionalServer {
+	return &MongoKvConditionalServer{
+		kv: kv,
+	}
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"testing"
+
+	kvconditionalpb "github.com/nitrictech/mongodb-provider/common/proto/kvconditional/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestPreconditionMet(t *testing.T) {
+	revision := func(revision int64) *kvconditionalpb.Precondition {
+		return &kvconditionalpb.Precondition{Condition: &kvconditionalpb.Precondition_Revision{Revision: revision}}
+	}
+	etag := func(etag string) *kvconditionalpb.Precondition {
+		return &kvconditionalpb.Precondition{Condition: &kvconditionalpb.Precondition_Etag{Etag: etag}}
+	}
+
+	stored := &valueVersion{Revision: 3, Hash: "abc"}
+	// Written before revisions were tracked, its hash is computed from its content
+	legacy := &valueVersion{Revision: 0, Hash: "def"}
+
+	tests := []struct {
+		name         string
+		version      *valueVersion
+		precondition *kvconditionalpb.Precondition
+		met          bool
+	}{
+		{name: "no precondition", version: stored, precondition: nil, met: true},
+		{name: "empty precondition", version: nil, precondition: &kvconditionalpb.Precondition{}, met: true},
+		{name: "matching revision", version: stored, precondition: revision(3), met: true},
+		{name: "older revision", version: stored, precondition: revision(2), met: false},
+		{name: "matching etag", version: stored, precondition: etag("abc"), met: true},
+		{name: "different etag", version: stored, precondition: etag("def"), met: false},
+		{name: "legacy etag", version: legacy, precondition: etag("def"), met: true},
+		{name: "legacy revision", version: legacy, precondition: revision(0), met: true},
+		{name: "missing value with revision 0", version: nil, precondition: revision(0), met: true},
+		{name: "missing value with revision", version: nil, precondition: revision(1), met: false},
+		{name: "missing value with etag", version: nil, precondition: etag("abc"), met: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if met := preconditionMet(test.version, test.precondition); met != test.met {
+				t.Fatalf("expected met to be %v, got %v", test.met, met)
+			}
+		})
+	}
+}
+
+func TestDocumentVersion(t *testing.T) {
+	c := &MongoKvConditionalServer{kv: &MongoDBServer{}}
+
+	content, err := structpb.NewStruct(map[string]interface{}{"status": "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := contentHash(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     bson.D
+		version valueVersion
+	}{
+		{
+			name:    "tracked",
+			doc:     bson.D{{"_id", "a"}, {"status", "open"}, {"_revision", int64(2)}, {"_hash", "stored"}},
+			version: valueVersion{Revision: 2, Hash: "stored"},
+		},
+		{
+			name:    "legacy",
+			doc:     bson.D{{"_id", "a"}, {"status", "open"}},
+			version: valueVersion{Revision: 0, Hash: hash},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, err := bson.Marshal(test.doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, got, err := c.documentVersion("orders", raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *version != test.version {
+				t.Fatalf("expected version %+v, got %+v", test.version, *version)
+			}
+
+			if got.Fields["status"].GetStringValue() != "open" || len(got.Fields) != 1 {
+				t.Fatalf("expected only the content of the document, got %v", got)
+			}
+		})
+	}
+}
+
+func TestValidateConditionalContent(t *testing.T) {
+	for _, field := range []string{"_revision", "_hash"} {
+		content, err := structpb.NewStruct(map[string]interface{}{field: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := validateConditionalContent(content); err == nil {
+			t.Fatalf("expected content setting %s to be rejected", field)
+		}
+	}
+}
@@ -0,0 +1,633 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	kvupdatepb "github.com/nitrictech/mongodb-provider/common/proto/kvupdate/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Server error codes returned when an update doesn't fit the stored value
+const (
+	mongoErrBadValue                   = 2
+	mongoErrTypeMismatch               = 14
+	mongoErrPathNotViable              = 28
+	mongoErrConflictingUpdateOperators = 40
+)
+
+// MongoKvUpdateServer applies partial updates to the documents stored by MongoDBServer.
+//
+// Every request is translated into a single update of the document, so concurrent updates
+// don't overwrite each other the way a read, modify and SetValue would.
+// The update advances the revision of the value and its content hash is refreshed in the same transaction.
+type MongoKvUpdateServer struct {
+	kv *MongoDBServer
+}
+
+var _ kvupdatepb.KvUpdateServer = &MongoKvUpdateServer{}
+
+// updateBuilder collects the fields changed by each update operator
+type updateBuilder struct {
+	operators map[string]bson.D
+	order     []string
+	paths     map[string]bool
+	// Conditions the document must meet for the update to apply
+	conditions bson.D
+}
+
+func newUpdateBuilder() *updateBuilder {
+	return &updateBuilder{
+		operators: map[string]bson.D{},
+		paths:     map[string]bool{},
+	}
+}
+
+func (b *updateBuilder) add(operator string, path string, value interface{}) error {
+	if b.paths[path] {
+		return fmt.Errorf("field %s is changed more than once", path)
+	}
+	b.paths[path] = true
+
+	if _, ok := b.operators[operator]; !ok {
+		b.order = append(b.order, operator)
+	}
+	b.operators[operator] = append(b.operators[operator], bson.E{path, value})
+
+	return nil
+}
+
+// Whether a field, one of its parents or one of its members has been changed
+func (b *updateBuilder) changes(path string) bool {
+	for changed := range b.paths {
+		if changed == path || strings.HasPrefix(path, changed+".") || strings.HasPrefix(changed, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *updateBuilder) require(path string, condition interface{}) {
+	b.conditions = append(b.conditions, bson.E{path, condition})
+}
+
+func (b *updateBuilder) update() bson.D {
+	update := bson.D{}
+	for _, operator := range b.order {
+		update = append(update, bson.E{operator, b.operators[operator]})
+	}
+
+	return update
+}
+
+// Check a single segment of a field path can be used in an update
+func validatePathSegment(segment string) error {
+	if segment == "" {
+		return fmt.Errorf("field names must not be empty")
+	}
+
+	if strings.Contains(segment, ".") || strings.HasPrefix(segment, "$") {
+		return fmt.Errorf("field name %q must not contain '.' or start with '$'", segment)
+	}
+
+	return nil
+}
+
+// Check a dot separated field path can be used in an update
+func validateFieldPath(path string) error {
+	segments := strings.Split(path, ".")
+	if isReservedField(segments[0]) {
+		return fmt.Errorf("field %s is reserved", segments[0])
+	}
+
+	for _, segment := range segments {
+		if err := validatePathSegment(segment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Keep whole amounts as integers so counters stored as integers stay integers
+func incrementAmount(amount float64) interface{} {
+	if amount == math.Trunc(amount) && math.Abs(amount) < math.MaxInt64 {
+		return int64(amount)
+	}
+
+	return amount
+}
+
+func (b *updateBuilder) addOperation(operation *kvupdatepb.UpdateOperation) error {
+	switch op := operation.Operation.(type) {
+	case *kvupdatepb.UpdateOperation_Increment:
+		if err := validateFieldPath(op.Increment.Path); err != nil {
+			return err
+		}
+
+		return b.add("$inc", op.Increment.Path, incrementAmount(op.Increment.Amount))
+	case *kvupdatepb.UpdateOperation_Set:
+		if err := validateFieldPath(op.Set.Path); err != nil {
+			return err
+		}
+
+		return b.add("$set", op.Set.Path, op.Set.Value.AsInterface())
+	case *kvupdatepb.UpdateOperation_Unset:
+		if err := validateFieldPath(op.Unset.Path); err != nil {
+			return err
+		}
+
+		return b.add("$unset", op.Unset.Path, "")
+	case *kvupdatepb.UpdateOperation_Append:
+		if err := validateFieldPath(op.Append.Path); err != nil {
+			return err
+		}
+
+		values := make(bson.A, 0, len(op.Append.Values))
+		for _, value := range op.Append.Values {
+			values = append(values, value.AsInterface())
+		}
+
+		return b.add("$push", op.Append.Path, bson.D{{"$each", values}})
+	case *kvupdatepb.UpdateOperation_Remove:
+		if err := validateFieldPath(op.Remove.Path); err != nil {
+			return err
+		}
+
+		// Match whole elements, so object values aren't read as query operators or partial matches
+		return b.add("$pull", op.Remove.Path, bson.D{{"$eq", op.Remove.Value.AsInterface()}})
+	default:
+		return fmt.Errorf("unknown update operation %T", op)
+	}
+}
+
+// Add the changes of a merge patch, null members unset fields and object members are merged recursively
+func (b *updateBuilder) addMergePatch(prefix string, patch *structpb.Struct) error {
+	// Sort the members so the update is the same for the same patch
+	keys := make([]string, 0, len(patch.Fields))
+	for key := range patch.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := validatePathSegment(key); err != nil {
+			return err
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		} else if isReservedField(key) {
+			return fmt.Errorf("field %s is reserved", key)
+		}
+
+		value := patch.Fields[key]
+		switch v := value.Kind.(type) {
+		case *structpb.Value_NullValue:
+			if err := b.add("$unset", path, ""); err != nil {
+				return err
+			}
+		case *structpb.Value_StructValue:
+			if err := b.addMergePatch(path, v.StructValue); err != nil {
+				return err
+			}
+		default:
+			if err := b.add("$set", path, value.AsInterface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Convert a JSON Pointer to a dot separated field path, returning the path and its last segment
+func pointerToPath(pointer string) (string, string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return "", "", fmt.Errorf("path %q must be a JSON Pointer to a field", pointer)
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		segment = strings.ReplaceAll(segment, "~1", "/")
+		segment = strings.ReplaceAll(segment, "~0", "~")
+
+		if err := validatePathSegment(segment); err != nil {
+			return "", "", err
+		}
+
+		segments[i] = segment
+	}
+
+	if isReservedField(segments[0]) {
+		return "", "", fmt.Errorf("field %s is reserved", segments[0])
+	}
+
+	return strings.Join(segments, "."), segments[len(segments)-1], nil
+}
+
+// The parent path of a field path, and the array index of the field if it has one
+func arrayElement(path string, last string) (string, int, bool) {
+	// Members of the value itself are never array elements
+	if path == last {
+		return "", 0, false
+	}
+
+	index, err := strconv.Atoi(last)
+	if err != nil || index < 0 {
+		return "", 0, false
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(path, last), "."), index, true
+}
+
+func (b *updateBuilder) addJsonPatchOperation(operation *kvupdatepb.JsonPatchOperation) error {
+	path, last, err := pointerToPath(operation.Path)
+	if err != nil {
+		return err
+	}
+
+	switch operation.Op {
+	case "add":
+		if last == "-" && path != last {
+			parent := strings.TrimSuffix(strings.TrimSuffix(path, last), ".")
+			return b.add("$push", parent, operation.Value.AsInterface())
+		}
+
+		// Numeric members are treated as array positions
+		if parent, index, ok := arrayElement(path, last); ok {
+			return b.add("$push", parent, bson.D{
+				{"$each", bson.A{operation.Value.AsInterface()}},
+				{"$position", index},
+			})
+		}
+
+		return b.add("$set", path, operation.Value.AsInterface())
+	case "remove":
+		if _, _, ok := arrayElement(path, last); ok {
+			return fmt.Errorf("removing array elements by position is not supported, use the remove update operation")
+		}
+
+		b.require(path, bson.D{{"$exists", true}})
+
+		return b.add("$unset", path, "")
+	case "replace":
+		b.require(path, bson.D{{"$exists", true}})
+
+		return b.add("$set", path, operation.Value.AsInterface())
+	case "move":
+		from, fromLast, err := pointerToPath(operation.From)
+		if err != nil {
+			return err
+		}
+
+		_, _, fromIndex := arrayElement(from, fromLast)
+		_, _, toIndex := arrayElement(path, last)
+		if fromIndex || toIndex {
+			return fmt.Errorf("moving array elements is not supported")
+		}
+
+		b.require(from, bson.D{{"$exists", true}})
+
+		if err := b.add("$rename", from, path); err != nil {
+			return err
+		}
+		// The destination is also changed by the rename
+		if b.paths[path] {
+			return fmt.Errorf("field %s is changed more than once", path)
+		}
+		b.paths[path] = true
+
+		return nil
+	case "test":
+		// Conditions are checked against the value before the patch, which only matches applying the operations
+		// in order when no earlier operation changed the path
+		if b.changes(path) {
+			return fmt.Errorf("test of %s follows an operation that changes it", path)
+		}
+
+		// Compare the whole value, so object values aren't read as query operators
+		b.require(path, bson.D{{"$eq", operation.Value.AsInterface()}})
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported JSON Patch operation %q", operation.Op)
+	}
+}
+
+// Apply an update to a value and return its new content
+func (u *MongoKvUpdateServer) apply(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, ref *kvupdatepb.ValueRef, b *updateBuilder, upsert bool) (*structpb.Struct, error) {
+	if ref == nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"a value ref is required",
+			fmt.Errorf("ref not set"),
+		)
+	}
+
+	if len(b.order) == 0 {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"at least one change is required",
+			fmt.Errorf("empty update"),
+		)
+	}
+
+	// Every change advances the revision of the value
+	if err := b.add("$inc", revisionField, int64(1)); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid update",
+			err,
+		)
+	}
+
+	if err := u.kv.timeSeriesErr(newErr, ref.Store); err != nil {
+		return nil, err
+	}
+
+	coll, key, err := u.kv.scopedCollection(ctx, ref.Store, ref.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	id, err := u.kv.storeId(ref.Store, key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid key %s", ref.Key),
+			err,
+		)
+	}
+
+	if err := u.kv.quotas.allow(ctx, ref.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	if upsert {
+		if err := u.kv.collections.ensure(ctx, ref.Store, coll); err != nil {
+			return nil, newErr(
+				codes.FailedPrecondition,
+				fmt.Sprintf("unable to create the collection of %s store", ref.Store),
+				err,
+			)
+		}
+	}
+
+	filter := append(bson.D{{"_id", id}}, b.conditions...)
+
+	session, err := coll.Database().Client().StartSession()
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to start session",
+			err,
+		)
+	}
+	defer session.EndSession(ctx)
+
+	countsUsage := u.kv.quotas.countsUsage(ref.Store)
+	release := func() {}
+
+	// The hash of the new content is stored in the same transaction as the update
+	res, err := session.WithTransaction(ctx, func(txCtx mongo.SessionContext) (interface{}, error) {
+		// The usage counted by an attempt that was retried
+		release()
+		release = func() {}
+
+		var size int64
+		var exists bool
+		if countsUsage {
+			var err error
+			size, exists, err = documentSize(txCtx, coll, id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		doc, err := coll.FindOneAndUpdate(
+			txCtx,
+			filter,
+			b.update(),
+			options.FindOneAndUpdate().SetUpsert(upsert).SetReturnDocument(options.After),
+		).Raw()
+		if err != nil {
+			return nil, err
+		}
+
+		// Fields inside compressed content can't be updated in place, returning aborts the update
+		if _, err := doc.LookupErr(compressedField); err == nil {
+			return nil, errCompressedValue
+		}
+
+		content, err := u.kv.storeContent(ref.Store, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := contentHash(content)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = coll.UpdateOne(txCtx, bson.D{{"_id", id}}, bson.D{{"$set", bson.D{{hashField, hash}}}})
+		if err != nil {
+			return nil, err
+		}
+
+		// Usage is kept in the stack's cluster, so it is counted outside the transaction and released if it aborts
+		if countsUsage {
+			newSize, _, err := documentSize(txCtx, coll, id)
+			if err != nil {
+				return nil, err
+			}
+
+			change := quotaUsage{Bytes: newSize - size}
+			if !exists {
+				change.Documents = 1
+			}
+
+			release, err = u.kv.quotas.count(ctx, ref.Store, change)
+			if err != nil {
+				release = func() {}
+				return nil, err
+			}
+		}
+
+		return content, nil
+	})
+	if err != nil {
+		release()
+	}
+
+	var serverErr mongo.ServerError
+	var exceeded *quotaError
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if len(b.conditions) > 0 {
+			// Tell a failed condition apart from a missing value
+			if count, countErr := coll.CountDocuments(ctx, bson.D{{"_id", id}}); countErr == nil && count > 0 {
+				return nil, newErr(
+					codes.FailedPrecondition,
+					fmt.Sprintf("%s in store %s doesn't match the patch", ref.Key, ref.Store),
+					err,
+				)
+			}
+		}
+
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("key %s not found in store %s", ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &exceeded) {
+		return nil, quotaErr(newErr, err)
+	} else if errors.Is(err, errCompressedValue) {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("%s in store %s is stored compressed, set the whole value instead", ref.Key, ref.Store),
+			err,
+		)
+	} else if mongo.IsDuplicateKeyError(err) {
+		return nil, newErr(
+			codes.AlreadyExists,
+			fmt.Sprintf("unable to update %s in %s store, a value with the same unique index fields exists", ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCannotExtractGeoKeys) {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("unable to update %s in %s store, a geo field isn't valid GeoJSON", ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCappedDocumentSize) {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("unable to update %s in %s store, values of capped stores can only be replaced with values of the same size", ref.Key, ref.Store),
+			err,
+		)
+	} else if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrConflictingUpdateOperators) {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"update changes overlapping fields",
+			err,
+		)
+	} else if errors.As(err, &serverErr) && (serverErr.HasErrorCode(mongoErrTypeMismatch) || serverErr.HasErrorCode(mongoErrPathNotViable) || serverErr.HasErrorCode(mongoErrBadValue)) {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("update doesn't apply to the stored value of %s in store %s", ref.Key, ref.Store),
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to update %s in store %s", ref.Key, ref.Store),
+			err,
+		)
+	}
+
+	// Tenant databases are created by their first write, so they are indexed then
+	if u.kv.tenancy != TenancyModeNone {
+		u.kv.indexes.ensureTenant(ref.Store, coll)
+	}
+
+	if u.kv.cachedStores[ref.Store] {
+		u.kv.cache.invalidate(ref.Store, key)
+	}
+
+	return res.(*structpb.Struct), nil
+}
+
+// Apply update operations to a value in a single atomic write
+func (u *MongoKvUpdateServer) Update(ctx context.Context, req *kvupdatepb.KvUpdateRequest) (*kvupdatepb.KvUpdateResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoKvUpdateServer.Update")
+
+	b := newUpdateBuilder()
+	for _, operation := range req.Operations {
+		if err := b.addOperation(operation); err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid update operation",
+				err,
+			)
+		}
+	}
+
+	content, err := u.apply(ctx, newErr, req.Ref, b, req.Upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kvupdatepb.KvUpdateResponse{
+		Content: content,
+	}, nil
+}
+
+// Apply a JSON Merge Patch (RFC 7386) to a value
+func (u *MongoKvUpdateServer) MergePatch(ctx context.Context, req *kvupdatepb.KvUpdateMergePatchRequest) (*kvupdatepb.KvUpdateMergePatchResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoKvUpdateServer.MergePatch")
+
+	b := newUpdateBuilder()
+	if err := b.addMergePatch("", req.Patch); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid merge patch",
+			err,
+		)
+	}
+
+	content, err := u.apply(ctx, newErr, req.Ref, b, req.Upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kvupdatepb.KvUpdateMergePatchResponse{
+		Content: content,
+	}, nil
+}
+
+// Apply a JSON Patch (RFC 6902) to a value
+func (u *MongoKvUpdateServer) JsonPatch(ctx context.Context, req *kvupdatepb.KvUpdateJsonPatchRequest) (*kvupdatepb.KvUpdateJsonPatchResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoKvUpdateServer.JsonPatch")
+
+	b := newUpdateBuilder()
+	for _, operation := range req.Operations {
+		if err := b.addJsonPatchOperation(operation); err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid JSON Patch operation",
+				err,
+			)
+		}
+	}
+
+	content, err := u.apply(ctx, newErr, req.Ref, b, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kvupdatepb.KvUpdateJsonPatchResponse{
+		Content: content,
+	}, nil
+}
+
+func NewKvUpdate(kv *MongoDBServer) *MongoKvUpdateServer {
+	return &MongoKvUpdateServer{
+		kv: kv,
+	}
+}
@@ -0,0 +1,132 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	kvupdatepb "github.com/nitrictech/mongodb-provider/common/proto/kvupdate/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustValue(t *testing.T, v interface{}) *structpb.Value {
+	t.Helper()
+
+	value, err := structpb.NewValue(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return value
+}
+
+func TestJsonPatchTest(t *testing.T) {
+	b := newUpdateBuilder()
+
+	operations := []*kvupdatepb.JsonPatchOperation{
+		{Op: "test", Path: "/limits", Value: mustValue(t, map[string]interface{}{"$gt": 0})},
+		{Op: "test", Path: "/tags", Value: mustValue(t, []interface{}{"a"})},
+	}
+	for _, operation := range operations {
+		if err := b.addJsonPatchOperation(operation); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Values are compared whole, never read as operators or matched against single elements
+	expected := bson.D{
+		{"limits", bson.D{{"$eq", map[string]interface{}{"$gt": float64(0)}}}},
+		{"tags", bson.D{{"$eq", []interface{}{"a"}}}},
+	}
+	if !reflect.DeepEqual(b.conditions, expected) {
+		t.Fatalf("expected conditions %v, got %v", expected, b.conditions)
+	}
+}
+
+func TestJsonPatchTestOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []*kvupdatepb.JsonPatchOperation
+		valid      bool
+	}{
+		{
+			name: "test before replace",
+			operations: []*kvupdatepb.JsonPatchOperation{
+				{Op: "test", Path: "/status", Value: structpb.NewStringValue("open")},
+				{Op: "replace", Path: "/status", Value: structpb.NewStringValue("closed")},
+			},
+			valid: true,
+		},
+		{
+			name: "test of another field",
+			operations: []*kvupdatepb.JsonPatchOperation{
+				{Op: "replace", Path: "/status", Value: structpb.NewStringValue("closed")},
+				{Op: "test", Path: "/statuses", Value: structpb.NewStringValue("open")},
+			},
+			valid: true,
+		},
+		{
+			name: "test after replace",
+			operations: []*kvupdatepb.JsonPatchOperation{
+				{Op: "replace", Path: "/status", Value: structpb.NewStringValue("closed")},
+				{Op: "test", Path: "/status", Value: structpb.NewStringValue("closed")},
+			},
+		},
+		{
+			name: "test of a member after its parent is added",
+			operations: []*kvupdatepb.JsonPatchOperation{
+				{Op: "add", Path: "/address", Value: structpb.NewStructValue(&structpb.Struct{})},
+				{Op: "test", Path: "/address/city", Value: structpb.NewStringValue("Perth")},
+			},
+		},
+		{
+			name: "test of a parent after a member is removed",
+			operations: []*kvupdatepb.JsonPatchOperation{
+				{Op: "remove", Path: "/address/city"},
+				{Op: "test", Path: "/address", Value: structpb.NewStructValue(&structpb.Struct{})},
+			},
+		},
+		{
+			name: "test of a move destination",
+			operations: []*kvupdatepb.JsonPatchOperation{
+				{Op: "move", From: "/draft", Path: "/status"},
+				{Op: "test", Path: "/status", Value: structpb.NewStringValue("open")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := newUpdateBuilder()
+
+			var err error
+			for _, operation := range test.operations {
+				if err = b.addJsonPatchOperation(operation); err != nil {
+					break
+				}
+			}
+
+			if test.valid && err != nil {
+				t.Fatalf("expected the patch to be valid, got %v", err)
+			} else if !test.valid && err == nil {
+				t.Fatal("expected the patch to be rejected")
+			}
+		})
+	}
+}
+
+func TestUpdateRemove(t *testing.T) {
+	b := newUpdateBuilder()
+
+	err := b.addOperation(&kvupdatepb.UpdateOperation{Operation: &kvupdatepb.UpdateOperation_Remove{
+		Remove: &kvupdatepb.Remove{Path: "items", Value: mustValue(t, map[string]interface{}{"$ne": nil})},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := bson.D{{"$pull", bson.D{{"items", bson.D{{"$eq", map[string]interface{}{"$ne": nil}}}}}}}
+	if !reflect.DeepEqual(b.update(), expected) {
+		t.Fatalf("expected update %v, got %v", expected, b.update())
+	}
+}
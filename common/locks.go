@@ -0,0 +1,338 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	lockspb "github.com/nitrictech/mongodb-provider/common/proto/locks/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultLockTTL = 30 * time.Second
+	// How often a blocked Acquire retries while the lock is held
+	lockRetryInterval = 250 * time.Millisecond
+)
+
+type lockDocument struct {
+	Name         string    `bson:"_id"`
+	Owner        string    `bson:"owner"`
+	FencingToken int64     `bson:"token"`
+	ExpiresAt    time.Time `bson:"expiresAt"`
+}
+
+// MongoLocksServer provides distributed locks stored in the "locks" collection.
+//
+// Expired leases are removed by a TTL index and can be taken over by any owner before then.
+// Fencing tokens are drawn from the "locks.fencing" collection, which is never expired,
+// so they keep increasing across expiry and deletion of the lock document.
+type MongoLocksServer struct {
+	db *mongo.Database
+
+	indexed atomic.Bool
+}
+
+var _ lockspb.LocksServer = &MongoLocksServer{}
+
+func (l *MongoLocksServer) getCollectionHandle() *mongo.Collection {
+	return l.db.Collection("locks")
+}
+
+func (l *MongoLocksServer) getFencingCollectionHandle() *mongo.Collection {
+	return l.db.Collection("locks.fencing")
+}
+
+func (l *MongoLocksServer) ensureIndexes(ctx context.Context) error {
+	if l.indexed.Load() {
+		return nil
+	}
+
+	_, err := l.getCollectionHandle().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"expiresAt", 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	l.indexed.Store(true)
+
+	return nil
+}
+
+func (doc *lockDocument) toLease() *lockspb.Lease {
+	return &lockspb.Lease{
+		Name:         doc.Name,
+		Owner:        doc.Owner,
+		FencingToken: doc.FencingToken,
+		ExpiresAt:    timestamppb.New(doc.ExpiresAt),
+	}
+}
+
+// Reserve the next fencing token for a lock
+func (l *MongoLocksServer) nextFencingToken(ctx context.Context, name string) (int64, error) {
+	var counter struct {
+		Token int64 `bson:"token"`
+	}
+
+	err := l.getFencingCollectionHandle().FindOneAndUpdate(
+		ctx,
+		bson.D{{"_id", name}},
+		bson.D{{"$inc", bson.D{{"token", int64(1)}}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+
+	return counter.Token, err
+}
+
+// The lease duration of a request, a lease that expires as it is taken would let two owners hold the lock
+func lockTTL(ttl *durationpb.Duration) (time.Duration, error) {
+	if ttl == nil {
+		return defaultLockTTL, nil
+	}
+
+	if ttl.AsDuration() <= 0 {
+		return 0, fmt.Errorf("ttl must be positive, got %s", ttl.AsDuration())
+	}
+
+	return ttl.AsDuration(), nil
+}
+
+// Take the lock if it is free, expired or already held by the owner. Returns nil if it is held by another owner.
+func (l *MongoLocksServer) tryAcquire(ctx context.Context, name string, owner string, ttl time.Duration) (*lockDocument, error) {
+	if err := l.ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	token, err := l.nextFencingToken(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// The token guard stops a stale acquirer from taking over with a lower token than the lock has already issued
+	filter := bson.D{
+		{"_id", name},
+		{"$or", bson.A{
+			bson.D{{"expiresAt", bson.D{{"$lte", now}}}},
+			bson.D{{"owner", owner}},
+		}},
+		{"token", bson.D{{"$lt", token}}},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"owner", owner},
+		{"token", token},
+		{"expiresAt", now.Add(ttl)},
+	}}}
+
+	var doc lockDocument
+	err = l.getCollectionHandle().FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	if mongo.IsDuplicateKeyError(err) {
+		// The filter didn't match an existing lock, so it is held by someone else
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// Acquire a lock, waiting until it is available or the wait timeout elapses
+func (l *MongoLocksServer) Acquire(ctx context.Context, req *lockspb.LocksAcquireRequest) (*lockspb.LocksAcquireResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoLocksServer.Acquire")
+
+	if req.Name == "" || req.Owner == "" {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"lock name and owner are required",
+			fmt.Errorf("invalid lock request"),
+		)
+	}
+
+	ttl, err := lockTTL(req.Ttl)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid lock ttl",
+			err,
+		)
+	}
+
+	if req.WaitTimeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.WaitTimeout.AsDuration())
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(lockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		doc, err := l.tryAcquire(ctx, req.Name, req.Owner, ttl)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return nil, newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to acquire lock %s", req.Name),
+				err,
+			)
+		}
+
+		if doc != nil {
+			return &lockspb.LocksAcquireResponse{
+				Lease: doc.toLease(),
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, newErr(
+				codes.DeadlineExceeded,
+				fmt.Sprintf("timed out waiting for lock %s", req.Name),
+				ctx.Err(),
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Acquire a lock only if it is immediately available
+func (l *MongoLocksServer) TryAcquire(ctx context.Context, req *lockspb.LocksTryAcquireRequest) (*lockspb.LocksTryAcquireResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoLocksServer.TryAcquire")
+
+	if req.Name == "" || req.Owner == "" {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"lock name and owner are required",
+			fmt.Errorf("invalid lock request"),
+		)
+	}
+
+	ttl, err := lockTTL(req.Ttl)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid lock ttl",
+			err,
+		)
+	}
+
+	doc, err := l.tryAcquire(ctx, req.Name, req.Owner, ttl)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to acquire lock %s", req.Name),
+			err,
+		)
+	}
+
+	if doc == nil {
+		return &lockspb.LocksTryAcquireResponse{
+			Acquired: false,
+		}, nil
+	}
+
+	return &lockspb.LocksTryAcquireResponse{
+		Acquired: true,
+		Lease:    doc.toLease(),
+	}, nil
+}
+
+// Extend the expiry of a held lock
+func (l *MongoLocksServer) Renew(ctx context.Context, req *lockspb.LocksRenewRequest) (*lockspb.LocksRenewResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoLocksServer.Renew")
+
+	ttl, err := lockTTL(req.Ttl)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid lock ttl",
+			err,
+		)
+	}
+
+	now := time.Now()
+
+	filter := bson.D{
+		{"_id", req.Name},
+		{"owner", req.Owner},
+		{"token", req.FencingToken},
+		{"expiresAt", bson.D{{"$gt", now}}},
+	}
+	update := bson.D{{"$set", bson.D{{"expiresAt", now.Add(ttl)}}}}
+
+	var doc lockDocument
+	err = l.getCollectionHandle().FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("lease on lock %s is no longer held by %s", req.Name, req.Owner),
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to renew lock %s", req.Name),
+			err,
+		)
+	}
+
+	return &lockspb.LocksRenewResponse{
+		Lease: doc.toLease(),
+	}, nil
+}
+
+// Release a held lock
+func (l *MongoLocksServer) Release(ctx context.Context, req *lockspb.LocksReleaseRequest) (*lockspb.LocksReleaseResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoLocksServer.Release")
+
+	filter := bson.D{
+		{"_id", req.Name},
+		{"owner", req.Owner},
+		{"token", req.FencingToken},
+	}
+
+	res, err := l.getCollectionHandle().DeleteOne(ctx, filter)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to release lock %s", req.Name),
+			err,
+		)
+	}
+
+	if res.DeletedCount == 0 {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("lease on lock %s is no longer held by %s", req.Name, req.Owner),
+			fmt.Errorf("lease not found"),
+		)
+	}
+
+	return &lockspb.LocksReleaseResponse{}, nil
+}
+
+func NewLocks(db *mongo.Database) *MongoLocksServer {
+	return &MongoLocksServer{
+		db: db,
+	}
+}
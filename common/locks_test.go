@@ -0,0 +1,202 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lockspb "github.com/nitrictech/mongodb-provider/common/proto/locks/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestLockTTL(t *testing.T) {
+	tests := []struct {
+		name  string
+		ttl   *durationpb.Duration
+		want  time.Duration
+		valid bool
+	}{
+		{name: "default", want: defaultLockTTL, valid: true},
+		{name: "positive", ttl: durationpb.New(time.Minute), want: time.Minute, valid: true},
+		{name: "zero", ttl: durationpb.New(0)},
+		{name: "negative", ttl: durationpb.New(-time.Second)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ttl, err := lockTTL(test.ttl)
+
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected ttl %s to be rejected", test.ttl.AsDuration())
+				}
+				return
+			}
+
+			if err != nil || ttl != test.want {
+				t.Fatalf("expected ttl %s, got %s and %v", test.want, ttl, err)
+			}
+		})
+	}
+}
+
+func TestLocksRejectInvalidTTL(t *testing.T) {
+	// Requests are validated before the cluster is used
+	locks := NewLocks(nil)
+	ctx := context.Background()
+	ttl := durationpb.New(0)
+
+	_, err := locks.Acquire(ctx, &lockspb.LocksAcquireRequest{Name: "jobs", Owner: "a", Ttl: ttl})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected Acquire to be InvalidArgument, got %v", err)
+	}
+
+	_, err = locks.TryAcquire(ctx, &lockspb.LocksTryAcquireRequest{Name: "jobs", Owner: "a", Ttl: ttl})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected TryAcquire to be InvalidArgument, got %v", err)
+	}
+
+	_, err = locks.Renew(ctx, &lockspb.LocksRenewRequest{Name: "jobs", Owner: "a", FencingToken: 1, Ttl: ttl})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected Renew to be InvalidArgument, got %v", err)
+	}
+}
+
+func tryAcquire(t *testing.T, locks *MongoLocksServer, owner string, ttl time.Duration) *lockspb.Lease {
+	t.Helper()
+
+	res, err := locks.TryAcquire(context.Background(), &lockspb.LocksTryAcquireRequest{Name: "jobs", Owner: owner, Ttl: durationpb.New(ttl)})
+	if err != nil {
+		t.Fatalf("unable to try to acquire the lock for %s: %v", owner, err)
+	}
+
+	return res.Lease
+}
+
+func TestLocksContention(t *testing.T) {
+	locks := NewLocks(testDatabase(t))
+	ctx := context.Background()
+
+	held := tryAcquire(t, locks, "a", time.Minute)
+	if held == nil {
+		t.Fatal("expected a to acquire the free lock")
+	}
+
+	if lease := tryAcquire(t, locks, "b", time.Minute); lease != nil {
+		t.Fatalf("expected the lock to be held by a, b got %+v", lease)
+	}
+
+	_, err := locks.Acquire(ctx, &lockspb.LocksAcquireRequest{Name: "jobs", Owner: "b", WaitTimeout: durationpb.New(500 * time.Millisecond)})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected b to time out waiting, got %v", err)
+	}
+
+	// A waiting acquirer takes the lock once it is released
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		_, _ = locks.Release(ctx, &lockspb.LocksReleaseRequest{Name: "jobs", Owner: "a", FencingToken: held.FencingToken})
+	}()
+
+	res, err := locks.Acquire(ctx, &lockspb.LocksAcquireRequest{Name: "jobs", Owner: "b", WaitTimeout: durationpb.New(5 * time.Second)})
+	if err != nil {
+		t.Fatalf("expected b to acquire the released lock, got %v", err)
+	}
+
+	if res.Lease.Owner != "b" || res.Lease.FencingToken <= held.FencingToken {
+		t.Fatalf("expected b to hold the lock with a newer token than %d, got %+v", held.FencingToken, res.Lease)
+	}
+}
+
+func TestLocksExpiry(t *testing.T) {
+	locks := NewLocks(testDatabase(t))
+	ctx := context.Background()
+
+	expired := tryAcquire(t, locks, "a", 500*time.Millisecond)
+	if expired == nil {
+		t.Fatal("expected a to acquire the free lock")
+	}
+
+	time.Sleep(time.Second)
+
+	// The lease can be taken over before the TTL index removes it
+	lease := tryAcquire(t, locks, "b", time.Minute)
+	if lease == nil {
+		t.Fatal("expected b to take over the expired lock")
+	}
+
+	if lease.FencingToken <= expired.FencingToken {
+		t.Fatalf("expected the token to increase from %d, got %d", expired.FencingToken, lease.FencingToken)
+	}
+
+	_, err := locks.Renew(ctx, &lockspb.LocksRenewRequest{Name: "jobs", Owner: "a", FencingToken: expired.FencingToken})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected renewing the expired lease to fail, got %v", err)
+	}
+
+	renewed, err := locks.Renew(ctx, &lockspb.LocksRenewRequest{Name: "jobs", Owner: "b", FencingToken: lease.FencingToken, Ttl: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatalf("unable to renew the held lease: %v", err)
+	}
+
+	if !renewed.Lease.ExpiresAt.AsTime().After(lease.ExpiresAt.AsTime()) || renewed.Lease.FencingToken != lease.FencingToken {
+		t.Fatalf("expected the lease to be extended with the same token, got %+v", renewed.Lease)
+	}
+}
+
+func TestLocksFencingTokens(t *testing.T) {
+	locks := NewLocks(testDatabase(t))
+	ctx := context.Background()
+
+	previous := int64(0)
+	for _, owner := range []string{"a", "b", "a", "a"} {
+		lease := tryAcquire(t, locks, owner, time.Minute)
+		if lease == nil {
+			t.Fatalf("expected %s to acquire the lock", owner)
+		}
+
+		if lease.FencingToken <= previous {
+			t.Fatalf("expected the token to increase from %d, got %d", previous, lease.FencingToken)
+		}
+		previous = lease.FencingToken
+
+		// Tokens keep increasing after the lock document is removed
+		_, err := locks.Release(ctx, &lockspb.LocksReleaseRequest{Name: "jobs", Owner: owner, FencingToken: lease.FencingToken})
+		if err != nil {
+			t.Fatalf("unable to release the lock for %s: %v", owner, err)
+		}
+	}
+}
+
+func TestLocksRelease(t *testing.T) {
+	locks := NewLocks(testDatabase(t))
+	ctx := context.Background()
+
+	lease := tryAcquire(t, locks, "a", time.Minute)
+	if lease == nil {
+		t.Fatal("expected a to acquire the free lock")
+	}
+
+	_, err := locks.Release(ctx, &lockspb.LocksReleaseRequest{Name: "jobs", Owner: "b", FencingToken: lease.FencingToken})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected a release by another owner to fail, got %v", err)
+	}
+
+	_, err = locks.Release(ctx, &lockspb.LocksReleaseRequest{Name: "jobs", Owner: "a", FencingToken: lease.FencingToken - 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected a release with a stale token to fail, got %v", err)
+	}
+
+	if other := tryAcquire(t, locks, "b", time.Minute); other != nil {
+		t.Fatal("expected the failed releases to leave the lock held by a")
+	}
+
+	if _, err := locks.Release(ctx, &lockspb.LocksReleaseRequest{Name: "jobs", Owner: "a", FencingToken: lease.FencingToken}); err != nil {
+		t.Fatalf("unable to release the held lock: %v", err)
+	}
+
+	if other := tryAcquire(t, locks, "b", time.Minute); other == nil {
+		t.Fatal("expected b to acquire the released lock")
+	}
+}
@@ -0,0 +1,153 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nitrictech/mongodb-provider/common/env"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// MetricsReporter is a meter provider that totals the runtime's counters and logs them on an interval.
+//
+// It is registered as the global meter provider, so the cache and compression counters are recorded
+// without an OpenTelemetry SDK. Instruments other than int64 counters are no-ops.
+type MetricsReporter struct {
+	embedded.MeterProvider
+
+	interval time.Duration
+
+	mu sync.Mutex
+	// Totals by counter name and attributes, e.g. kvstore.cache.hits{store=orders}
+	totals map[string]int64
+	// Totals as of the last report, so unchanged counters aren't logged again
+	reported map[string]int64
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+var _ metric.MeterProvider = &MetricsReporter{}
+
+type reporterMeter struct {
+	noop.Meter
+
+	reporter *MetricsReporter
+}
+
+func (m *reporterMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &reporterCounter{name: name, reporter: m.reporter}, nil
+}
+
+type reporterCounter struct {
+	embedded.Int64Counter
+
+	name     string
+	reporter *MetricsReporter
+}
+
+func (c *reporterCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	key := fmt.Sprintf("%s{%s}", c.name, attrs.Encoded(attribute.DefaultEncoder()))
+
+	c.reporter.mu.Lock()
+	defer c.reporter.mu.Unlock()
+
+	c.reporter.totals[key] += incr
+}
+
+func (r *MetricsReporter) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return &reporterMeter{reporter: r}
+}
+
+// The current total of a counter with the attributes, e.g. kvstore.cache.hits{store=orders}
+func (r *MetricsReporter) Total(key string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.totals[key]
+}
+
+// Log the totals of counters that changed since the last report
+func (r *MetricsReporter) report() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := []string{}
+	for key, total := range r.totals {
+		if r.reported[key] != total {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		logger.Infof("metric %s: %d", key, r.totals[key])
+		r.reported[key] = r.totals[key]
+	}
+}
+
+// Register the reporter as the global meter provider and log the totals on its interval
+func (r *MetricsReporter) Start() {
+	otel.SetMeterProvider(r)
+
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.report()
+			}
+		}
+	}()
+}
+
+// Stop reporting, logging the final totals
+func (r *MetricsReporter) Stop() {
+	close(r.stop)
+	r.done.Wait()
+
+	r.report()
+}
+
+// Create a reporter from the runtime's MONGO_METRICS_INTERVAL, nil when reporting is disabled
+func NewMetricsReporter() (*MetricsReporter, error) {
+	interval, err := time.ParseDuration(env.MONGO_METRICS_INTERVAL.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid MONGO_METRICS_INTERVAL: %w", err)
+	}
+
+	if interval < 0 {
+		return nil, fmt.Errorf("invalid MONGO_METRICS_INTERVAL: must not be negative, got %s", interval)
+	}
+
+	if interval == 0 {
+		return nil, nil
+	}
+
+	return newMetricsReporter(interval), nil
+}
+
+func newMetricsReporter(interval time.Duration) *MetricsReporter {
+	return &MetricsReporter{
+		interval: interval,
+		totals:   map[string]int64{},
+		reported: map[string]int64{},
+		stop:     make(chan struct{}),
+	}
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestMetricsReporterTotals(t *testing.T) {
+	ctx := context.Background()
+	reporter := newMetricsReporter(time.Hour)
+
+	counter, err := reporter.Meter("test").Int64Counter("requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("store", "orders")))
+	counter.Add(ctx, 3, metric.WithAttributes(attribute.String("store", "orders")))
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("store", "users")))
+
+	if total := reporter.Total("requests{store=orders}"); total != 5 {
+		t.Fatalf("expected 5 orders requests, got %d", total)
+	}
+
+	if total := reporter.Total("requests{store=users}"); total != 1 {
+		t.Fatalf("expected 1 users request, got %d", total)
+	}
+
+	reporter.report()
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("store", "users")))
+
+	// Only counters that changed since the last report are reported again
+	changed := 0
+	for key, total := range reporter.totals {
+		if reporter.reported[key] != total {
+			changed++
+		}
+	}
+
+	if changed != 1 {
+		t.Fatalf("expected only the users counter to have changed, got %d", changed)
+	}
+}
+
+func TestMetricsReporterCache(t *testing.T) {
+	ctx := context.Background()
+	reporter := newMetricsReporter(time.Hour)
+	otel.SetMeterProvider(reporter)
+
+	cache := newValueCache(1 << 20)
+	for i := 0; i < 3; i++ {
+		if _, err := cache.get(ctx, "orders", "a", countingLoad(testContent(t, "v"), new(int))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hits, misses := reporter.Total("kvstore.cache.hits{store=orders}"), reporter.Total("kvstore.cache.misses{store=orders}"); hits != 2 || misses != 1 {
+		t.Fatalf("expected 2 hits and 1 miss, got %d hits and %d misses", hits, misses)
+	}
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc"
+)
+
+// A database of its own on the cluster at MONGO_TEST_URI, which is dropped when the test ends.
+// Tests that need a cluster are skipped when it isn't set, `make test-mongo` runs them against the Atlas local container.
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI isn't set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("unable to connect to %s: %v", uri, err)
+	}
+
+	db := client.Database(fmt.Sprintf("test-%d", time.Now().UnixNano()))
+
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+
+	return db
+}
+
+// A key value server of the stores in a test database, see testDatabase
+func testServer(t *testing.T, opts ...Option) *MongoDBServer {
+	t.Helper()
+
+	db := testDatabase(t)
+
+	server, err := NewWithOptions(context.Background(), append([]Option{WithClient(db.Client()), WithDatabase(db.Name())}, opts...)...)
+	if err != nil {
+		t.Fatalf("unable to create the server: %v", err)
+	}
+
+	return server
+}
+
+// A server stream that keeps the responses it is sent
+type testStream[T any] struct {
+	grpc.ServerStream
+
+	sent []T
+}
+
+func (s *testStream[T]) Send(res T) error {
+	s.sent = append(s.sent, res)
+	return nil
+}
+
+func (s *testStream[T]) Context() context.Context {
+	return context.Background()
+}
@@ -0,0 +1,515 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	outboxpb "github.com/nitrictech/mongodb-provider/common/proto/outbox/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	topicspb "github.com/nitrictech/nitric/core/pkg/proto/topics/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// The payload field subscribers receive the outbox deduplication id in
+const DeduplicationIdField = "x-deduplication-id"
+
+const (
+	// How often the relay checks for unpublished events
+	outboxPollInterval = time.Second
+	// How long a relay holds an event while publishing it before another relay may retry it
+	outboxClaimTimeout = 30 * time.Second
+	// How long published events are kept before being removed
+	outboxRetention = 24 * time.Hour
+)
+
+type outboxDocument struct {
+	DeduplicationId string    `bson:"_id"`
+	Topic           string    `bson:"topic"`
+	Payload         []byte    `bson:"payload"`
+	CreatedAt       time.Time `bson:"createdAt"`
+	// Position of the event within its commit, events of a commit share createdAt
+	Position     int        `bson:"position"`
+	ClaimedUntil time.Time  `bson:"claimedUntil"`
+	Attempts     int        `bson:"attempts"`
+	PublishedAt  *time.Time `bson:"publishedAt,omitempty"`
+}
+
+func getOutboxCollectionHandle(db *mongo.Database) *mongo.Collection {
+	return db.Collection("outbox")
+}
+
+// MongoOutboxServer commits key value writes and their events in one transaction,
+// the events are stored in the "outbox" collection until an OutboxRelay publishes them.
+//
+// The transaction can only span the stack's cluster, so writes to stores routed to a target are refused,
+// as are writes with tenancy enabled.
+type MongoOutboxServer struct {
+	kv *MongoDBServer
+}
+
+var _ outboxpb.OutboxServer = &MongoOutboxServer{}
+
+// outboxWrite is a key value write resolved to its document
+type outboxWrite struct {
+	store string
+	key   string
+	id    interface{}
+	coll  *mongo.Collection
+	// The fields the value is stored as and the update that sets them, both nil for deletes
+	stored map[string]interface{}
+	update mongo.Pipeline
+}
+
+// Resolve a write to the document it changes in the stack's cluster
+func (o *MongoOutboxServer) resolve(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, write *outboxpb.OutboxWrite) (*outboxWrite, error) {
+	resolved := &outboxWrite{}
+
+	var content *structpb.Struct
+	switch w := write.GetWrite().(type) {
+	case *outboxpb.OutboxWrite_Set:
+		resolved.store, resolved.key, content = w.Set.Store, w.Set.Key, w.Set.Content
+	case *outboxpb.OutboxWrite_Delete:
+		resolved.store, resolved.key = w.Delete.Store, w.Delete.Key
+
+		if err := o.kv.cappedDeleteErr(newErr, resolved.store); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, newErr(
+			codes.InvalidArgument,
+			"outbox writes require a set or delete",
+			fmt.Errorf("unknown outbox write %T", w),
+		)
+	}
+
+	if err := o.kv.timeSeriesErr(newErr, resolved.store); err != nil {
+		return nil, err
+	}
+
+	if _, ok := o.kv.storeDatabases[resolved.store]; ok {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("store %s is routed to a target, outbox writes must be to stores in the stack's cluster", resolved.store),
+			fmt.Errorf("routed store"),
+		)
+	}
+
+	id, err := o.kv.storeId(resolved.store, resolved.key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid key %s", resolved.key),
+			err,
+		)
+	}
+	resolved.id = id
+	resolved.coll = o.kv.getCollectionHandle(resolved.store)
+
+	if err := o.kv.quotas.allow(ctx, resolved.store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	if content == nil {
+		return resolved, nil
+	}
+
+	if err := o.kv.collections.ensure(ctx, resolved.store, resolved.coll); err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("unable to create the collection of %s store", resolved.store),
+			err,
+		)
+	}
+
+	resolved.stored, err = o.kv.compressor.stored(ctx, resolved.store, content)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to compress value content",
+			err,
+		)
+	}
+
+	resolved.update, err = setStoredValueUpdate(content, resolved.stored)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("unable to hash %s content", resolved.key),
+			err,
+		)
+	}
+
+	return resolved, nil
+}
+
+// Apply key value writes and record events in a single transaction
+func (o *MongoOutboxServer) Commit(ctx context.Context, req *outboxpb.OutboxCommitRequest) (*outboxpb.OutboxCommitResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoOutboxServer.Commit")
+
+	if o.kv.tenancy != TenancyModeNone {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			"the outbox can't be used with tenancy enabled",
+			fmt.Errorf("tenancy mode %s", o.kv.tenancy),
+		)
+	}
+
+	now := time.Now()
+
+	events := make([]interface{}, 0, len(req.Events))
+	deduplicationIds := make([]string, 0, len(req.Events))
+	for i, event := range req.Events {
+		if event.Topic == "" {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"outbox events require a topic",
+				fmt.Errorf("missing topic"),
+			)
+		}
+
+		payload, err := proto.Marshal(event.Payload)
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"unable to marshal event payload",
+				err,
+			)
+		}
+
+		deduplicationId := event.DeduplicationId
+		if deduplicationId == "" {
+			deduplicationId = primitive.NewObjectID().Hex()
+		}
+
+		events = append(events, outboxDocument{
+			DeduplicationId: deduplicationId,
+			Topic:           event.Topic,
+			Payload:         payload,
+			CreatedAt:       now,
+			Position:        i,
+			ClaimedUntil:    now,
+		})
+		deduplicationIds = append(deduplicationIds, deduplicationId)
+	}
+
+	writes := make([]*outboxWrite, 0, len(req.Writes))
+	for _, write := range req.Writes {
+		resolved, err := o.resolve(ctx, newErr, write)
+		if err != nil {
+			return nil, err
+		}
+
+		writes = append(writes, resolved)
+	}
+
+	// Quota usage is kept outside the transaction, so it is reserved first and released if the commit fails
+	releases := []func(){}
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+
+	for _, write := range writes {
+		r, err := o.kv.quotas.reserve(ctx, write.coll, write.store, write.id, write.stored)
+		if err != nil {
+			release()
+			return nil, quotaErr(newErr, err)
+		}
+
+		releases = append(releases, r)
+	}
+
+	db := o.kv.Database()
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		release()
+		return nil, newErr(
+			codes.Internal,
+			"unable to start session",
+			err,
+		)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(txCtx mongo.SessionContext) (interface{}, error) {
+		for _, write := range writes {
+			if write.update == nil {
+				_, err := write.coll.DeleteOne(txCtx, bson.D{{"_id", write.id}})
+				if err != nil {
+					return nil, fmt.Errorf("unable to delete %s from %s store: %w", write.key, write.store, err)
+				}
+
+				continue
+			}
+
+			_, err := write.coll.UpdateOne(
+				txCtx,
+				bson.D{{"_id", write.id}},
+				write.update,
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to set %s in %s store: %w", write.key, write.store, err)
+			}
+		}
+
+		if len(events) > 0 {
+			if _, err := getOutboxCollectionHandle(db).InsertMany(txCtx, events); err != nil {
+				return nil, fmt.Errorf("unable to record events: %w", err)
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		release()
+	}
+
+	var serverErr mongo.ServerError
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, newErr(
+			codes.AlreadyExists,
+			"an event with the same deduplication id has already been recorded",
+			err,
+		)
+	} else if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCappedDocumentSize) {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			"values of capped stores can only be replaced with values of the same size",
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to commit outbox transaction",
+			err,
+		)
+	}
+
+	for _, write := range writes {
+		if o.kv.cachedStores[write.store] {
+			o.kv.cache.invalidate(write.store, write.key)
+		}
+	}
+
+	return &outboxpb.OutboxCommitResponse{
+		DeduplicationIds: deduplicationIds,
+	}, nil
+}
+
+func NewOutbox(kv *MongoDBServer) *MongoOutboxServer {
+	return &MongoOutboxServer{
+		kv: kv,
+	}
+}
+
+// OutboxRelay publishes events recorded in the outbox to their topics.
+//
+// Delivery is at-least-once, an event may be published again if a relay fails before marking it as published,
+// subscribers can use the DeduplicationIdField of the payload to discard repeats.
+//
+// Events of a topic are published in the order they were committed, only the oldest unpublished event of each topic
+// can be claimed. An event that fails to publish holds back the later events of its topic until it is published,
+// while other topics carry on.
+type OutboxRelay struct {
+	db     *mongo.Database
+	topics topicspb.TopicsServer
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func (r *OutboxRelay) ensureIndexes(ctx context.Context) error {
+	_, err := getOutboxCollectionHandle(r.db).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"publishedAt", 1}, {"createdAt", 1}, {"position", 1}}},
+		// Published events are only kept long enough to diagnose delivery
+		{Keys: bson.D{{"publishedAt", 1}}, Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds()))},
+	})
+
+	return err
+}
+
+// Find the oldest unpublished event of a topic that isn't being published by another relay
+func (r *OutboxRelay) next(ctx context.Context, now time.Time) (string, error) {
+	cursor, err := getOutboxCollectionHandle(r.db).Aggregate(ctx, mongo.Pipeline{
+		{{"$match", bson.D{{"publishedAt", nil}}}},
+		{{"$sort", bson.D{{"createdAt", 1}, {"position", 1}}}},
+		// The head of each topic, later events wait for it even while it is claimed
+		{{"$group", bson.D{
+			{"_id", "$topic"},
+			{"id", bson.D{{"$first", "$_id"}}},
+			{"claimedUntil", bson.D{{"$first", "$claimedUntil"}}},
+			{"createdAt", bson.D{{"$first", "$createdAt"}}},
+			{"position", bson.D{{"$first", "$position"}}},
+		}}},
+		{{"$match", bson.D{{"claimedUntil", bson.D{{"$lte", now}}}}}},
+		{{"$sort", bson.D{{"createdAt", 1}, {"position", 1}}}},
+		{{"$limit", 1}},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return "", cursor.Err()
+	}
+
+	var head struct {
+		Id string `bson:"id"`
+	}
+	if err := cursor.Decode(&head); err != nil {
+		return "", err
+	}
+
+	return head.Id, nil
+}
+
+// Claim the oldest event that can be published, returns nil when every topic is drained or held back
+func (r *OutboxRelay) claim(ctx context.Context) (*outboxDocument, error) {
+	now := time.Now()
+
+	id, err := r.next(ctx, now)
+	if err != nil || id == "" {
+		return nil, err
+	}
+
+	// Another relay may have claimed the event since it was found, it is left to that relay
+	filter := bson.D{
+		{"_id", id},
+		{"publishedAt", nil},
+		{"claimedUntil", bson.D{{"$lte", now}}},
+	}
+	update := bson.D{
+		{"$set", bson.D{{"claimedUntil", now.Add(outboxClaimTimeout)}}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	}
+
+	var doc outboxDocument
+	err = getOutboxCollectionHandle(r.db).FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, doc *outboxDocument) error {
+	payload := &structpb.Struct{}
+	if err := proto.Unmarshal(doc.Payload, payload); err != nil {
+		return err
+	}
+
+	if payload.Fields == nil {
+		payload.Fields = map[string]*structpb.Value{}
+	}
+	payload.Fields[DeduplicationIdField] = structpb.NewStringValue(doc.DeduplicationId)
+
+	_, err := r.topics.Publish(ctx, &topicspb.TopicPublishRequest{
+		TopicName: doc.Topic,
+		Message: &topicspb.TopicMessage{
+			Content: &topicspb.TopicMessage_StructPayload{
+				StructPayload: payload,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = getOutboxCollectionHandle(r.db).UpdateOne(
+		ctx,
+		bson.D{{"_id", doc.DeduplicationId}},
+		bson.D{{"$set", bson.D{{"publishedAt", time.Now()}}}},
+	)
+
+	return err
+}
+
+// Publish events until the outbox is drained
+func (r *OutboxRelay) drain(ctx context.Context) {
+	for {
+		doc, err := r.claim(ctx)
+		if err != nil {
+			logger.Errorf("outbox relay: unable to claim event: %v", err)
+			return
+		}
+
+		if doc == nil {
+			return
+		}
+
+		// A failed event stays claimed until the claim times out, which spaces out retries and holds back its topic
+		if err := r.publish(ctx, doc); err != nil {
+			logger.Errorf("outbox relay: unable to publish event %s to topic %s (attempt %d): %v", doc.DeduplicationId, doc.Topic, doc.Attempts, err)
+		}
+
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+	}
+}
+
+// Start publishing events in the background
+func (r *OutboxRelay) Start() error {
+	ctx := context.Background()
+
+	if err := r.ensureIndexes(ctx); err != nil {
+		return err
+	}
+
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			r.drain(ctx)
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop the relay, waiting for an in-flight publish to finish
+func (r *OutboxRelay) Stop() {
+	close(r.stop)
+	r.done.Wait()
+}
+
+func NewOutboxRelay(db *mongo.Database, topics topicspb.TopicsServer) *OutboxRelay {
+	return &OutboxRelay{
+		db:     db,
+		topics: topics,
+		stop:   make(chan struct{}),
+	}
+}
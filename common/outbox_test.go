@@ -0,0 +1,153 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	outboxpb "github.com/nitrictech/mongodb-provider/common/proto/outbox/v1"
+	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
+	topicspb "github.com/nitrictech/nitric/core/pkg/proto/topics/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Records the deduplication ids of published events, failing the given number of attempts of an event first
+type testTopics struct {
+	failures  map[string]int
+	published []string
+}
+
+func (t *testTopics) Publish(ctx context.Context, req *topicspb.TopicPublishRequest) (*topicspb.TopicPublishResponse, error) {
+	id := req.Message.GetStructPayload().Fields[DeduplicationIdField].GetStringValue()
+
+	if t.failures[id] > 0 {
+		t.failures[id]--
+		return nil, fmt.Errorf("topic %s is unavailable", req.TopicName)
+	}
+
+	t.published = append(t.published, id)
+
+	return &topicspb.TopicPublishResponse{}, nil
+}
+
+func outboxSet(t *testing.T, store string, key string, content map[string]interface{}) *outboxpb.OutboxWrite {
+	t.Helper()
+
+	value, err := structpb.NewStruct(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &outboxpb.OutboxWrite{Write: &outboxpb.OutboxWrite_Set{Set: &outboxpb.OutboxSetValue{Store: store, Key: key, Content: value}}}
+}
+
+func outboxEvent(topic string, id string) *outboxpb.OutboxEvent {
+	return &outboxpb.OutboxEvent{Topic: topic, DeduplicationId: id, Payload: &structpb.Struct{}}
+}
+
+func TestOutboxCommit(t *testing.T) {
+	server := testServer(t)
+	outbox := NewOutbox(server)
+	ctx := context.Background()
+
+	_, err := outbox.Commit(ctx, &outboxpb.OutboxCommitRequest{
+		Writes: []*outboxpb.OutboxWrite{outboxSet(t, "orders", "1", map[string]interface{}{"status": "paid"})},
+		Events: []*outboxpb.OutboxEvent{outboxEvent("orders", "paid-1")},
+	})
+	if err != nil {
+		t.Fatalf("unable to commit: %v", err)
+	}
+
+	value, err := server.GetValue(ctx, &kvstorepb.KvStoreGetValueRequest{Ref: &kvstorepb.ValueRef{Store: "orders", Key: "1"}})
+	if err != nil {
+		t.Fatalf("expected the committed value, got %v", err)
+	}
+
+	if value.Value.Content.Fields["status"].GetStringValue() != "paid" {
+		t.Fatalf("unexpected committed value %v", value.Value.Content)
+	}
+
+	// A commit whose event can't be recorded doesn't store its writes
+	_, err = outbox.Commit(ctx, &outboxpb.OutboxCommitRequest{
+		Writes: []*outboxpb.OutboxWrite{outboxSet(t, "orders", "2", map[string]interface{}{"status": "paid"})},
+		Events: []*outboxpb.OutboxEvent{outboxEvent("orders", "paid-1")},
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected a repeated deduplication id to be AlreadyExists, got %v", err)
+	}
+
+	_, err = server.GetValue(ctx, &kvstorepb.KvStoreGetValueRequest{Ref: &kvstorepb.ValueRef{Store: "orders", Key: "2"}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected the write of the failed commit not to be stored, got %v", err)
+	}
+
+	count, err := getOutboxCollectionHandle(server.Database()).CountDocuments(ctx, bson.D{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected one recorded event, got %d", count)
+	}
+}
+
+func TestOutboxRelay(t *testing.T) {
+	server := testServer(t)
+	outbox := NewOutbox(server)
+	ctx := context.Background()
+
+	commits := [][]*outboxpb.OutboxEvent{
+		{outboxEvent("orders", "paid-1"), outboxEvent("orders", "shipped-1")},
+		{outboxEvent("emails", "receipt-1")},
+	}
+	for _, events := range commits {
+		if _, err := outbox.Commit(ctx, &outboxpb.OutboxCommitRequest{Events: events}); err != nil {
+			t.Fatalf("unable to commit: %v", err)
+		}
+	}
+
+	topics := &testTopics{failures: map[string]int{"paid-1": 1}}
+	relay := NewOutboxRelay(server.Database(), topics)
+	if err := relay.ensureIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed event holds back the rest of its topic, but not other topics
+	relay.drain(ctx)
+
+	if fmt.Sprint(topics.published) != "[receipt-1]" {
+		t.Fatalf("expected only receipt-1 to be published, got %v", topics.published)
+	}
+
+	// Retry once the failed claim times out
+	_, err := getOutboxCollectionHandle(server.Database()).UpdateOne(ctx, bson.D{{"_id", "paid-1"}}, bson.D{{"$set", bson.D{{"claimedUntil", time.Now()}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relay.drain(ctx)
+
+	if fmt.Sprint(topics.published) != "[receipt-1 paid-1 shipped-1]" {
+		t.Fatalf("expected paid-1 to be redelivered before shipped-1, got %v", topics.published)
+	}
+
+	var doc outboxDocument
+	if err := getOutboxCollectionHandle(server.Database()).FindOne(ctx, bson.D{{"_id", "paid-1"}}).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Attempts != 2 || doc.PublishedAt == nil {
+		t.Fatalf("expected paid-1 to be published on the second attempt, got %+v", doc)
+	}
+
+	// Nothing is published twice
+	relay.drain(ctx)
+
+	if len(topics.published) != 3 {
+		t.Fatalf("expected no more events to be published, got %v", topics.published)
+	}
+}
@@ -0,0 +1,427 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/capped/v1/capped.proto
+
+package cappedpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A value of a capped store
+type CappedEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key of the value
+	Key     string           `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Content *structpb.Struct `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *CappedEntry) Reset() {
+	*x = CappedEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CappedEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CappedEntry) ProtoMessage() {}
+
+func (x *CappedEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CappedEntry.ProtoReflect.Descriptor instead.
+func (*CappedEntry) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_capped_v1_capped_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CappedEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CappedEntry) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type CappedReadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The capped store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// Read the values after this key, from the oldest when empty or when the key has been dropped
+	After string `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	// The maximum number of values to return, defaults to 100 and can be at most 1000
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The tenant to read in key tenancy mode
+	Tenant string `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+}
+
+func (x *CappedReadRequest) Reset() {
+	*x = CappedReadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CappedReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CappedReadRequest) ProtoMessage() {}
+
+func (x *CappedReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CappedReadRequest.ProtoReflect.Descriptor instead.
+func (*CappedReadRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_capped_v1_capped_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CappedReadRequest) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *CappedReadRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *CappedReadRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *CappedReadRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type CappedReadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*CappedEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *CappedReadResponse) Reset() {
+	*x = CappedReadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CappedReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CappedReadResponse) ProtoMessage() {}
+
+func (x *CappedReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CappedReadResponse.ProtoReflect.Descriptor instead.
+func (*CappedReadResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_capped_v1_capped_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CappedReadResponse) GetEntries() []*CappedEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type CappedTailRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The capped store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// Stream the values after this key, from the oldest when empty or when the key has been dropped
+	After string `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	// The tenant to read in key tenancy mode
+	Tenant string `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+}
+
+func (x *CappedTailRequest) Reset() {
+	*x = CappedTailRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CappedTailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CappedTailRequest) ProtoMessage() {}
+
+func (x *CappedTailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_capped_v1_capped_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CappedTailRequest.ProtoReflect.Descriptor instead.
+func (*CappedTailRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_capped_v1_capped_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CappedTailRequest) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *CappedTailRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *CappedTailRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+var File_mongo_proto_capped_v1_capped_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_capped_v1_capped_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61,
+	0x70, 0x70, 0x65, 0x64, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x52, 0x0a, 0x0b, 0x43, 0x61, 0x70,
+	0x70, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x6d, 0x0a,
+	0x11, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0x52, 0x0a, 0x12,
+	0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x70, 0x70,
+	0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x22, 0x57, 0x0a, 0x11, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x54, 0x61, 0x69, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x32, 0xbd, 0x01, 0x0a, 0x06, 0x43, 0x61,
+	0x70, 0x70, 0x65, 0x64, 0x12, 0x5b, 0x0a, 0x04, 0x52, 0x65, 0x61, 0x64, 0x12, 0x28, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x61, 0x70, 0x70, 0x65,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x56, 0x0a, 0x04, 0x54, 0x61, 0x69, 0x6c, 0x12, 0x28, 0x2e, 0x6d, 0x6f, 0x6e, 0x67,
+	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x54, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x70, 0x70,
+	0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x30, 0x01, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x69, 0x74, 0x72, 0x69, 0x63, 0x74, 0x65,
+	0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64, 0x62, 0x2d, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x2f, 0x76, 0x31, 0x3b, 0x63, 0x61, 0x70, 0x70, 0x65,
+	0x64, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mongo_proto_capped_v1_capped_proto_rawDescOnce sync.Once
+	file_mongo_proto_capped_v1_capped_proto_rawDescData = file_mongo_proto_capped_v1_capped_proto_rawDesc
+)
+
+func file_mongo_proto_capped_v1_capped_proto_rawDescGZIP() []byte {
+	file_mongo_proto_capped_v1_capped_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_capped_v1_capped_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_capped_v1_capped_proto_rawDescData)
+	})
+	return file_mongo_proto_capped_v1_capped_proto_rawDescData
+}
+
+var file_mongo_proto_capped_v1_capped_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_mongo_proto_capped_v1_capped_proto_goTypes = []interface{}{
+	(*CappedEntry)(nil),        // 0: mongo.proto.capped.v1.CappedEntry
+	(*CappedReadRequest)(nil),  // 1: mongo.proto.capped.v1.CappedReadRequest
+	(*CappedReadResponse)(nil), // 2: mongo.proto.capped.v1.CappedReadResponse
+	(*CappedTailRequest)(nil),  // 3: mongo.proto.capped.v1.CappedTailRequest
+	(*structpb.Struct)(nil),    // 4: google.protobuf.Struct
+}
+var file_mongo_proto_capped_v1_capped_proto_depIdxs = []int32{
+	4, // 0: mongo.proto.capped.v1.CappedEntry.content:type_name -> google.protobuf.Struct
+	0, // 1: mongo.proto.capped.v1.CappedReadResponse.entries:type_name -> mongo.proto.capped.v1.CappedEntry
+	1, // 2: mongo.proto.capped.v1.Capped.Read:input_type -> mongo.proto.capped.v1.CappedReadRequest
+	3, // 3: mongo.proto.capped.v1.Capped.Tail:input_type -> mongo.proto.capped.v1.CappedTailRequest
+	2, // 4: mongo.proto.capped.v1.Capped.Read:output_type -> mongo.proto.capped.v1.CappedReadResponse
+	0, // 5: mongo.proto.capped.v1.Capped.Tail:output_type -> mongo.proto.capped.v1.CappedEntry
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_capped_v1_capped_proto_init() }
+func file_mongo_proto_capped_v1_capped_proto_init() {
+	if File_mongo_proto_capped_v1_capped_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_capped_v1_capped_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CappedEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_capped_v1_capped_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CappedReadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_capped_v1_capped_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CappedReadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_capped_v1_capped_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CappedTailRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_capped_v1_capped_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_capped_v1_capped_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_capped_v1_capped_proto_depIdxs,
+		MessageInfos:      file_mongo_proto_capped_v1_capped_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_capped_v1_capped_proto = out.File
+	file_mongo_proto_capped_v1_capped_proto_rawDesc = nil
+	file_mongo_proto_capped_v1_capped_proto_goTypes = nil
+	file_mongo_proto_capped_v1_capped_proto_depIdxs = nil
+}
@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/capped/v1/capped.proto
+
+package cappedpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Capped_Read_FullMethodName = "/mongo.proto.capped.v1.Capped/Read"
+	Capped_Tail_FullMethodName = "/mongo.proto.capped.v1.Capped/Tail"
+)
+
+// CappedClient is the client API for Capped service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CappedClient interface {
+	// Read a page of values, oldest first
+	Read(ctx context.Context, in *CappedReadRequest, opts ...grpc.CallOption) (*CappedReadResponse, error)
+	// Stream the values after a key, then each new key as it is set
+	Tail(ctx context.Context, in *CappedTailRequest, opts ...grpc.CallOption) (Capped_TailClient, error)
+}
+
+type cappedClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCappedClient(cc grpc.ClientConnInterface) CappedClient {
+	return &cappedClient{cc}
+}
+
+func (c *cappedClient) Read(ctx context.Context, in *CappedReadRequest, opts ...grpc.CallOption) (*CappedReadResponse, error) {
+	out := new(CappedReadResponse)
+	err := c.cc.Invoke(ctx, Capped_Read_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cappedClient) Tail(ctx context.Context, in *CappedTailRequest, opts ...grpc.CallOption) (Capped_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Capped_ServiceDesc.Streams[0], Capped_Tail_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cappedTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Capped_TailClient interface {
+	Recv() (*CappedEntry, error)
+	grpc.ClientStream
+}
+
+type cappedTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *cappedTailClient) Recv() (*CappedEntry, error) {
+	m := new(CappedEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CappedServer is the server API for Capped service.
+// All implementations should embed UnimplementedCappedServer
+// for forward compatibility
+type CappedServer interface {
+	// Read a page of values, oldest first
+	Read(context.Context, *CappedReadRequest) (*CappedReadResponse, error)
+	// Stream the values after a key, then each new key as it is set
+	Tail(*CappedTailRequest, Capped_TailServer) error
+}
+
+// UnimplementedCappedServer should be embedded to have forward compatible implementations.
+type UnimplementedCappedServer struct {
+}
+
+func (UnimplementedCappedServer) Read(context.Context, *CappedReadRequest) (*CappedReadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
+}
+func (UnimplementedCappedServer) Tail(*CappedTailRequest, Capped_TailServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
+
+// UnsafeCappedServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CappedServer will
+// result in compilation errors.
+type UnsafeCappedServer interface {
+	mustEmbedUnimplementedCappedServer()
+}
+
+func RegisterCappedServer(s grpc.ServiceRegistrar, srv CappedServer) {
+	s.RegisterService(&Capped_ServiceDesc, srv)
+}
+
+func _Capped_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CappedReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CappedServer).Read(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Capped_Read_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CappedServer).Read(ctx, req.(*CappedReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Capped_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CappedTailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CappedServer).Tail(m, &cappedTailServer{stream})
+}
+
+type Capped_TailServer interface {
+	Send(*CappedEntry) error
+	grpc.ServerStream
+}
+
+type cappedTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *cappedTailServer) Send(m *CappedEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Capped_ServiceDesc is the grpc.ServiceDesc for Capped service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Capped_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.capped.v1.Capped",
+	HandlerType: (*CappedServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Read",
+			Handler:    _Capped_Read_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _Capped_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mongo/proto/capped/v1/capped.proto",
+}
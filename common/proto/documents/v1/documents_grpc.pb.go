@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/documents/v1/documents.proto
+
+package documentspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Documents_Query_FullMethodName = "/mongo.proto.documents.v1.Documents/Query"
+)
+
+// DocumentsClient is the client API for Documents service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DocumentsClient interface {
+	// Find the values of a store that match every filter
+	Query(ctx context.Context, in *DocumentsQueryRequest, opts ...grpc.CallOption) (*DocumentsQueryResponse, error)
+}
+
+type documentsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDocumentsClient(cc grpc.ClientConnInterface) DocumentsClient {
+	return &documentsClient{cc}
+}
+
+func (c *documentsClient) Query(ctx context.Context, in *DocumentsQueryRequest, opts ...grpc.CallOption) (*DocumentsQueryResponse, error) {
+	out := new(DocumentsQueryResponse)
+	err := c.cc.Invoke(ctx, Documents_Query_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DocumentsServer is the server API for Documents service.
+// All implementations should embed UnimplementedDocumentsServer
+// for forward compatibility
+type DocumentsServer interface {
+	// Find the values of a store that match every filter
+	Query(context.Context, *DocumentsQueryRequest) (*DocumentsQueryResponse, error)
+}
+
+// UnimplementedDocumentsServer should be embedded to have forward compatible implementations.
+type UnimplementedDocumentsServer struct {
+}
+
+func (UnimplementedDocumentsServer) Query(context.Context, *DocumentsQueryRequest) (*DocumentsQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+
+// UnsafeDocumentsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DocumentsServer will
+// result in compilation errors.
+type UnsafeDocumentsServer interface {
+	mustEmbedUnimplementedDocumentsServer()
+}
+
+func RegisterDocumentsServer(s grpc.ServiceRegistrar, srv DocumentsServer) {
+	s.RegisterService(&Documents_ServiceDesc, srv)
+}
+
+func _Documents_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DocumentsQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentsServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Documents_Query_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentsServer).Query(ctx, req.(*DocumentsQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Documents_ServiceDesc is the grpc.ServiceDesc for Documents service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Documents_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.documents.v1.Documents",
+	HandlerType: (*DocumentsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Query",
+			Handler:    _Documents_Query_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/documents/v1/documents.proto",
+}
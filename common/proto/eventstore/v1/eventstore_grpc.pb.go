@@ -0,0 +1,308 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/eventstore/v1/eventstore.proto
+
+package eventstorepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	EventStore_Append_FullMethodName     = "/mongo.proto.eventstore.v1.EventStore/Append"
+	EventStore_ReadStream_FullMethodName = "/mongo.proto.eventstore.v1.EventStore/ReadStream"
+	EventStore_ReadAll_FullMethodName    = "/mongo.proto.eventstore.v1.EventStore/ReadAll"
+	EventStore_Subscribe_FullMethodName  = "/mongo.proto.eventstore.v1.EventStore/Subscribe"
+)
+
+// EventStoreClient is the client API for EventStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EventStoreClient interface {
+	// Append events to the end of a stream
+	Append(ctx context.Context, in *EventStoreAppendRequest, opts ...grpc.CallOption) (*EventStoreAppendResponse, error)
+	// Read the events of a stream from a version
+	ReadStream(ctx context.Context, in *EventStoreReadStreamRequest, opts ...grpc.CallOption) (EventStore_ReadStreamClient, error)
+	// Read the events of all streams in global order from a position
+	ReadAll(ctx context.Context, in *EventStoreReadAllRequest, opts ...grpc.CallOption) (EventStore_ReadAllClient, error)
+	// Receive events as they are appended, starting after a global position
+	Subscribe(ctx context.Context, in *EventStoreSubscribeRequest, opts ...grpc.CallOption) (EventStore_SubscribeClient, error)
+}
+
+type eventStoreClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventStoreClient(cc grpc.ClientConnInterface) EventStoreClient {
+	return &eventStoreClient{cc}
+}
+
+func (c *eventStoreClient) Append(ctx context.Context, in *EventStoreAppendRequest, opts ...grpc.CallOption) (*EventStoreAppendResponse, error) {
+	out := new(EventStoreAppendResponse)
+	err := c.cc.Invoke(ctx, EventStore_Append_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventStoreClient) ReadStream(ctx context.Context, in *EventStoreReadStreamRequest, opts ...grpc.CallOption) (EventStore_ReadStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventStore_ServiceDesc.Streams[0], EventStore_ReadStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventStoreReadStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventStore_ReadStreamClient interface {
+	Recv() (*EventStoreReadStreamResponse, error)
+	grpc.ClientStream
+}
+
+type eventStoreReadStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventStoreReadStreamClient) Recv() (*EventStoreReadStreamResponse, error) {
+	m := new(EventStoreReadStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eventStoreClient) ReadAll(ctx context.Context, in *EventStoreReadAllRequest, opts ...grpc.CallOption) (EventStore_ReadAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventStore_ServiceDesc.Streams[1], EventStore_ReadAll_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventStoreReadAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventStore_ReadAllClient interface {
+	Recv() (*EventStoreReadAllResponse, error)
+	grpc.ClientStream
+}
+
+type eventStoreReadAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventStoreReadAllClient) Recv() (*EventStoreReadAllResponse, error) {
+	m := new(EventStoreReadAllResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eventStoreClient) Subscribe(ctx context.Context, in *EventStoreSubscribeRequest, opts ...grpc.CallOption) (EventStore_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventStore_ServiceDesc.Streams[2], EventStore_Subscribe_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventStoreSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventStore_SubscribeClient interface {
+	Recv() (*EventStoreSubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type eventStoreSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventStoreSubscribeClient) Recv() (*EventStoreSubscribeResponse, error) {
+	m := new(EventStoreSubscribeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EventStoreServer is the server API for EventStore service.
+// All implementations should embed UnimplementedEventStoreServer
+// for forward compatibility
+type EventStoreServer interface {
+	// Append events to the end of a stream
+	Append(context.Context, *EventStoreAppendRequest) (*EventStoreAppendResponse, error)
+	// Read the events of a stream from a version
+	ReadStream(*EventStoreReadStreamRequest, EventStore_ReadStreamServer) error
+	// Read the events of all streams in global order from a position
+	ReadAll(*EventStoreReadAllRequest, EventStore_ReadAllServer) error
+	// Receive events as they are appended, starting after a global position
+	Subscribe(*EventStoreSubscribeRequest, EventStore_SubscribeServer) error
+}
+
+// UnimplementedEventStoreServer should be embedded to have forward compatible implementations.
+type UnimplementedEventStoreServer struct {
+}
+
+func (UnimplementedEventStoreServer) Append(context.Context, *EventStoreAppendRequest) (*EventStoreAppendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
+}
+func (UnimplementedEventStoreServer) ReadStream(*EventStoreReadStreamRequest, EventStore_ReadStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadStream not implemented")
+}
+func (UnimplementedEventStoreServer) ReadAll(*EventStoreReadAllRequest, EventStore_ReadAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadAll not implemented")
+}
+func (UnimplementedEventStoreServer) Subscribe(*EventStoreSubscribeRequest, EventStore_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+
+// UnsafeEventStoreServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventStoreServer will
+// result in compilation errors.
+type UnsafeEventStoreServer interface {
+	mustEmbedUnimplementedEventStoreServer()
+}
+
+func RegisterEventStoreServer(s grpc.ServiceRegistrar, srv EventStoreServer) {
+	s.RegisterService(&EventStore_ServiceDesc, srv)
+}
+
+func _EventStore_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventStoreAppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventStoreServer).Append(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventStore_Append_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventStoreServer).Append(ctx, req.(*EventStoreAppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventStore_ReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventStoreReadStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventStoreServer).ReadStream(m, &eventStoreReadStreamServer{stream})
+}
+
+type EventStore_ReadStreamServer interface {
+	Send(*EventStoreReadStreamResponse) error
+	grpc.ServerStream
+}
+
+type eventStoreReadStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventStoreReadStreamServer) Send(m *EventStoreReadStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EventStore_ReadAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventStoreReadAllRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventStoreServer).ReadAll(m, &eventStoreReadAllServer{stream})
+}
+
+type EventStore_ReadAllServer interface {
+	Send(*EventStoreReadAllResponse) error
+	grpc.ServerStream
+}
+
+type eventStoreReadAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventStoreReadAllServer) Send(m *EventStoreReadAllResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EventStore_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventStoreSubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventStoreServer).Subscribe(m, &eventStoreSubscribeServer{stream})
+}
+
+type EventStore_SubscribeServer interface {
+	Send(*EventStoreSubscribeResponse) error
+	grpc.ServerStream
+}
+
+type eventStoreSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventStoreSubscribeServer) Send(m *EventStoreSubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// EventStore_ServiceDesc is the grpc.ServiceDesc for EventStore service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventStore_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.eventstore.v1.EventStore",
+	HandlerType: (*EventStoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Append",
+			Handler:    _EventStore_Append_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadStream",
+			Handler:       _EventStore_ReadStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadAll",
+			Handler:       _EventStore_ReadAll_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _EventStore_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mongo/proto/eventstore/v1/eventstore.proto",
+}
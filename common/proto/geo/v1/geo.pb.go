@@ -0,0 +1,828 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/geo/v1/geo.proto
+
+package geopb
+
+import (
+	v1 "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A position in WGS84 degrees
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Longitude float64 `protobuf:"fixed64,1,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude  float64 `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Point) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Point) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+// The points within a distance of a center
+type Circle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center *Point `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	// Radius in meters
+	Radius float64 `protobuf:"fixed64,2,opt,name=radius,proto3" json:"radius,omitempty"`
+}
+
+func (x *Circle) Reset() {
+	*x = Circle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Circle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Circle) ProtoMessage() {}
+
+func (x *Circle) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Circle.ProtoReflect.Descriptor instead.
+func (*Circle) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Circle) GetCenter() *Point {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *Circle) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+// Conditions shared by every geo query
+type GeoQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key value store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The declared geo field, a dot separated path
+	Field string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	// Conditions on the other fields every value must match
+	Filters []*v1.Filter `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters,omitempty"`
+	// The maximum number of values to return, defaults to 100 and can be at most 1000
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The tenant to search in key tenancy mode
+	Tenant string `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`
+}
+
+func (x *GeoQuery) Reset() {
+	*x = GeoQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoQuery) ProtoMessage() {}
+
+func (x *GeoQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoQuery.ProtoReflect.Descriptor instead.
+func (*GeoQuery) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GeoQuery) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *GeoQuery) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *GeoQuery) GetFilters() []*v1.Filter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *GeoQuery) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GeoQuery) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type GeoNearRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *GeoQuery `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Point *Point    `protobuf:"bytes,2,opt,name=point,proto3" json:"point,omitempty"`
+	// Only values within this many meters of the point, unlimited when zero
+	MaxDistance float64 `protobuf:"fixed64,3,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+	// Only values at least this many meters from the point
+	MinDistance float64 `protobuf:"fixed64,4,opt,name=min_distance,json=minDistance,proto3" json:"min_distance,omitempty"`
+}
+
+func (x *GeoNearRequest) Reset() {
+	*x = GeoNearRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoNearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoNearRequest) ProtoMessage() {}
+
+func (x *GeoNearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoNearRequest.ProtoReflect.Descriptor instead.
+func (*GeoNearRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GeoNearRequest) GetQuery() *GeoQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *GeoNearRequest) GetPoint() *Point {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *GeoNearRequest) GetMaxDistance() float64 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+func (x *GeoNearRequest) GetMinDistance() float64 {
+	if x != nil {
+		return x.MinDistance
+	}
+	return 0
+}
+
+type GeoWithinRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *GeoQuery `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Types that are assignable to Shape:
+	//	*GeoWithinRequest_Circle
+	//	*GeoWithinRequest_Geometry
+	Shape isGeoWithinRequest_Shape `protobuf_oneof:"shape"`
+}
+
+func (x *GeoWithinRequest) Reset() {
+	*x = GeoWithinRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoWithinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoWithinRequest) ProtoMessage() {}
+
+func (x *GeoWithinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoWithinRequest.ProtoReflect.Descriptor instead.
+func (*GeoWithinRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GeoWithinRequest) GetQuery() *GeoQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (m *GeoWithinRequest) GetShape() isGeoWithinRequest_Shape {
+	if m != nil {
+		return m.Shape
+	}
+	return nil
+}
+
+func (x *GeoWithinRequest) GetCircle() *Circle {
+	if x, ok := x.GetShape().(*GeoWithinRequest_Circle); ok {
+		return x.Circle
+	}
+	return nil
+}
+
+func (x *GeoWithinRequest) GetGeometry() *structpb.Struct {
+	if x, ok := x.GetShape().(*GeoWithinRequest_Geometry); ok {
+		return x.Geometry
+	}
+	return nil
+}
+
+type isGeoWithinRequest_Shape interface {
+	isGeoWithinRequest_Shape()
+}
+
+type GeoWithinRequest_Circle struct {
+	Circle *Circle `protobuf:"bytes,2,opt,name=circle,proto3,oneof"`
+}
+
+type GeoWithinRequest_Geometry struct {
+	// A GeoJSON Polygon or MultiPolygon
+	Geometry *structpb.Struct `protobuf:"bytes,3,opt,name=geometry,proto3,oneof"`
+}
+
+func (*GeoWithinRequest_Circle) isGeoWithinRequest_Shape() {}
+
+func (*GeoWithinRequest_Geometry) isGeoWithinRequest_Shape() {}
+
+type GeoIntersectsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *GeoQuery `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// A GeoJSON geometry
+	Geometry *structpb.Struct `protobuf:"bytes,2,opt,name=geometry,proto3" json:"geometry,omitempty"`
+}
+
+func (x *GeoIntersectsRequest) Reset() {
+	*x = GeoIntersectsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoIntersectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIntersectsRequest) ProtoMessage() {}
+
+func (x *GeoIntersectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIntersectsRequest.ProtoReflect.Descriptor instead.
+func (*GeoIntersectsRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GeoIntersectsRequest) GetQuery() *GeoQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *GeoIntersectsRequest) GetGeometry() *structpb.Struct {
+	if x != nil {
+		return x.Geometry
+	}
+	return nil
+}
+
+// A value found by a query
+type GeoMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key of the value
+	Key     string           `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Content *structpb.Struct `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Meters from the point of a near query, zero for other queries
+	Distance float64 `protobuf:"fixed64,3,opt,name=distance,proto3" json:"distance,omitempty"`
+}
+
+func (x *GeoMatch) Reset() {
+	*x = GeoMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoMatch) ProtoMessage() {}
+
+func (x *GeoMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoMatch.ProtoReflect.Descriptor instead.
+func (*GeoMatch) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GeoMatch) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GeoMatch) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *GeoMatch) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type GeoQueryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Nearest first for near queries, otherwise ordered by key
+	Matches []*GeoMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+}
+
+func (x *GeoQueryResponse) Reset() {
+	*x = GeoQueryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeoQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoQueryResponse) ProtoMessage() {}
+
+func (x *GeoQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_geo_v1_geo_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoQueryResponse.ProtoReflect.Descriptor instead.
+func (*GeoQueryResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_geo_v1_geo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GeoQueryResponse) GetMatches() []*GeoMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+var File_mongo_proto_geo_v1_geo_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_geo_v1_geo_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x65,
+	0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x67, 0x65, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12,
+	0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e,
+	0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x28, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x41, 0x0a, 0x05, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x53, 0x0a,
+	0x06, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61,
+	0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69,
+	0x75, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x08, 0x47, 0x65, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x3a, 0x0a, 0x07, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x07,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0xbb, 0x01, 0x0a, 0x0e, 0x47, 0x65, 0x6f, 0x4e, 0x65, 0x61,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x2f, 0x0a, 0x05,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x6f,
+	0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x44, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x69,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x34, 0x0a, 0x06,
+	0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x06, 0x63, 0x69, 0x72, 0x63,
+	0x6c, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x48, 0x00, 0x52,
+	0x08, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x42, 0x07, 0x0a, 0x05, 0x73, 0x68, 0x61,
+	0x70, 0x65, 0x22, 0x7f, 0x0a, 0x14, 0x47, 0x65, 0x6f, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x65,
+	0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x05, 0x71, 0x75,
+	0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67,
+	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x33,
+	0x0a, 0x08, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x08, 0x67, 0x65, 0x6f, 0x6d, 0x65,
+	0x74, 0x72, 0x79, 0x22, 0x6b, 0x0a, 0x08, 0x47, 0x65, 0x6f, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x22, 0x4a, 0x0a, 0x10, 0x47, 0x65, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x32, 0x8b, 0x02, 0x0a,
+	0x03, 0x47, 0x65, 0x6f, 0x12, 0x50, 0x0a, 0x04, 0x4e, 0x65, 0x61, 0x72, 0x12, 0x22, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x6f, 0x4e, 0x65, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x24, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67,
+	0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x06, 0x57, 0x69, 0x74, 0x68, 0x69, 0x6e,
+	0x12, 0x24, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67,
+	0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x0a,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x65, 0x63, 0x74, 0x73, 0x12, 0x28, 0x2e, 0x6d, 0x6f, 0x6e,
+	0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x6f, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x65, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x67, 0x65, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x42, 0x5a, 0x40, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x69, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x65, 0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64, 0x62, 0x2d, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x67, 0x65, 0x6f, 0x2f, 0x76, 0x31, 0x3b, 0x67, 0x65, 0x6f, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mongo_proto_geo_v1_geo_proto_rawDescOnce sync.Once
+	file_mongo_proto_geo_v1_geo_proto_rawDescData = file_mongo_proto_geo_v1_geo_proto_rawDesc
+)
+
+func file_mongo_proto_geo_v1_geo_proto_rawDescGZIP() []byte {
+	file_mongo_proto_geo_v1_geo_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_geo_v1_geo_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_geo_v1_geo_proto_rawDescData)
+	})
+	return file_mongo_proto_geo_v1_geo_proto_rawDescData
+}
+
+var file_mongo_proto_geo_v1_geo_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_mongo_proto_geo_v1_geo_proto_goTypes = []interface{}{
+	(*Point)(nil),                // 0: mongo.proto.geo.v1.Point
+	(*Circle)(nil),               // 1: mongo.proto.geo.v1.Circle
+	(*GeoQuery)(nil),             // 2: mongo.proto.geo.v1.GeoQuery
+	(*GeoNearRequest)(nil),       // 3: mongo.proto.geo.v1.GeoNearRequest
+	(*GeoWithinRequest)(nil),     // 4: mongo.proto.geo.v1.GeoWithinRequest
+	(*GeoIntersectsRequest)(nil), // 5: mongo.proto.geo.v1.GeoIntersectsRequest
+	(*GeoMatch)(nil),             // 6: mongo.proto.geo.v1.GeoMatch
+	(*GeoQueryResponse)(nil),     // 7: mongo.proto.geo.v1.GeoQueryResponse
+	(*v1.Filter)(nil),            // 8: mongo.proto.documents.v1.Filter
+	(*structpb.Struct)(nil),      // 9: google.protobuf.Struct
+}
+var file_mongo_proto_geo_v1_geo_proto_depIdxs = []int32{
+	0,  // 0: mongo.proto.geo.v1.Circle.center:type_name -> mongo.proto.geo.v1.Point
+	8,  // 1: mongo.proto.geo.v1.GeoQuery.filters:type_name -> mongo.proto.documents.v1.Filter
+	2,  // 2: mongo.proto.geo.v1.GeoNearRequest.query:type_name -> mongo.proto.geo.v1.GeoQuery
+	0,  // 3: mongo.proto.geo.v1.GeoNearRequest.point:type_name -> mongo.proto.geo.v1.Point
+	2,  // 4: mongo.proto.geo.v1.GeoWithinRequest.query:type_name -> mongo.proto.geo.v1.GeoQuery
+	1,  // 5: mongo.proto.geo.v1.GeoWithinRequest.circle:type_name -> mongo.proto.geo.v1.Circle
+	9,  // 6: mongo.proto.geo.v1.GeoWithinRequest.geometry:type_name -> google.protobuf.Struct
+	2,  // 7: mongo.proto.geo.v1.GeoIntersectsRequest.query:type_name -> mongo.proto.geo.v1.GeoQuery
+	9,  // 8: mongo.proto.geo.v1.GeoIntersectsRequest.geometry:type_name -> google.protobuf.Struct
+	9,  // 9: mongo.proto.geo.v1.GeoMatch.content:type_name -> google.protobuf.Struct
+	6,  // 10: mongo.proto.geo.v1.GeoQueryResponse.matches:type_name -> mongo.proto.geo.v1.GeoMatch
+	3,  // 11: mongo.proto.geo.v1.Geo.Near:input_type -> mongo.proto.geo.v1.GeoNearRequest
+	4,  // 12: mongo.proto.geo.v1.Geo.Within:input_type -> mongo.proto.geo.v1.GeoWithinRequest
+	5,  // 13: mongo.proto.geo.v1.Geo.Intersects:input_type -> mongo.proto.geo.v1.GeoIntersectsRequest
+	7,  // 14: mongo.proto.geo.v1.Geo.Near:output_type -> mongo.proto.geo.v1.GeoQueryResponse
+	7,  // 15: mongo.proto.geo.v1.Geo.Within:output_type -> mongo.proto.geo.v1.GeoQueryResponse
+	7,  // 16: mongo.proto.geo.v1.Geo.Intersects:output_type -> mongo.proto.geo.v1.GeoQueryResponse
+	14, // [14:17] is the sub-list for method output_type
+	11, // [11:14] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_geo_v1_geo_proto_init() }
+func file_mongo_proto_geo_v1_geo_proto_init() {
+	if File_mongo_proto_geo_v1_geo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Point); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Circle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoNearRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoWithinRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoIntersectsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_geo_v1_geo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoQueryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_mongo_proto_geo_v1_geo_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*GeoWithinRequest_Circle)(nil),
+		(*GeoWithinRequest_Geometry)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_geo_v1_geo_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_geo_v1_geo_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_geo_v1_geo_proto_depIdxs,
+		MessageInfos:      file_mongo_proto_geo_v1_geo_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_geo_v1_geo_proto = out.File
+	file_mongo_proto_geo_v1_geo_proto_rawDesc = nil
+	file_mongo_proto_geo_v1_geo_proto_goTypes = nil
+	file_mongo_proto_geo_v1_geo_proto_depIdxs = nil
+}
@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/geo/v1/geo.proto
+
+package geopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Geo_Near_FullMethodName       = "/mongo.proto.geo.v1.Geo/Near"
+	Geo_Within_FullMethodName     = "/mongo.proto.geo.v1.Geo/Within"
+	Geo_Intersects_FullMethodName = "/mongo.proto.geo.v1.Geo/Intersects"
+)
+
+// GeoClient is the client API for Geo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GeoClient interface {
+	// Find the values nearest to a point, nearest first
+	Near(ctx context.Context, in *GeoNearRequest, opts ...grpc.CallOption) (*GeoQueryResponse, error)
+	// Find the values within a circle or polygon
+	Within(ctx context.Context, in *GeoWithinRequest, opts ...grpc.CallOption) (*GeoQueryResponse, error)
+	// Find the values that intersect a geometry
+	Intersects(ctx context.Context, in *GeoIntersectsRequest, opts ...grpc.CallOption) (*GeoQueryResponse, error)
+}
+
+type geoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeoClient(cc grpc.ClientConnInterface) GeoClient {
+	return &geoClient{cc}
+}
+
+func (c *geoClient) Near(ctx context.Context, in *GeoNearRequest, opts ...grpc.CallOption) (*GeoQueryResponse, error) {
+	out := new(GeoQueryResponse)
+	err := c.cc.Invoke(ctx, Geo_Near_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoClient) Within(ctx context.Context, in *GeoWithinRequest, opts ...grpc.CallOption) (*GeoQueryResponse, error) {
+	out := new(GeoQueryResponse)
+	err := c.cc.Invoke(ctx, Geo_Within_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoClient) Intersects(ctx context.Context, in *GeoIntersectsRequest, opts ...grpc.CallOption) (*GeoQueryResponse, error) {
+	out := new(GeoQueryResponse)
+	err := c.cc.Invoke(ctx, Geo_Intersects_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GeoServer is the server API for Geo service.
+// All implementations should embed UnimplementedGeoServer
+// for forward compatibility
+type GeoServer interface {
+	// Find the values nearest to a point, nearest first
+	Near(context.Context, *GeoNearRequest) (*GeoQueryResponse, error)
+	// Find the values within a circle or polygon
+	Within(context.Context, *GeoWithinRequest) (*GeoQueryResponse, error)
+	// Find the values that intersect a geometry
+	Intersects(context.Context, *GeoIntersectsRequest) (*GeoQueryResponse, error)
+}
+
+// UnimplementedGeoServer should be embedded to have forward compatible implementations.
+type UnimplementedGeoServer struct {
+}
+
+func (UnimplementedGeoServer) Near(context.Context, *GeoNearRequest) (*GeoQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Near not implemented")
+}
+func (UnimplementedGeoServer) Within(context.Context, *GeoWithinRequest) (*GeoQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Within not implemented")
+}
+func (UnimplementedGeoServer) Intersects(context.Context, *GeoIntersectsRequest) (*GeoQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Intersects not implemented")
+}
+
+// UnsafeGeoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeoServer will
+// result in compilation errors.
+type UnsafeGeoServer interface {
+	mustEmbedUnimplementedGeoServer()
+}
+
+func RegisterGeoServer(s grpc.ServiceRegistrar, srv GeoServer) {
+	s.RegisterService(&Geo_ServiceDesc, srv)
+}
+
+func _Geo_Near_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeoNearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).Near(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_Near_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).Near(ctx, req.(*GeoNearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geo_Within_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeoWithinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).Within(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_Within_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).Within(ctx, req.(*GeoWithinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geo_Intersects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeoIntersectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).Intersects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_Intersects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).Intersects(ctx, req.(*GeoIntersectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Geo_ServiceDesc is the grpc.ServiceDesc for Geo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Geo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.geo.v1.Geo",
+	HandlerType: (*GeoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Near",
+			Handler:    _Geo_Near_Handler,
+		},
+		{
+			MethodName: "Within",
+			Handler:    _Geo_Within_Handler,
+		},
+		{
+			MethodName: "Intersects",
+			Handler:    _Geo_Intersects_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/geo/v1/geo.proto",
+}
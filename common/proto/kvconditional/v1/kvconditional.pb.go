@@ -0,0 +1,788 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/kvconditional/v1/kvconditional.proto
+
+package kvconditionalpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A reference to a value in a key value store
+type ValueRef struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key value store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The key of the value
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ValueRef) Reset() {
+	*x = ValueRef{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValueRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueRef) ProtoMessage() {}
+
+func (x *ValueRef) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueRef.ProtoReflect.Descriptor instead.
+func (*ValueRef) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ValueRef) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *ValueRef) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// A version of a value to compare against
+type Precondition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Condition:
+	//	*Precondition_Revision
+	//	*Precondition_Etag
+	Condition isPrecondition_Condition `protobuf_oneof:"condition"`
+}
+
+func (x *Precondition) Reset() {
+	*x = Precondition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Precondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Precondition) ProtoMessage() {}
+
+func (x *Precondition) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Precondition.ProtoReflect.Descriptor instead.
+func (*Precondition) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *Precondition) GetCondition() isPrecondition_Condition {
+	if m != nil {
+		return m.Condition
+	}
+	return nil
+}
+
+func (x *Precondition) GetRevision() int64 {
+	if x, ok := x.GetCondition().(*Precondition_Revision); ok {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *Precondition) GetEtag() string {
+	if x, ok := x.GetCondition().(*Precondition_Etag); ok {
+		return x.Etag
+	}
+	return ""
+}
+
+type isPrecondition_Condition interface {
+	isPrecondition_Condition()
+}
+
+type Precondition_Revision struct {
+	// The revision of the value, 0 matches a value that doesn't exist
+	Revision int64 `protobuf:"varint,1,opt,name=revision,proto3,oneof"`
+}
+
+type Precondition_Etag struct {
+	// The content hash of the value
+	Etag string `protobuf:"bytes,2,opt,name=etag,proto3,oneof"`
+}
+
+func (*Precondition_Revision) isPrecondition_Condition() {}
+
+func (*Precondition_Etag) isPrecondition_Condition() {}
+
+type KvConditionalGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value to get
+	Ref *ValueRef `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// The version the caller already has, the content is returned when the value no longer matches it
+	IfNoneMatch *Precondition `protobuf:"bytes,2,opt,name=if_none_match,json=ifNoneMatch,proto3" json:"if_none_match,omitempty"`
+}
+
+func (x *KvConditionalGetRequest) Reset() {
+	*x = KvConditionalGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvConditionalGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvConditionalGetRequest) ProtoMessage() {}
+
+func (x *KvConditionalGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvConditionalGetRequest.ProtoReflect.Descriptor instead.
+func (*KvConditionalGetRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *KvConditionalGetRequest) GetRef() *ValueRef {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *KvConditionalGetRequest) GetIfNoneMatch() *Precondition {
+	if x != nil {
+		return x.IfNoneMatch
+	}
+	return nil
+}
+
+type KvConditionalGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// False when the value still matches if_none_match, content is unset
+	Changed bool `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+	// The content of the value
+	Content *structpb.Struct `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// The revision of the value, incremented on every write
+	Revision int64 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	// The content hash of the value
+	Etag string `protobuf:"bytes,4,opt,name=etag,proto3" json:"etag,omitempty"`
+}
+
+func (x *KvConditionalGetResponse) Reset() {
+	*x = KvConditionalGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvConditionalGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvConditionalGetResponse) ProtoMessage() {}
+
+func (x *KvConditionalGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvConditionalGetResponse.ProtoReflect.Descriptor instead.
+func (*KvConditionalGetResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KvConditionalGetResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *KvConditionalGetResponse) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *KvConditionalGetResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *KvConditionalGetResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type KvConditionalSetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value to set
+	Ref *ValueRef `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// The new content
+	Content *structpb.Struct `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// The version the value must be at, the value is set unconditionally when unset
+	IfMatch *Precondition `protobuf:"bytes,3,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+}
+
+func (x *KvConditionalSetRequest) Reset() {
+	*x = KvConditionalSetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvConditionalSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvConditionalSetRequest) ProtoMessage() {}
+
+func (x *KvConditionalSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvConditionalSetRequest.ProtoReflect.Descriptor instead.
+func (*KvConditionalSetRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *KvConditionalSetRequest) GetRef() *ValueRef {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *KvConditionalSetRequest) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *KvConditionalSetRequest) GetIfMatch() *Precondition {
+	if x != nil {
+		return x.IfMatch
+	}
+	return nil
+}
+
+type KvConditionalSetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The revision of the value after the write
+	Revision int64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// The content hash of the value after the write
+	Etag string `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+}
+
+func (x *KvConditionalSetResponse) Reset() {
+	*x = KvConditionalSetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvConditionalSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvConditionalSetResponse) ProtoMessage() {}
+
+func (x *KvConditionalSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvConditionalSetResponse.ProtoReflect.Descriptor instead.
+func (*KvConditionalSetResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KvConditionalSetResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *KvConditionalSetResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type KvConditionalDeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value to delete
+	Ref *ValueRef `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// The version the value must be at, the value is deleted unconditionally when unset
+	IfMatch *Precondition `protobuf:"bytes,2,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+}
+
+func (x *KvConditionalDeleteRequest) Reset() {
+	*x = KvConditionalDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvConditionalDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvConditionalDeleteRequest) ProtoMessage() {}
+
+func (x *KvConditionalDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvConditionalDeleteRequest.ProtoReflect.Descriptor instead.
+func (*KvConditionalDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *KvConditionalDeleteRequest) GetRef() *ValueRef {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *KvConditionalDeleteRequest) GetIfMatch() *Precondition {
+	if x != nil {
+		return x.IfMatch
+	}
+	return nil
+}
+
+type KvConditionalDeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *KvConditionalDeleteResponse) Reset() {
+	*x = KvConditionalDeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvConditionalDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvConditionalDeleteResponse) ProtoMessage() {}
+
+func (x *KvConditionalDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvConditionalDeleteResponse.ProtoReflect.Descriptor instead.
+func (*KvConditionalDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP(), []int{7}
+}
+
+var File_mongo_proto_kvconditional_v1_kvconditional_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDesc = []byte{
+	0x0a, 0x30, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x76,
+	0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x31, 0x2f, 0x6b,
+	0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x1c, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x6b, 0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31,
+	0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x32,
+	0x0a, 0x08, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x22, 0x4f, 0x0a, 0x0c, 0x50, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x04, 0x65, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x04, 0x65, 0x74, 0x61, 0x67, 0x42, 0x0b, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0xa3, 0x01, 0x0a, 0x17, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x38, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x65, 0x66, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x4e, 0x0a, 0x0d, 0x69, 0x66, 0x5f,
+	0x6e, 0x6f, 0x6e, 0x65, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x2a, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b,
+	0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x69, 0x66,
+	0x4e, 0x6f, 0x6e, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x22, 0x97, 0x01, 0x0a, 0x18, 0x4b, 0x76,
+	0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x47, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x65, 0x74, 0x61, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x65,
+	0x74, 0x61, 0x67, 0x22, 0xcd, 0x01, 0x0a, 0x17, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x38, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x65, 0x66, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x08,
+	0x69, 0x66, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x69, 0x66, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x22, 0x4a, 0x0a, 0x18, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x65,
+	0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x65, 0x74, 0x61, 0x67, 0x22,
+	0x9d, 0x01, 0x0a, 0x1a, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38,
+	0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x6f,
+	0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x65, 0x66, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x45, 0x0a, 0x08, 0x69, 0x66, 0x5f, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x6d, 0x6f, 0x6e,
+	0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x69, 0x66, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x22,
+	0x1d, 0x0a, 0x1b, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x92,
+	0x03, 0x0a, 0x0d, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c,
+	0x12, 0x7d, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x49, 0x66, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x12, 0x35, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b,
+	0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e,
+	0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x7b, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x49, 0x66, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x35, 0x2e,
+	0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x43,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c,
+	0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x53, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x84, 0x01, 0x0a,
+	0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x66, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x38,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76,
+	0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x39, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x56, 0x5a, 0x54, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6e, 0x69, 0x74, 0x72, 0x69, 0x63, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e,
+	0x67, 0x6f, 0x64, 0x62, 0x2d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x76, 0x63, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x31, 0x3b, 0x6b, 0x76, 0x63, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescOnce sync.Once
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescData = file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDesc
+)
+
+func file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescGZIP() []byte {
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescData)
+	})
+	return file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDescData
+}
+
+var file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_mongo_proto_kvconditional_v1_kvconditional_proto_goTypes = []interface{}{
+	(*ValueRef)(nil),                    // 0: mongo.proto.kvconditional.v1.ValueRef
+	(*Precondition)(nil),                // 1: mongo.proto.kvconditional.v1.Precondition
+	(*KvConditionalGetRequest)(nil),     // 2: mongo.proto.kvconditional.v1.KvConditionalGetRequest
+	(*KvConditionalGetResponse)(nil),    // 3: mongo.proto.kvconditional.v1.KvConditionalGetResponse
+	(*KvConditionalSetRequest)(nil),     // 4: mongo.proto.kvconditional.v1.KvConditionalSetRequest
+	(*KvConditionalSetResponse)(nil),    // 5: mongo.proto.kvconditional.v1.KvConditionalSetResponse
+	(*KvConditionalDeleteRequest)(nil),  // 6: mongo.proto.kvconditional.v1.KvConditionalDeleteRequest
+	(*KvConditionalDeleteResponse)(nil), // 7: mongo.proto.kvconditional.v1.KvConditionalDeleteResponse
+	(*structpb.Struct)(nil),             // 8: google.protobuf.Struct
+}
+var file_mongo_proto_kvconditional_v1_kvconditional_proto_depIdxs = []int32{
+	0,  // 0: mongo.proto.kvconditional.v1.KvConditionalGetRequest.ref:type_name -> mongo.proto.kvconditional.v1.ValueRef
+	1,  // 1: mongo.proto.kvconditional.v1.KvConditionalGetRequest.if_none_match:type_name -> mongo.proto.kvconditional.v1.Precondition
+	8,  // 2: mongo.proto.kvconditional.v1.KvConditionalGetResponse.content:type_name -> google.protobuf.Struct
+	0,  // 3: mongo.proto.kvconditional.v1.KvConditionalSetRequest.ref:type_name -> mongo.proto.kvconditional.v1.ValueRef
+	8,  // 4: mongo.proto.kvconditional.v1.KvConditionalSetRequest.content:type_name -> google.protobuf.Struct
+	1,  // 5: mongo.proto.kvconditional.v1.KvConditionalSetRequest.if_match:type_name -> mongo.proto.kvconditional.v1.Precondition
+	0,  // 6: mongo.proto.kvconditional.v1.KvConditionalDeleteRequest.ref:type_name -> mongo.proto.kvconditional.v1.ValueRef
+	1,  // 7: mongo.proto.kvconditional.v1.KvConditionalDeleteRequest.if_match:type_name -> mongo.proto.kvconditional.v1.Precondition
+	2,  // 8: mongo.proto.kvconditional.v1.KvConditional.GetIfChanged:input_type -> mongo.proto.kvconditional.v1.KvConditionalGetRequest
+	4,  // 9: mongo.proto.kvconditional.v1.KvConditional.SetIfMatch:input_type -> mongo.proto.kvconditional.v1.KvConditionalSetRequest
+	6,  // 10: mongo.proto.kvconditional.v1.KvConditional.DeleteIfMatch:input_type -> mongo.proto.kvconditional.v1.KvConditionalDeleteRequest
+	3,  // 11: mongo.proto.kvconditional.v1.KvConditional.GetIfChanged:output_type -> mongo.proto.kvconditional.v1.KvConditionalGetResponse
+	5,  // 12: mongo.proto.kvconditional.v1.KvConditional.SetIfMatch:output_type -> mongo.proto.kvconditional.v1.KvConditionalSetResponse
+	7,  // 13: mongo.proto.kvconditional.v1.KvConditional.DeleteIfMatch:output_type -> mongo.proto.kvconditional.v1.KvConditionalDeleteResponse
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_kvconditional_v1_kvconditional_proto_init() }
+func file_mongo_proto_kvconditional_v1_kvconditional_proto_init() {
+	if File_mongo_proto_kvconditional_v1_kvconditional_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValueRef); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Precondition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvConditionalGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvConditionalGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvConditionalSetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvConditionalSetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvConditionalDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvConditionalDeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*Precondition_Revision)(nil),
+		(*Precondition_Etag)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_kvconditional_v1_kvconditional_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_kvconditional_v1_kvconditional_proto_depIdxs,
+		MessageInfos:      file_mongo_proto_kvconditional_v1_kvconditional_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_kvconditional_v1_kvconditional_proto = out.File
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_rawDesc = nil
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_goTypes = nil
+	file_mongo_proto_kvconditional_v1_kvconditional_proto_depIdxs = nil
+}
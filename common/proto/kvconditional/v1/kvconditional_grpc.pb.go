@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/kvconditional/v1/kvconditional.proto
+
+package kvconditionalpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	KvConditional_GetIfChanged_FullMethodName  = "/mongo.proto.kvconditional.v1.KvConditional/GetIfChanged"
+	KvConditional_SetIfMatch_FullMethodName    = "/mongo.proto.kvconditional.v1.KvConditional/SetIfMatch"
+	KvConditional_DeleteIfMatch_FullMethodName = "/mongo.proto.kvconditional.v1.KvConditional/DeleteIfMatch"
+)
+
+// KvConditionalClient is the client API for KvConditional service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KvConditionalClient interface {
+	// Get a value unless it still matches a revision or ETag the caller already has
+	GetIfChanged(ctx context.Context, in *KvConditionalGetRequest, opts ...grpc.CallOption) (*KvConditionalGetResponse, error)
+	// Set a value only if it is at the given revision or ETag
+	SetIfMatch(ctx context.Context, in *KvConditionalSetRequest, opts ...grpc.CallOption) (*KvConditionalSetResponse, error)
+	// Delete a value only if it is at the given revision or ETag
+	DeleteIfMatch(ctx context.Context, in *KvConditionalDeleteRequest, opts ...grpc.CallOption) (*KvConditionalDeleteResponse, error)
+}
+
+type kvConditionalClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKvConditionalClient(cc grpc.ClientConnInterface) KvConditionalClient {
+	return &kvConditionalClient{cc}
+}
+
+func (c *kvConditionalClient) GetIfChanged(ctx context.Context, in *KvConditionalGetRequest, opts ...grpc.CallOption) (*KvConditionalGetResponse, error) {
+	out := new(KvConditionalGetResponse)
+	err := c.cc.Invoke(ctx, KvConditional_GetIfChanged_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kvConditionalClient) SetIfMatch(ctx context.Context, in *KvConditionalSetRequest, opts ...grpc.CallOption) (*KvConditionalSetResponse, error) {
+	out := new(KvConditionalSetResponse)
+	err := c.cc.Invoke(ctx, KvConditional_SetIfMatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kvConditionalClient) DeleteIfMatch(ctx context.Context, in *KvConditionalDeleteRequest, opts ...grpc.CallOption) (*KvConditionalDeleteResponse, error) {
+	out := new(KvConditionalDeleteResponse)
+	err := c.cc.Invoke(ctx, KvConditional_DeleteIfMatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KvConditionalServer is the server API for KvConditional service.
+// All implementations should embed UnimplementedKvConditionalServer
+// for forward compatibility
+type KvConditionalServer interface {
+	// Get a value unless it still matches a revision or ETag the caller already has
+	GetIfChanged(context.Context, *KvConditionalGetRequest) (*KvConditionalGetResponse, error)
+	// Set a value only if it is at the given revision or ETag
+	SetIfMatch(context.Context, *KvConditionalSetRequest) (*KvConditionalSetResponse, error)
+	// Delete a value only if it is at the given revision or ETag
+	DeleteIfMatch(context.Context, *KvConditionalDeleteRequest) (*KvConditionalDeleteResponse, error)
+}
+
+// UnimplementedKvConditionalServer should be embedded to have forward compatible implementations.
+type UnimplementedKvConditionalServer struct {
+}
+
+func (UnimplementedKvConditionalServer) GetIfChanged(context.Context, *KvConditionalGetRequest) (*KvConditionalGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIfChanged not implemented")
+}
+func (UnimplementedKvConditionalServer) SetIfMatch(context.Context, *KvConditionalSetRequest) (*KvConditionalSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetIfMatch not implemented")
+}
+func (UnimplementedKvConditionalServer) DeleteIfMatch(context.Context, *KvConditionalDeleteRequest) (*KvConditionalDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteIfMatch not implemented")
+}
+
+// UnsafeKvConditionalServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KvConditionalServer will
+// result in compilation errors.
+type UnsafeKvConditionalServer interface {
+	mustEmbedUnimplementedKvConditionalServer()
+}
+
+func RegisterKvConditionalServer(s grpc.ServiceRegistrar, srv KvConditionalServer) {
+	s.RegisterService(&KvConditional_ServiceDesc, srv)
+}
+
+func _KvConditional_GetIfChanged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KvConditionalGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KvConditionalServer).GetIfChanged(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KvConditional_GetIfChanged_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KvConditionalServer).GetIfChanged(ctx, req.(*KvConditionalGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KvConditional_SetIfMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KvConditionalSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KvConditionalServer).SetIfMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KvConditional_SetIfMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KvConditionalServer).SetIfMatch(ctx, req.(*KvConditionalSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KvConditional_DeleteIfMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KvConditionalDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KvConditionalServer).DeleteIfMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KvConditional_DeleteIfMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KvConditionalServer).DeleteIfMatch(ctx, req.(*KvConditionalDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KvConditional_ServiceDesc is the grpc.ServiceDesc for KvConditional service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KvConditional_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.kvconditional.v1.KvConditional",
+	HandlerType: (*KvConditionalServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIfChanged",
+			Handler:    _KvConditional_GetIfChanged_Handler,
+		},
+		{
+			MethodName: "SetIfMatch",
+			Handler:    _KvConditional_SetIfMatch_Handler,
+		},
+		{
+			MethodName: "DeleteIfMatch",
+			Handler:    _KvConditional_DeleteIfMatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/kvconditional/v1/kvconditional.proto",
+}
@@ -0,0 +1,1292 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/kvupdate/v1/kvupdate.proto
+
+package kvupdatepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A reference to a value in a key value store
+type ValueRef struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key value store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The key of the value
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ValueRef) Reset() {
+	*x = ValueRef{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValueRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueRef) ProtoMessage() {}
+
+func (x *ValueRef) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueRef.ProtoReflect.Descriptor instead.
+func (*ValueRef) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ValueRef) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *ValueRef) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// Add to a numeric field, the field is created with the amount if it doesn't exist
+type Increment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Dot separated path of the field
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The amount to add, negative to decrement
+	Amount float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *Increment) Reset() {
+	*x = Increment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Increment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Increment) ProtoMessage() {}
+
+func (x *Increment) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Increment.ProtoReflect.Descriptor instead.
+func (*Increment) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Increment) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Increment) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// Set a field, creating any missing parent objects
+type Set struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Dot separated path of the field
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The new value
+	Value *structpb.Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Set) Reset() {
+	*x = Set{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Set) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Set) ProtoMessage() {}
+
+func (x *Set) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Set.ProtoReflect.Descriptor instead.
+func (*Set) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Set) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Set) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// Remove a field
+type Unset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Dot separated path of the field
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *Unset) Reset() {
+	*x = Unset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Unset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Unset) ProtoMessage() {}
+
+func (x *Unset) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Unset.ProtoReflect.Descriptor instead.
+func (*Unset) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Unset) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// Append values to an array field, the field is created if it doesn't exist
+type Append struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Dot separated path of the field
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The values to append, in order
+	Values []*structpb.Value `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *Append) Reset() {
+	*x = Append{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Append) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Append) ProtoMessage() {}
+
+func (x *Append) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Append.ProtoReflect.Descriptor instead.
+func (*Append) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Append) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Append) GetValues() []*structpb.Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Remove every element equal to a value from an array field
+type Remove struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Dot separated path of the field
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The value to remove
+	Value *structpb.Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Remove) Reset() {
+	*x = Remove{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Remove) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Remove) ProtoMessage() {}
+
+func (x *Remove) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Remove.ProtoReflect.Descriptor instead.
+func (*Remove) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Remove) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Remove) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// A single update operation, each operation in an update must target a different field
+type UpdateOperation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Operation:
+	//	*UpdateOperation_Increment
+	//	*UpdateOperation_Set
+	//	*UpdateOperation_Unset
+	//	*UpdateOperation_Append
+	//	*UpdateOperation_Remove
+	Operation isUpdateOperation_Operation `protobuf_oneof:"operation"`
+}
+
+func (x *UpdateOperation) Reset() {
+	*x = UpdateOperation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOperation) ProtoMessage() {}
+
+func (x *UpdateOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOperation.ProtoReflect.Descriptor instead.
+func (*UpdateOperation) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{6}
+}
+
+func (m *UpdateOperation) GetOperation() isUpdateOperation_Operation {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (x *UpdateOperation) GetIncrement() *Increment {
+	if x, ok := x.GetOperation().(*UpdateOperation_Increment); ok {
+		return x.Increment
+	}
+	return nil
+}
+
+func (x *UpdateOperation) GetSet() *Set {
+	if x, ok := x.GetOperation().(*UpdateOperation_Set); ok {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *UpdateOperation) GetUnset() *Unset {
+	if x, ok := x.GetOperation().(*UpdateOperation_Unset); ok {
+		return x.Unset
+	}
+	return nil
+}
+
+func (x *UpdateOperation) GetAppend() *Append {
+	if x, ok := x.GetOperation().(*UpdateOperation_Append); ok {
+		return x.Append
+	}
+	return nil
+}
+
+func (x *UpdateOperation) GetRemove() *Remove {
+	if x, ok := x.GetOperation().(*UpdateOperation_Remove); ok {
+		return x.Remove
+	}
+	return nil
+}
+
+type isUpdateOperation_Operation interface {
+	isUpdateOperation_Operation()
+}
+
+type UpdateOperation_Increment struct {
+	Increment *Increment `protobuf:"bytes,1,opt,name=increment,proto3,oneof"`
+}
+
+type UpdateOperation_Set struct {
+	Set *Set `protobuf:"bytes,2,opt,name=set,proto3,oneof"`
+}
+
+type UpdateOperation_Unset struct {
+	Unset *Unset `protobuf:"bytes,3,opt,name=unset,proto3,oneof"`
+}
+
+type UpdateOperation_Append struct {
+	Append *Append `protobuf:"bytes,4,opt,name=append,proto3,oneof"`
+}
+
+type UpdateOperation_Remove struct {
+	Remove *Remove `protobuf:"bytes,5,opt,name=remove,proto3,oneof"`
+}
+
+func (*UpdateOperation_Increment) isUpdateOperation_Operation() {}
+
+func (*UpdateOperation_Set) isUpdateOperation_Operation() {}
+
+func (*UpdateOperation_Unset) isUpdateOperation_Operation() {}
+
+func (*UpdateOperation_Append) isUpdateOperation_Operation() {}
+
+func (*UpdateOperation_Remove) isUpdateOperation_Operation() {}
+
+type KvUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value to update
+	Ref *ValueRef `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// The operations to apply
+	Operations []*UpdateOperation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Create the value if it doesn't exist, otherwise a missing value returns NotFound
+	Upsert bool `protobuf:"varint,3,opt,name=upsert,proto3" json:"upsert,omitempty"`
+}
+
+func (x *KvUpdateRequest) Reset() {
+	*x = KvUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvUpdateRequest) ProtoMessage() {}
+
+func (x *KvUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvUpdateRequest.ProtoReflect.Descriptor instead.
+func (*KvUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *KvUpdateRequest) GetRef() *ValueRef {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *KvUpdateRequest) GetOperations() []*UpdateOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *KvUpdateRequest) GetUpsert() bool {
+	if x != nil {
+		return x.Upsert
+	}
+	return false
+}
+
+type KvUpdateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value after the update
+	Content *structpb.Struct `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *KvUpdateResponse) Reset() {
+	*x = KvUpdateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvUpdateResponse) ProtoMessage() {}
+
+func (x *KvUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvUpdateResponse.ProtoReflect.Descriptor instead.
+func (*KvUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *KvUpdateResponse) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type KvUpdateMergePatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value to patch
+	Ref *ValueRef `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// The merge patch, null members remove fields and object members are merged recursively
+	Patch *structpb.Struct `protobuf:"bytes,2,opt,name=patch,proto3" json:"patch,omitempty"`
+	// Create the value if it doesn't exist, otherwise a missing value returns NotFound
+	Upsert bool `protobuf:"varint,3,opt,name=upsert,proto3" json:"upsert,omitempty"`
+}
+
+func (x *KvUpdateMergePatchRequest) Reset() {
+	*x = KvUpdateMergePatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvUpdateMergePatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvUpdateMergePatchRequest) ProtoMessage() {}
+
+func (x *KvUpdateMergePatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvUpdateMergePatchRequest.ProtoReflect.Descriptor instead.
+func (*KvUpdateMergePatchRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *KvUpdateMergePatchRequest) GetRef() *ValueRef {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *KvUpdateMergePatchRequest) GetPatch() *structpb.Struct {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *KvUpdateMergePatchRequest) GetUpsert() bool {
+	if x != nil {
+		return x.Upsert
+	}
+	return false
+}
+
+type KvUpdateMergePatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value after the patch
+	Content *structpb.Struct `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *KvUpdateMergePatchResponse) Reset() {
+	*x = KvUpdateMergePatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvUpdateMergePatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvUpdateMergePatchResponse) ProtoMessage() {}
+
+func (x *KvUpdateMergePatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvUpdateMergePatchResponse.ProtoReflect.Descriptor instead.
+func (*KvUpdateMergePatchResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *KvUpdateMergePatchResponse) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+// A JSON Patch operation
+type JsonPatchOperation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// One of add, remove, replace, move or test
+	Op string `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	// JSON Pointer to the target field
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// JSON Pointer to the source field of a move
+	From string `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// The value for add, replace and test
+	Value *structpb.Value `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *JsonPatchOperation) Reset() {
+	*x = JsonPatchOperation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JsonPatchOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonPatchOperation) ProtoMessage() {}
+
+func (x *JsonPatchOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonPatchOperation.ProtoReflect.Descriptor instead.
+func (*JsonPatchOperation) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *JsonPatchOperation) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *JsonPatchOperation) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *JsonPatchOperation) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *JsonPatchOperation) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type KvUpdateJsonPatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value to patch
+	Ref *ValueRef `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// The patch operations
+	Operations []*JsonPatchOperation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+}
+
+func (x *KvUpdateJsonPatchRequest) Reset() {
+	*x = KvUpdateJsonPatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvUpdateJsonPatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvUpdateJsonPatchRequest) ProtoMessage() {}
+
+func (x *KvUpdateJsonPatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvUpdateJsonPatchRequest.ProtoReflect.Descriptor instead.
+func (*KvUpdateJsonPatchRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KvUpdateJsonPatchRequest) GetRef() *ValueRef {
+	if x != nil {
+		return x.Ref
+	}
+	return nil
+}
+
+func (x *KvUpdateJsonPatchRequest) GetOperations() []*JsonPatchOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type KvUpdateJsonPatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value after the patch
+	Content *structpb.Struct `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *KvUpdateJsonPatchResponse) Reset() {
+	*x = KvUpdateJsonPatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KvUpdateJsonPatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KvUpdateJsonPatchResponse) ProtoMessage() {}
+
+func (x *KvUpdateJsonPatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KvUpdateJsonPatchResponse.ProtoReflect.Descriptor instead.
+func (*KvUpdateJsonPatchResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *KvUpdateJsonPatchResponse) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+var File_mongo_proto_kvupdate_v1_kvupdate_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDesc = []byte{
+	0x0a, 0x26, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x76,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76,
+	0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x32, 0x0a, 0x08, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x22, 0x37, 0x0a, 0x09, 0x49, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x47, 0x0a, 0x03,
+	0x53, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1b, 0x0a, 0x05, 0x55, 0x6e, 0x73, 0x65, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x22, 0x4c, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x2e, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x22, 0x4a, 0x0a, 0x06, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2c,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc2, 0x02, 0x0a,
+	0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x42, 0x0a, 0x09, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e,
+	0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x69, 0x6e, 0x63, 0x72, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x03, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x48,
+	0x00, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12, 0x36, 0x0a, 0x05, 0x75, 0x6e, 0x73, 0x65, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x55, 0x6e, 0x73, 0x65, 0x74, 0x48, 0x00, 0x52, 0x05, 0x75, 0x6e, 0x73, 0x65, 0x74, 0x12, 0x39,
+	0x0a, 0x06, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x48,
+	0x00, 0x52, 0x06, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x12, 0x39, 0x0a, 0x06, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x6f, 0x6e, 0x67,
+	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0xa8, 0x01, 0x0a, 0x0f, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x52, 0x65, 0x66, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x48, 0x0a, 0x0a, 0x6f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x70, 0x73, 0x65, 0x72, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x70, 0x73, 0x65, 0x72, 0x74, 0x22, 0x45, 0x0a, 0x10,
+	0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x22, 0x97, 0x01, 0x0a, 0x19, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x33, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x65,
+	0x66, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x2d, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05,
+	0x70, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x70, 0x73, 0x65, 0x72, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x70, 0x73, 0x65, 0x72, 0x74, 0x22, 0x4f, 0x0a,
+	0x1a, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x7a,
+	0x0a, 0x12, 0x4a, 0x73, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x6f, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2c, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x18, 0x4b,
+	0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4a, 0x73, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x65, 0x66, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x4b, 0x0a, 0x0a,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2b, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b,
+	0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x73, 0x6f, 0x6e, 0x50,
+	0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x4e, 0x0a, 0x19, 0x4b, 0x76, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x4a, 0x73, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x32, 0xd4, 0x02, 0x0a, 0x08, 0x4b, 0x76,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x5d, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x12, 0x28, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b,
+	0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x6d, 0x6f, 0x6e,
+	0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x75, 0x0a, 0x0a, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x61,
+	0x74, 0x63, 0x68, 0x12, 0x32, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x33, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x09,
+	0x4a, 0x73, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x31, 0x2e, 0x6d, 0x6f, 0x6e, 0x67,
+	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4a, 0x73, 0x6f, 0x6e,
+	0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6b, 0x76, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x76, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4a,
+	0x73, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x42, 0x4c, 0x5a, 0x4a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e,
+	0x69, 0x74, 0x72, 0x69, 0x63, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64,
+	0x62, 0x2d, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x2f, 0x76, 0x31, 0x3b, 0x6b, 0x76, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescOnce sync.Once
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescData = file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDesc
+)
+
+func file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescGZIP() []byte {
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescData)
+	})
+	return file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDescData
+}
+
+var file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_mongo_proto_kvupdate_v1_kvupdate_proto_goTypes = []interface{}{
+	(*ValueRef)(nil),                   // 0: mongo.proto.kvupdate.v1.ValueRef
+	(*Increment)(nil),                  // 1: mongo.proto.kvupdate.v1.Increment
+	(*Set)(nil),                        // 2: mongo.proto.kvupdate.v1.Set
+	(*Unset)(nil),                      // 3: mongo.proto.kvupdate.v1.Unset
+	(*Append)(nil),                     // 4: mongo.proto.kvupdate.v1.Append
+	(*Remove)(nil),                     // 5: mongo.proto.kvupdate.v1.Remove
+	(*UpdateOperation)(nil),            // 6: mongo.proto.kvupdate.v1.UpdateOperation
+	(*KvUpdateRequest)(nil),            // 7: mongo.proto.kvupdate.v1.KvUpdateRequest
+	(*KvUpdateResponse)(nil),           // 8: mongo.proto.kvupdate.v1.KvUpdateResponse
+	(*KvUpdateMergePatchRequest)(nil),  // 9: mongo.proto.kvupdate.v1.KvUpdateMergePatchRequest
+	(*KvUpdateMergePatchResponse)(nil), // 10: mongo.proto.kvupdate.v1.KvUpdateMergePatchResponse
+	(*JsonPatchOperation)(nil),         // 11: mongo.proto.kvupdate.v1.JsonPatchOperation
+	(*KvUpdateJsonPatchRequest)(nil),   // 12: mongo.proto.kvupdate.v1.KvUpdateJsonPatchRequest
+	(*KvUpdateJsonPatchResponse)(nil),  // 13: mongo.proto.kvupdate.v1.KvUpdateJsonPatchResponse
+	(*structpb.Value)(nil),             // 14: google.protobuf.Value
+	(*structpb.Struct)(nil),            // 15: google.protobuf.Struct
+}
+var file_mongo_proto_kvupdate_v1_kvupdate_proto_depIdxs = []int32{
+	14, // 0: mongo.proto.kvupdate.v1.Set.value:type_name -> google.protobuf.Value
+	14, // 1: mongo.proto.kvupdate.v1.Append.values:type_name -> google.protobuf.Value
+	14, // 2: mongo.proto.kvupdate.v1.Remove.value:type_name -> google.protobuf.Value
+	1,  // 3: mongo.proto.kvupdate.v1.UpdateOperation.increment:type_name -> mongo.proto.kvupdate.v1.Increment
+	2,  // 4: mongo.proto.kvupdate.v1.UpdateOperation.set:type_name -> mongo.proto.kvupdate.v1.Set
+	3,  // 5: mongo.proto.kvupdate.v1.UpdateOperation.unset:type_name -> mongo.proto.kvupdate.v1.Unset
+	4,  // 6: mongo.proto.kvupdate.v1.UpdateOperation.append:type_name -> mongo.proto.kvupdate.v1.Append
+	5,  // 7: mongo.proto.kvupdate.v1.UpdateOperation.remove:type_name -> mongo.proto.kvupdate.v1.Remove
+	0,  // 8: mongo.proto.kvupdate.v1.KvUpdateRequest.ref:type_name -> mongo.proto.kvupdate.v1.ValueRef
+	6,  // 9: mongo.proto.kvupdate.v1.KvUpdateRequest.operations:type_name -> mongo.proto.kvupdate.v1.UpdateOperation
+	15, // 10: mongo.proto.kvupdate.v1.KvUpdateResponse.content:type_name -> google.protobuf.Struct
+	0,  // 11: mongo.proto.kvupdate.v1.KvUpdateMergePatchRequest.ref:type_name -> mongo.proto.kvupdate.v1.ValueRef
+	15, // 12: mongo.proto.kvupdate.v1.KvUpdateMergePatchRequest.patch:type_name -> google.protobuf.Struct
+	15, // 13: mongo.proto.kvupdate.v1.KvUpdateMergePatchResponse.content:type_name -> google.protobuf.Struct
+	14, // 14: mongo.proto.kvupdate.v1.JsonPatchOperation.value:type_name -> google.protobuf.Value
+	0,  // 15: mongo.proto.kvupdate.v1.KvUpdateJsonPatchRequest.ref:type_name -> mongo.proto.kvupdate.v1.ValueRef
+	11, // 16: mongo.proto.kvupdate.v1.KvUpdateJsonPatchRequest.operations:type_name -> mongo.proto.kvupdate.v1.JsonPatchOperation
+	15, // 17: mongo.proto.kvupdate.v1.KvUpdateJsonPatchResponse.content:type_name -> google.protobuf.Struct
+	7,  // 18: mongo.proto.kvupdate.v1.KvUpdate.Update:input_type -> mongo.proto.kvupdate.v1.KvUpdateRequest
+	9,  // 19: mongo.proto.kvupdate.v1.KvUpdate.MergePatch:input_type -> mongo.proto.kvupdate.v1.KvUpdateMergePatchRequest
+	12, // 20: mongo.proto.kvupdate.v1.KvUpdate.JsonPatch:input_type -> mongo.proto.kvupdate.v1.KvUpdateJsonPatchRequest
+	8,  // 21: mongo.proto.kvupdate.v1.KvUpdate.Update:output_type -> mongo.proto.kvupdate.v1.KvUpdateResponse
+	10, // 22: mongo.proto.kvupdate.v1.KvUpdate.MergePatch:output_type -> mongo.proto.kvupdate.v1.KvUpdateMergePatchResponse
+	13, // 23: mongo.proto.kvupdate.v1.KvUpdate.JsonPatch:output_type -> mongo.proto.kvupdate.v1.KvUpdateJsonPatchResponse
+	21, // [21:24] is the sub-list for method output_type
+	18, // [18:21] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_kvupdate_v1_kvupdate_proto_init() }
+func file_mongo_proto_kvupdate_v1_kvupdate_proto_init() {
+	if File_mongo_proto_kvupdate_v1_kvupdate_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValueRef); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Increment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Set); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Unset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Append); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Remove); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateOperation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvUpdateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvUpdateMergePatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvUpdateMergePatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JsonPatchOperation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvUpdateJsonPatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KvUpdateJsonPatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*UpdateOperation_Increment)(nil),
+		(*UpdateOperation_Set)(nil),
+		(*UpdateOperation_Unset)(nil),
+		(*UpdateOperation_Append)(nil),
+		(*UpdateOperation_Remove)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_kvupdate_v1_kvupdate_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_kvupdate_v1_kvupdate_proto_depIdxs,
+		MessageInfos:      file_mongo_proto_kvupdate_v1_kvupdate_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_kvupdate_v1_kvupdate_proto = out.File
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_rawDesc = nil
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_goTypes = nil
+	file_mongo_proto_kvupdate_v1_kvupdate_proto_depIdxs = nil
+}
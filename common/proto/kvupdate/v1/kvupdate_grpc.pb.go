@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/kvupdate/v1/kvupdate.proto
+
+package kvupdatepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	KvUpdate_Update_FullMethodName     = "/mongo.proto.kvupdate.v1.KvUpdate/Update"
+	KvUpdate_MergePatch_FullMethodName = "/mongo.proto.kvupdate.v1.KvUpdate/MergePatch"
+	KvUpdate_JsonPatch_FullMethodName  = "/mongo.proto.kvupdate.v1.KvUpdate/JsonPatch"
+)
+
+// KvUpdateClient is the client API for KvUpdate service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KvUpdateClient interface {
+	// Apply update operations to a value in a single atomic write
+	Update(ctx context.Context, in *KvUpdateRequest, opts ...grpc.CallOption) (*KvUpdateResponse, error)
+	// Apply a JSON Merge Patch (RFC 7386) to a value
+	MergePatch(ctx context.Context, in *KvUpdateMergePatchRequest, opts ...grpc.CallOption) (*KvUpdateMergePatchResponse, error)
+	// Apply a JSON Patch (RFC 6902) to a value
+	JsonPatch(ctx context.Context, in *KvUpdateJsonPatchRequest, opts ...grpc.CallOption) (*KvUpdateJsonPatchResponse, error)
+}
+
+type kvUpdateClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKvUpdateClient(cc grpc.ClientConnInterface) KvUpdateClient {
+	return &kvUpdateClient{cc}
+}
+
+func (c *kvUpdateClient) Update(ctx context.Context, in *KvUpdateRequest, opts ...grpc.CallOption) (*KvUpdateResponse, error) {
+	out := new(KvUpdateResponse)
+	err := c.cc.Invoke(ctx, KvUpdate_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kvUpdateClient) MergePatch(ctx context.Context, in *KvUpdateMergePatchRequest, opts ...grpc.CallOption) (*KvUpdateMergePatchResponse, error) {
+	out := new(KvUpdateMergePatchResponse)
+	err := c.cc.Invoke(ctx, KvUpdate_MergePatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kvUpdateClient) JsonPatch(ctx context.Context, in *KvUpdateJsonPatchRequest, opts ...grpc.CallOption) (*KvUpdateJsonPatchResponse, error) {
+	out := new(KvUpdateJsonPatchResponse)
+	err := c.cc.Invoke(ctx, KvUpdate_JsonPatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KvUpdateServer is the server API for KvUpdate service.
+// All implementations should embed UnimplementedKvUpdateServer
+// for forward compatibility
+type KvUpdateServer interface {
+	// Apply update operations to a value in a single atomic write
+	Update(context.Context, *KvUpdateRequest) (*KvUpdateResponse, error)
+	// Apply a JSON Merge Patch (RFC 7386) to a value
+	MergePatch(context.Context, *KvUpdateMergePatchRequest) (*KvUpdateMergePatchResponse, error)
+	// Apply a JSON Patch (RFC 6902) to a value
+	JsonPatch(context.Context, *KvUpdateJsonPatchRequest) (*KvUpdateJsonPatchResponse, error)
+}
+
+// UnimplementedKvUpdateServer should be embedded to have forward compatible implementations.
+type UnimplementedKvUpdateServer struct {
+}
+
+func (UnimplementedKvUpdateServer) Update(context.Context, *KvUpdateRequest) (*KvUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedKvUpdateServer) MergePatch(context.Context, *KvUpdateMergePatchRequest) (*KvUpdateMergePatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergePatch not implemented")
+}
+func (UnimplementedKvUpdateServer) JsonPatch(context.Context, *KvUpdateJsonPatchRequest) (*KvUpdateJsonPatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JsonPatch not implemented")
+}
+
+// UnsafeKvUpdateServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KvUpdateServer will
+// result in compilation errors.
+type UnsafeKvUpdateServer interface {
+	mustEmbedUnimplementedKvUpdateServer()
+}
+
+func RegisterKvUpdateServer(s grpc.ServiceRegistrar, srv KvUpdateServer) {
+	s.RegisterService(&KvUpdate_ServiceDesc, srv)
+}
+
+func _KvUpdate_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KvUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KvUpdateServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KvUpdate_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KvUpdateServer).Update(ctx, req.(*KvUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KvUpdate_MergePatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KvUpdateMergePatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KvUpdateServer).MergePatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KvUpdate_MergePatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KvUpdateServer).MergePatch(ctx, req.(*KvUpdateMergePatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KvUpdate_JsonPatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KvUpdateJsonPatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KvUpdateServer).JsonPatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KvUpdate_JsonPatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KvUpdateServer).JsonPatch(ctx, req.(*KvUpdateJsonPatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KvUpdate_ServiceDesc is the grpc.ServiceDesc for KvUpdate service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KvUpdate_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.kvupdate.v1.KvUpdate",
+	HandlerType: (*KvUpdateServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Update",
+			Handler:    _KvUpdate_Update_Handler,
+		},
+		{
+			MethodName: "MergePatch",
+			Handler:    _KvUpdate_MergePatch_Handler,
+		},
+		{
+			MethodName: "JsonPatch",
+			Handler:    _KvUpdate_JsonPatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/kvupdate/v1/kvupdate.proto",
+}
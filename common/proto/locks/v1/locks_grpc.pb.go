@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/locks/v1/locks.proto
+
+package lockspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Locks_Acquire_FullMethodName    = "/mongo.proto.locks.v1.Locks/Acquire"
+	Locks_TryAcquire_FullMethodName = "/mongo.proto.locks.v1.Locks/TryAcquire"
+	Locks_Renew_FullMethodName      = "/mongo.proto.locks.v1.Locks/Renew"
+	Locks_Release_FullMethodName    = "/mongo.proto.locks.v1.Locks/Release"
+)
+
+// LocksClient is the client API for Locks service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LocksClient interface {
+	// Acquire a lock, waiting until it is available or the wait timeout elapses
+	Acquire(ctx context.Context, in *LocksAcquireRequest, opts ...grpc.CallOption) (*LocksAcquireResponse, error)
+	// Acquire a lock only if it is immediately available
+	TryAcquire(ctx context.Context, in *LocksTryAcquireRequest, opts ...grpc.CallOption) (*LocksTryAcquireResponse, error)
+	// Extend the expiry of a held lock
+	Renew(ctx context.Context, in *LocksRenewRequest, opts ...grpc.CallOption) (*LocksRenewResponse, error)
+	// Release a held lock
+	Release(ctx context.Context, in *LocksReleaseRequest, opts ...grpc.CallOption) (*LocksReleaseResponse, error)
+}
+
+type locksClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLocksClient(cc grpc.ClientConnInterface) LocksClient {
+	return &locksClient{cc}
+}
+
+func (c *locksClient) Acquire(ctx context.Context, in *LocksAcquireRequest, opts ...grpc.CallOption) (*LocksAcquireResponse, error) {
+	out := new(LocksAcquireResponse)
+	err := c.cc.Invoke(ctx, Locks_Acquire_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locksClient) TryAcquire(ctx context.Context, in *LocksTryAcquireRequest, opts ...grpc.CallOption) (*LocksTryAcquireResponse, error) {
+	out := new(LocksTryAcquireResponse)
+	err := c.cc.Invoke(ctx, Locks_TryAcquire_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locksClient) Renew(ctx context.Context, in *LocksRenewRequest, opts ...grpc.CallOption) (*LocksRenewResponse, error) {
+	out := new(LocksRenewResponse)
+	err := c.cc.Invoke(ctx, Locks_Renew_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locksClient) Release(ctx context.Context, in *LocksReleaseRequest, opts ...grpc.CallOption) (*LocksReleaseResponse, error) {
+	out := new(LocksReleaseResponse)
+	err := c.cc.Invoke(ctx, Locks_Release_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LocksServer is the server API for Locks service.
+// All implementations should embed UnimplementedLocksServer
+// for forward compatibility
+type LocksServer interface {
+	// Acquire a lock, waiting until it is available or the wait timeout elapses
+	Acquire(context.Context, *LocksAcquireRequest) (*LocksAcquireResponse, error)
+	// Acquire a lock only if it is immediately available
+	TryAcquire(context.Context, *LocksTryAcquireRequest) (*LocksTryAcquireResponse, error)
+	// Extend the expiry of a held lock
+	Renew(context.Context, *LocksRenewRequest) (*LocksRenewResponse, error)
+	// Release a held lock
+	Release(context.Context, *LocksReleaseRequest) (*LocksReleaseResponse, error)
+}
+
+// UnimplementedLocksServer should be embedded to have forward compatible implementations.
+type UnimplementedLocksServer struct {
+}
+
+func (UnimplementedLocksServer) Acquire(context.Context, *LocksAcquireRequest) (*LocksAcquireResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Acquire not implemented")
+}
+func (UnimplementedLocksServer) TryAcquire(context.Context, *LocksTryAcquireRequest) (*LocksTryAcquireResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TryAcquire not implemented")
+}
+func (UnimplementedLocksServer) Renew(context.Context, *LocksRenewRequest) (*LocksRenewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Renew not implemented")
+}
+func (UnimplementedLocksServer) Release(context.Context, *LocksReleaseRequest) (*LocksReleaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+
+// UnsafeLocksServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LocksServer will
+// result in compilation errors.
+type UnsafeLocksServer interface {
+	mustEmbedUnimplementedLocksServer()
+}
+
+func RegisterLocksServer(s grpc.ServiceRegistrar, srv LocksServer) {
+	s.RegisterService(&Locks_ServiceDesc, srv)
+}
+
+func _Locks_Acquire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocksAcquireRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocksServer).Acquire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Locks_Acquire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocksServer).Acquire(ctx, req.(*LocksAcquireRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Locks_TryAcquire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocksTryAcquireRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocksServer).TryAcquire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Locks_TryAcquire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocksServer).TryAcquire(ctx, req.(*LocksTryAcquireRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Locks_Renew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocksRenewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocksServer).Renew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Locks_Renew_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocksServer).Renew(ctx, req.(*LocksRenewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Locks_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocksReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocksServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Locks_Release_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocksServer).Release(ctx, req.(*LocksReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Locks_ServiceDesc is the grpc.ServiceDesc for Locks service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Locks_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.locks.v1.Locks",
+	HandlerType: (*LocksServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Acquire",
+			Handler:    _Locks_Acquire_Handler,
+		},
+		{
+			MethodName: "TryAcquire",
+			Handler:    _Locks_TryAcquire_Handler,
+		},
+		{
+			MethodName: "Renew",
+			Handler:    _Locks_Renew_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _Locks_Release_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/locks/v1/locks.proto",
+}
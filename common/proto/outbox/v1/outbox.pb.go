@@ -0,0 +1,605 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/outbox/v1/outbox.proto
+
+package outboxpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Set the value of a key in a store
+type OutboxSetValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The store containing the key
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The key to set
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// The new value
+	Content *structpb.Struct `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *OutboxSetValue) Reset() {
+	*x = OutboxSetValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutboxSetValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxSetValue) ProtoMessage() {}
+
+func (x *OutboxSetValue) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxSetValue.ProtoReflect.Descriptor instead.
+func (*OutboxSetValue) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OutboxSetValue) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *OutboxSetValue) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *OutboxSetValue) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+// Delete a key from a store
+type OutboxDeleteKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The store containing the key
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The key to delete
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *OutboxDeleteKey) Reset() {
+	*x = OutboxDeleteKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutboxDeleteKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxDeleteKey) ProtoMessage() {}
+
+func (x *OutboxDeleteKey) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxDeleteKey.ProtoReflect.Descriptor instead.
+func (*OutboxDeleteKey) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OutboxDeleteKey) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *OutboxDeleteKey) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// A key value write applied in the transaction
+type OutboxWrite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Write:
+	//	*OutboxWrite_Set
+	//	*OutboxWrite_Delete
+	Write isOutboxWrite_Write `protobuf_oneof:"write"`
+}
+
+func (x *OutboxWrite) Reset() {
+	*x = OutboxWrite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutboxWrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxWrite) ProtoMessage() {}
+
+func (x *OutboxWrite) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxWrite.ProtoReflect.Descriptor instead.
+func (*OutboxWrite) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *OutboxWrite) GetWrite() isOutboxWrite_Write {
+	if m != nil {
+		return m.Write
+	}
+	return nil
+}
+
+func (x *OutboxWrite) GetSet() *OutboxSetValue {
+	if x, ok := x.GetWrite().(*OutboxWrite_Set); ok {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *OutboxWrite) GetDelete() *OutboxDeleteKey {
+	if x, ok := x.GetWrite().(*OutboxWrite_Delete); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+type isOutboxWrite_Write interface {
+	isOutboxWrite_Write()
+}
+
+type OutboxWrite_Set struct {
+	Set *OutboxSetValue `protobuf:"bytes,1,opt,name=set,proto3,oneof"`
+}
+
+type OutboxWrite_Delete struct {
+	Delete *OutboxDeleteKey `protobuf:"bytes,2,opt,name=delete,proto3,oneof"`
+}
+
+func (*OutboxWrite_Set) isOutboxWrite_Write() {}
+
+func (*OutboxWrite_Delete) isOutboxWrite_Write() {}
+
+// An event published to a topic after the transaction commits
+type OutboxEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The Nitric name of the topic
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// The event payload
+	Payload *structpb.Struct `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Identifies the event to subscribers for deduplication, generated when empty
+	DeduplicationId string `protobuf:"bytes,3,opt,name=deduplication_id,json=deduplicationId,proto3" json:"deduplication_id,omitempty"`
+}
+
+func (x *OutboxEvent) Reset() {
+	*x = OutboxEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxEvent) ProtoMessage() {}
+
+func (x *OutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxEvent.ProtoReflect.Descriptor instead.
+func (*OutboxEvent) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *OutboxEvent) GetDeduplicationId() string {
+	if x != nil {
+		return x.DeduplicationId
+	}
+	return ""
+}
+
+type OutboxCommitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Writes applied in order
+	Writes []*OutboxWrite `protobuf:"bytes,1,rep,name=writes,proto3" json:"writes,omitempty"`
+	// Events recorded for publishing
+	Events []*OutboxEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *OutboxCommitRequest) Reset() {
+	*x = OutboxCommitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutboxCommitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxCommitRequest) ProtoMessage() {}
+
+func (x *OutboxCommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxCommitRequest.ProtoReflect.Descriptor instead.
+func (*OutboxCommitRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OutboxCommitRequest) GetWrites() []*OutboxWrite {
+	if x != nil {
+		return x.Writes
+	}
+	return nil
+}
+
+func (x *OutboxCommitRequest) GetEvents() []*OutboxEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type OutboxCommitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The deduplication ids of the recorded events, in request order
+	DeduplicationIds []string `protobuf:"bytes,1,rep,name=deduplication_ids,json=deduplicationIds,proto3" json:"deduplication_ids,omitempty"`
+}
+
+func (x *OutboxCommitResponse) Reset() {
+	*x = OutboxCommitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutboxCommitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxCommitResponse) ProtoMessage() {}
+
+func (x *OutboxCommitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_outbox_v1_outbox_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxCommitResponse.ProtoReflect.Descriptor instead.
+func (*OutboxCommitResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OutboxCommitResponse) GetDeduplicationIds() []string {
+	if x != nil {
+		return x.DeduplicationIds
+	}
+	return nil
+}
+
+var File_mongo_proto_outbox_v1_outbox_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_outbox_v1_outbox_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x75,
+	0x74, 0x62, 0x6f, 0x78, 0x2f, 0x76, 0x31, 0x2f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6b, 0x0a, 0x0e, 0x4f, 0x75, 0x74,
+	0x62, 0x6f, 0x78, 0x53, 0x65, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x39, 0x0a, 0x0f, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x22, 0x93, 0x01, 0x0a, 0x0b, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x57, 0x72, 0x69, 0x74,
+	0x65, 0x12, 0x39, 0x0a, 0x03, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6f, 0x75, 0x74,
+	0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x53, 0x65, 0x74,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12, 0x40, 0x0a, 0x06,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f,
+	0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x4b, 0x65, 0x79, 0x48, 0x00, 0x52, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x07,
+	0x0a, 0x05, 0x77, 0x72, 0x69, 0x74, 0x65, 0x22, 0x81, 0x01, 0x0a, 0x0b, 0x4f, 0x75, 0x74, 0x62,
+	0x6f, 0x78, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x31, 0x0a,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x12, 0x29, 0x0a, 0x10, 0x64, 0x65, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x65, 0x64, 0x75,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x8d, 0x01, 0x0a, 0x13,
+	0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x06, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x62,
+	0x6f, 0x78, 0x57, 0x72, 0x69, 0x74, 0x65, 0x52, 0x06, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x12,
+	0x3a, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6f, 0x75,
+	0x74, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x43, 0x0a, 0x14, 0x4f,
+	0x75, 0x74, 0x62, 0x6f, 0x78, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10,
+	0x64, 0x65, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73,
+	0x32, 0x6b, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x12, 0x61, 0x0a, 0x06, 0x43, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x12, 0x2a, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74,
+	0x62, 0x6f, 0x78, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x2b, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6f,
+	0x75, 0x74, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x48, 0x5a,
+	0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x69, 0x74, 0x72,
+	0x69, 0x63, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64, 0x62, 0x2d, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x2f, 0x76, 0x31, 0x3b, 0x6f,
+	0x75, 0x74, 0x62, 0x6f, 0x78, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mongo_proto_outbox_v1_outbox_proto_rawDescOnce sync.Once
+	file_mongo_proto_outbox_v1_outbox_proto_rawDescData = file_mongo_proto_outbox_v1_outbox_proto_rawDesc
+)
+
+func file_mongo_proto_outbox_v1_outbox_proto_rawDescGZIP() []byte {
+	file_mongo_proto_outbox_v1_outbox_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_outbox_v1_outbox_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_outbox_v1_outbox_proto_rawDescData)
+	})
+	return file_mongo_proto_outbox_v1_outbox_proto_rawDescData
+}
+
+var file_mongo_proto_outbox_v1_outbox_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_mongo_proto_outbox_v1_outbox_proto_goTypes = []interface{}{
+	(*OutboxSetValue)(nil),       // 0: mongo.proto.outbox.v1.OutboxSetValue
+	(*OutboxDeleteKey)(nil),      // 1: mongo.proto.outbox.v1.OutboxDeleteKey
+	(*OutboxWrite)(nil),          // 2: mongo.proto.outbox.v1.OutboxWrite
+	(*OutboxEvent)(nil),          // 3: mongo.proto.outbox.v1.OutboxEvent
+	(*OutboxCommitRequest)(nil),  // 4: mongo.proto.outbox.v1.OutboxCommitRequest
+	(*OutboxCommitResponse)(nil), // 5: mongo.proto.outbox.v1.OutboxCommitResponse
+	(*structpb.Struct)(nil),      // 6: google.protobuf.Struct
+}
+var file_mongo_proto_outbox_v1_outbox_proto_depIdxs = []int32{
+	6, // 0: mongo.proto.outbox.v1.OutboxSetValue.content:type_name -> google.protobuf.Struct
+	0, // 1: mongo.proto.outbox.v1.OutboxWrite.set:type_name -> mongo.proto.outbox.v1.OutboxSetValue
+	1, // 2: mongo.proto.outbox.v1.OutboxWrite.delete:type_name -> mongo.proto.outbox.v1.OutboxDeleteKey
+	6, // 3: mongo.proto.outbox.v1.OutboxEvent.payload:type_name -> google.protobuf.Struct
+	2, // 4: mongo.proto.outbox.v1.OutboxCommitRequest.writes:type_name -> mongo.proto.outbox.v1.OutboxWrite
+	3, // 5: mongo.proto.outbox.v1.OutboxCommitRequest.events:type_name -> mongo.proto.outbox.v1.OutboxEvent
+	4, // 6: mongo.proto.outbox.v1.Outbox.Commit:input_type -> mongo.proto.outbox.v1.OutboxCommitRequest
+	5, // 7: mongo.proto.outbox.v1.Outbox.Commit:output_type -> mongo.proto.outbox.v1.OutboxCommitResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_outbox_v1_outbox_proto_init() }
+func file_mongo_proto_outbox_v1_outbox_proto_init() {
+	if File_mongo_proto_outbox_v1_outbox_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_outbox_v1_outbox_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutboxSetValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_outbox_v1_outbox_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutboxDeleteKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_outbox_v1_outbox_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutboxWrite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_outbox_v1_outbox_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutboxEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_outbox_v1_outbox_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutboxCommitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_outbox_v1_outbox_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutboxCommitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_mongo_proto_outbox_v1_outbox_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*OutboxWrite_Set)(nil),
+		(*OutboxWrite_Delete)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_outbox_v1_outbox_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_outbox_v1_outbox_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_outbox_v1_outbox_proto_depIdxs,
+		MessageInfos:      file_mongo_proto_outbox_v1_outbox_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_outbox_v1_outbox_proto = out.File
+	file_mongo_proto_outbox_v1_outbox_proto_rawDesc = nil
+	file_mongo_proto_outbox_v1_outbox_proto_goTypes = nil
+	file_mongo_proto_outbox_v1_outbox_proto_depIdxs = nil
+}
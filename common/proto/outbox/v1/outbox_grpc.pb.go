@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/outbox/v1/outbox.proto
+
+package outboxpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Outbox_Commit_FullMethodName = "/mongo.proto.outbox.v1.Outbox/Commit"
+)
+
+// OutboxClient is the client API for Outbox service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OutboxClient interface {
+	// Apply key value writes and record events in a single transaction,
+	// the events are published to their topics once the transaction commits
+	Commit(ctx context.Context, in *OutboxCommitRequest, opts ...grpc.CallOption) (*OutboxCommitResponse, error)
+}
+
+type outboxClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutboxClient(cc grpc.ClientConnInterface) OutboxClient {
+	return &outboxClient{cc}
+}
+
+func (c *outboxClient) Commit(ctx context.Context, in *OutboxCommitRequest, opts ...grpc.CallOption) (*OutboxCommitResponse, error) {
+	out := new(OutboxCommitResponse)
+	err := c.cc.Invoke(ctx, Outbox_Commit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OutboxServer is the server API for Outbox service.
+// All implementations should embed UnimplementedOutboxServer
+// for forward compatibility
+type OutboxServer interface {
+	// Apply key value writes and record events in a single transaction,
+	// the events are published to their topics once the transaction commits
+	Commit(context.Context, *OutboxCommitRequest) (*OutboxCommitResponse, error)
+}
+
+// UnimplementedOutboxServer should be embedded to have forward compatible implementations.
+type UnimplementedOutboxServer struct {
+}
+
+func (UnimplementedOutboxServer) Commit(context.Context, *OutboxCommitRequest) (*OutboxCommitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
+
+// UnsafeOutboxServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OutboxServer will
+// result in compilation errors.
+type UnsafeOutboxServer interface {
+	mustEmbedUnimplementedOutboxServer()
+}
+
+func RegisterOutboxServer(s grpc.ServiceRegistrar, srv OutboxServer) {
+	s.RegisterService(&Outbox_ServiceDesc, srv)
+}
+
+func _Outbox_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutboxCommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutboxServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outbox_Commit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutboxServer).Commit(ctx, req.(*OutboxCommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Outbox_ServiceDesc is the grpc.ServiceDesc for Outbox service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Outbox_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.outbox.v1.Outbox",
+	HandlerType: (*OutboxServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Commit",
+			Handler:    _Outbox_Commit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/outbox/v1/outbox.proto",
+}
@@ -0,0 +1,706 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/snapshots/v1/snapshots.proto
+
+package snapshotspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RestoreMode int32
+
+const (
+	// Replace the target stores with the snapshot
+	RestoreMode_Apply RestoreMode = 0
+	// Compare the snapshot with the target stores without changing them
+	RestoreMode_DryRun RestoreMode = 1
+)
+
+// Enum value maps for RestoreMode.
+var (
+	RestoreMode_name = map[int32]string{
+		0: "Apply",
+		1: "DryRun",
+	}
+	RestoreMode_value = map[string]int32{
+		"Apply":  0,
+		"DryRun": 1,
+	}
+)
+
+func (x RestoreMode) Enum() *RestoreMode {
+	p := new(RestoreMode)
+	*p = x
+	return p
+}
+
+func (x RestoreMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RestoreMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_enumTypes[0].Descriptor()
+}
+
+func (RestoreMode) Type() protoreflect.EnumType {
+	return &file_mongo_proto_snapshots_v1_snapshots_proto_enumTypes[0]
+}
+
+func (x RestoreMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RestoreMode.Descriptor instead.
+func (RestoreMode) EnumDescriptor() ([]byte, []int) {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP(), []int{0}
+}
+
+// A store to restore from the snapshot
+type StoreRestore struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the store in the snapshot
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The store to restore into, defaults to the snapshot store name
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+}
+
+func (x *StoreRestore) Reset() {
+	*x = StoreRestore{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreRestore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreRestore) ProtoMessage() {}
+
+func (x *StoreRestore) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreRestore.ProtoReflect.Descriptor instead.
+func (*StoreRestore) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StoreRestore) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *StoreRestore) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type SnapshotsRestoreRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The bucket the snapshot was written to
+	Bucket string `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	// The id of the snapshot, e.g. 20261019T0300Z
+	SnapshotId string `protobuf:"bytes,2,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	// The stores to restore, defaults to every store in the snapshot restored in place
+	Stores []*StoreRestore `protobuf:"bytes,3,rep,name=stores,proto3" json:"stores,omitempty"`
+	// Whether to apply the restore or only compare
+	Mode RestoreMode `protobuf:"varint,4,opt,name=mode,proto3,enum=mongo.proto.snapshots.v1.RestoreMode" json:"mode,omitempty"`
+	// The id of an interrupted restore to resume
+	RestoreId string `protobuf:"bytes,5,opt,name=restore_id,json=restoreId,proto3" json:"restore_id,omitempty"`
+}
+
+func (x *SnapshotsRestoreRequest) Reset() {
+	*x = SnapshotsRestoreRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotsRestoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotsRestoreRequest) ProtoMessage() {}
+
+func (x *SnapshotsRestoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotsRestoreRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotsRestoreRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SnapshotsRestoreRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *SnapshotsRestoreRequest) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+func (x *SnapshotsRestoreRequest) GetStores() []*StoreRestore {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+func (x *SnapshotsRestoreRequest) GetMode() RestoreMode {
+	if x != nil {
+		return x.Mode
+	}
+	return RestoreMode_Apply
+}
+
+func (x *SnapshotsRestoreRequest) GetRestoreId() string {
+	if x != nil {
+		return x.RestoreId
+	}
+	return ""
+}
+
+// Progress of a store being restored
+type StoreProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the store in the snapshot
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The store being restored into
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// The number of documents in the snapshot
+	Documents int64 `protobuf:"varint,3,opt,name=documents,proto3" json:"documents,omitempty"`
+	// The number of documents loaded so far
+	Restored int64 `protobuf:"varint,4,opt,name=restored,proto3" json:"restored,omitempty"`
+	// Whether the target store has been replaced
+	Completed bool `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
+}
+
+func (x *StoreProgress) Reset() {
+	*x = StoreProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreProgress) ProtoMessage() {}
+
+func (x *StoreProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreProgress.ProtoReflect.Descriptor instead.
+func (*StoreProgress) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StoreProgress) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *StoreProgress) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *StoreProgress) GetDocuments() int64 {
+	if x != nil {
+		return x.Documents
+	}
+	return 0
+}
+
+func (x *StoreProgress) GetRestored() int64 {
+	if x != nil {
+		return x.Restored
+	}
+	return 0
+}
+
+func (x *StoreProgress) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+// Differences between a store in the snapshot and the target store
+type StoreDiff struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the store in the snapshot
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The store compared against
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Values in the snapshot that aren't in the target
+	Added int64 `protobuf:"varint,3,opt,name=added,proto3" json:"added,omitempty"`
+	// Values whose content differs
+	Changed int64 `protobuf:"varint,4,opt,name=changed,proto3" json:"changed,omitempty"`
+	// Values with the same content
+	Unchanged int64 `protobuf:"varint,5,opt,name=unchanged,proto3" json:"unchanged,omitempty"`
+	// Values in the target that aren't in the snapshot
+	Removed int64 `protobuf:"varint,6,opt,name=removed,proto3" json:"removed,omitempty"`
+	// A sample of the added keys
+	AddedKeys []string `protobuf:"bytes,7,rep,name=added_keys,json=addedKeys,proto3" json:"added_keys,omitempty"`
+	// A sample of the changed keys
+	ChangedKeys []string `protobuf:"bytes,8,rep,name=changed_keys,json=changedKeys,proto3" json:"changed_keys,omitempty"`
+}
+
+func (x *StoreDiff) Reset() {
+	*x = StoreDiff{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreDiff) ProtoMessage() {}
+
+func (x *StoreDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreDiff.ProtoReflect.Descriptor instead.
+func (*StoreDiff) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StoreDiff) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *StoreDiff) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *StoreDiff) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *StoreDiff) GetChanged() int64 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+func (x *StoreDiff) GetUnchanged() int64 {
+	if x != nil {
+		return x.Unchanged
+	}
+	return 0
+}
+
+func (x *StoreDiff) GetRemoved() int64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *StoreDiff) GetAddedKeys() []string {
+	if x != nil {
+		return x.AddedKeys
+	}
+	return nil
+}
+
+func (x *StoreDiff) GetChangedKeys() []string {
+	if x != nil {
+		return x.ChangedKeys
+	}
+	return nil
+}
+
+type SnapshotsRestoreResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The id to resume the restore with if it is interrupted, unset for dry runs
+	RestoreId string `protobuf:"bytes,1,opt,name=restore_id,json=restoreId,proto3" json:"restore_id,omitempty"`
+	// Types that are assignable to Update:
+	//	*SnapshotsRestoreResponse_Progress
+	//	*SnapshotsRestoreResponse_Diff
+	Update isSnapshotsRestoreResponse_Update `protobuf_oneof:"update"`
+}
+
+func (x *SnapshotsRestoreResponse) Reset() {
+	*x = SnapshotsRestoreResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotsRestoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotsRestoreResponse) ProtoMessage() {}
+
+func (x *SnapshotsRestoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotsRestoreResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotsRestoreResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SnapshotsRestoreResponse) GetRestoreId() string {
+	if x != nil {
+		return x.RestoreId
+	}
+	return ""
+}
+
+func (m *SnapshotsRestoreResponse) GetUpdate() isSnapshotsRestoreResponse_Update {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+func (x *SnapshotsRestoreResponse) GetProgress() *StoreProgress {
+	if x, ok := x.GetUpdate().(*SnapshotsRestoreResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *SnapshotsRestoreResponse) GetDiff() *StoreDiff {
+	if x, ok := x.GetUpdate().(*SnapshotsRestoreResponse_Diff); ok {
+		return x.Diff
+	}
+	return nil
+}
+
+type isSnapshotsRestoreResponse_Update interface {
+	isSnapshotsRestoreResponse_Update()
+}
+
+type SnapshotsRestoreResponse_Progress struct {
+	Progress *StoreProgress `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+type SnapshotsRestoreResponse_Diff struct {
+	Diff *StoreDiff `protobuf:"bytes,3,opt,name=diff,proto3,oneof"`
+}
+
+func (*SnapshotsRestoreResponse_Progress) isSnapshotsRestoreResponse_Update() {}
+
+func (*SnapshotsRestoreResponse_Diff) isSnapshotsRestoreResponse_Update() {}
+
+var File_mongo_proto_snapshots_v1_snapshots_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_snapshots_v1_snapshots_proto_rawDesc = []byte{
+	0x0a, 0x28, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x6d, 0x6f, 0x6e, 0x67,
+	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x2e, 0x76, 0x31, 0x22, 0x3c, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x22, 0xec, 0x01, 0x0a, 0x17, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x3e, 0x0a, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52,
+	0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x25, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x49,
+	0x64, 0x22, 0x95, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0xe3, 0x01, 0x0a, 0x09, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x44, 0x69, 0x66, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x22,
+	0xc5, 0x01, 0x0a, 0x18, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x08, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e,
+	0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x39, 0x0a, 0x04, 0x64, 0x69, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x44, 0x69, 0x66, 0x66, 0x48, 0x00, 0x52, 0x04, 0x64, 0x69, 0x66, 0x66, 0x42, 0x08, 0x0a,
+	0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2a, 0x24, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x10,
+	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x10, 0x01, 0x32, 0x7f, 0x0a,
+	0x09, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x72, 0x0a, 0x07, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x31, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x4e,
+	0x5a, 0x4c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x69, 0x74,
+	0x72, 0x69, 0x63, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64, 0x62, 0x2d,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2f,
+	0x76, 0x31, 0x3b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mongo_proto_snapshots_v1_snapshots_proto_rawDescOnce sync.Once
+	file_mongo_proto_snapshots_v1_snapshots_proto_rawDescData = file_mongo_proto_snapshots_v1_snapshots_proto_rawDesc
+)
+
+func file_mongo_proto_snapshots_v1_snapshots_proto_rawDescGZIP() []byte {
+	file_mongo_proto_snapshots_v1_snapshots_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_snapshots_v1_snapshots_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_snapshots_v1_snapshots_proto_rawDescData)
+	})
+	return file_mongo_proto_snapshots_v1_snapshots_proto_rawDescData
+}
+
+var file_mongo_proto_snapshots_v1_snapshots_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_mongo_proto_snapshots_v1_snapshots_proto_goTypes = []interface{}{
+	(RestoreMode)(0),                 // 0: mongo.proto.snapshots.v1.RestoreMode
+	(*StoreRestore)(nil),             // 1: mongo.proto.snapshots.v1.StoreRestore
+	(*SnapshotsRestoreRequest)(nil),  // 2: mongo.proto.snapshots.v1.SnapshotsRestoreRequest
+	(*StoreProgress)(nil),            // 3: mongo.proto.snapshots.v1.StoreProgress
+	(*StoreDiff)(nil),                // 4: mongo.proto.snapshots.v1.StoreDiff
+	(*SnapshotsRestoreResponse)(nil), // 5: mongo.proto.snapshots.v1.SnapshotsRestoreResponse
+}
+var file_mongo_proto_snapshots_v1_snapshots_proto_depIdxs = []int32{
+	1, // 0: mongo.proto.snapshots.v1.SnapshotsRestoreRequest.stores:type_name -> mongo.proto.snapshots.v1.StoreRestore
+	0, // 1: mongo.proto.snapshots.v1.SnapshotsRestoreRequest.mode:type_name -> mongo.proto.snapshots.v1.RestoreMode
+	3, // 2: mongo.proto.snapshots.v1.SnapshotsRestoreResponse.progress:type_name -> mongo.proto.snapshots.v1.StoreProgress
+	4, // 3: mongo.proto.snapshots.v1.SnapshotsRestoreResponse.diff:type_name -> mongo.proto.snapshots.v1.StoreDiff
+	2, // 4: mongo.proto.snapshots.v1.Snapshots.Restore:input_type -> mongo.proto.snapshots.v1.SnapshotsRestoreRequest
+	5, // 5: mongo.proto.snapshots.v1.Snapshots.Restore:output_type -> mongo.proto.snapshots.v1.SnapshotsRestoreResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_snapshots_v1_snapshots_proto_init() }
+func file_mongo_proto_snapshots_v1_snapshots_proto_init() {
+	if File_mongo_proto_snapshots_v1_snapshots_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreRestore); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotsRestoreRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreDiff); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotsRestoreResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*SnapshotsRestoreResponse_Progress)(nil),
+		(*SnapshotsRestoreResponse_Diff)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_snapshots_v1_snapshots_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_snapshots_v1_snapshots_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_snapshots_v1_snapshots_proto_depIdxs,
+		EnumInfos:         file_mongo_proto_snapshots_v1_snapshots_proto_enumTypes,
+		MessageInfos:      file_mongo_proto_snapshots_v1_snapshots_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_snapshots_v1_snapshots_proto = out.File
+	file_mongo_proto_snapshots_v1_snapshots_proto_rawDesc = nil
+	file_mongo_proto_snapshots_v1_snapshots_proto_goTypes = nil
+	file_mongo_proto_snapshots_v1_snapshots_proto_depIdxs = nil
+}
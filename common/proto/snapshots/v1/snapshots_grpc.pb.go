@@ -0,0 +1,136 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/snapshots/v1/snapshots.proto
+
+package snapshotspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Snapshots_Restore_FullMethodName = "/mongo.proto.snapshots.v1.Snapshots/Restore"
+)
+
+// SnapshotsClient is the client API for Snapshots service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SnapshotsClient interface {
+	// Restore key value stores from a snapshot, streaming progress as documents are loaded
+	Restore(ctx context.Context, in *SnapshotsRestoreRequest, opts ...grpc.CallOption) (Snapshots_RestoreClient, error)
+}
+
+type snapshotsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSnapshotsClient(cc grpc.ClientConnInterface) SnapshotsClient {
+	return &snapshotsClient{cc}
+}
+
+func (c *snapshotsClient) Restore(ctx context.Context, in *SnapshotsRestoreRequest, opts ...grpc.CallOption) (Snapshots_RestoreClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Snapshots_ServiceDesc.Streams[0], Snapshots_Restore_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &snapshotsRestoreClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Snapshots_RestoreClient interface {
+	Recv() (*SnapshotsRestoreResponse, error)
+	grpc.ClientStream
+}
+
+type snapshotsRestoreClient struct {
+	grpc.ClientStream
+}
+
+func (x *snapshotsRestoreClient) Recv() (*SnapshotsRestoreResponse, error) {
+	m := new(SnapshotsRestoreResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SnapshotsServer is the server API for Snapshots service.
+// All implementations should embed UnimplementedSnapshotsServer
+// for forward compatibility
+type SnapshotsServer interface {
+	// Restore key value stores from a snapshot, streaming progress as documents are loaded
+	Restore(*SnapshotsRestoreRequest, Snapshots_RestoreServer) error
+}
+
+// UnimplementedSnapshotsServer should be embedded to have forward compatible implementations.
+type UnimplementedSnapshotsServer struct {
+}
+
+func (UnimplementedSnapshotsServer) Restore(*SnapshotsRestoreRequest, Snapshots_RestoreServer) error {
+	return status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+
+// UnsafeSnapshotsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SnapshotsServer will
+// result in compilation errors.
+type UnsafeSnapshotsServer interface {
+	mustEmbedUnimplementedSnapshotsServer()
+}
+
+func RegisterSnapshotsServer(s grpc.ServiceRegistrar, srv SnapshotsServer) {
+	s.RegisterService(&Snapshots_ServiceDesc, srv)
+}
+
+func _Snapshots_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SnapshotsRestoreRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SnapshotsServer).Restore(m, &snapshotsRestoreServer{stream})
+}
+
+type Snapshots_RestoreServer interface {
+	Send(*SnapshotsRestoreResponse) error
+	grpc.ServerStream
+}
+
+type snapshotsRestoreServer struct {
+	grpc.ServerStream
+}
+
+func (x *snapshotsRestoreServer) Send(m *SnapshotsRestoreResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Snapshots_ServiceDesc is the grpc.ServiceDesc for Snapshots service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Snapshots_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.snapshots.v1.Snapshots",
+	HandlerType: (*SnapshotsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Restore",
+			Handler:       _Snapshots_Restore_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mongo/proto/snapshots/v1/snapshots.proto",
+}
@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/tenants/v1/tenants.proto
+
+package tenantspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Tenants_Export_FullMethodName = "/mongo.proto.tenants.v1.Tenants/Export"
+	Tenants_Delete_FullMethodName = "/mongo.proto.tenants.v1.Tenants/Delete"
+)
+
+// TenantsClient is the client API for Tenants service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TenantsClient interface {
+	// Stream every value the tenant has in the key value stores
+	Export(ctx context.Context, in *TenantsExportRequest, opts ...grpc.CallOption) (Tenants_ExportClient, error)
+	// Delete every value the tenant has in the key value stores
+	Delete(ctx context.Context, in *TenantsDeleteRequest, opts ...grpc.CallOption) (*TenantsDeleteResponse, error)
+}
+
+type tenantsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTenantsClient(cc grpc.ClientConnInterface) TenantsClient {
+	return &tenantsClient{cc}
+}
+
+func (c *tenantsClient) Export(ctx context.Context, in *TenantsExportRequest, opts ...grpc.CallOption) (Tenants_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Tenants_ServiceDesc.Streams[0], Tenants_Export_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tenantsExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Tenants_ExportClient interface {
+	Recv() (*TenantsExportResponse, error)
+	grpc.ClientStream
+}
+
+type tenantsExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *tenantsExportClient) Recv() (*TenantsExportResponse, error) {
+	m := new(TenantsExportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tenantsClient) Delete(ctx context.Context, in *TenantsDeleteRequest, opts ...grpc.CallOption) (*TenantsDeleteResponse, error) {
+	out := new(TenantsDeleteResponse)
+	err := c.cc.Invoke(ctx, Tenants_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TenantsServer is the server API for Tenants service.
+// All implementations should embed UnimplementedTenantsServer
+// for forward compatibility
+type TenantsServer interface {
+	// Stream every value the tenant has in the key value stores
+	Export(*TenantsExportRequest, Tenants_ExportServer) error
+	// Delete every value the tenant has in the key value stores
+	Delete(context.Context, *TenantsDeleteRequest) (*TenantsDeleteResponse, error)
+}
+
+// UnimplementedTenantsServer should be embedded to have forward compatible implementations.
+type UnimplementedTenantsServer struct {
+}
+
+func (UnimplementedTenantsServer) Export(*TenantsExportRequest, Tenants_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedTenantsServer) Delete(context.Context, *TenantsDeleteRequest) (*TenantsDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+
+// UnsafeTenantsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TenantsServer will
+// result in compilation errors.
+type UnsafeTenantsServer interface {
+	mustEmbedUnimplementedTenantsServer()
+}
+
+func RegisterTenantsServer(s grpc.ServiceRegistrar, srv TenantsServer) {
+	s.RegisterService(&Tenants_ServiceDesc, srv)
+}
+
+func _Tenants_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TenantsExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TenantsServer).Export(m, &tenantsExportServer{stream})
+}
+
+type Tenants_ExportServer interface {
+	Send(*TenantsExportResponse) error
+	grpc.ServerStream
+}
+
+type tenantsExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *tenantsExportServer) Send(m *TenantsExportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Tenants_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TenantsDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenantsServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tenants_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantsServer).Delete(ctx, req.(*TenantsDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Tenants_ServiceDesc is the grpc.ServiceDesc for Tenants service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tenants_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.tenants.v1.Tenants",
+	HandlerType: (*TenantsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Delete",
+			Handler:    _Tenants_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _Tenants_Export_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mongo/proto/tenants/v1/tenants.proto",
+}
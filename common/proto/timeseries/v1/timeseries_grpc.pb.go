@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/timeseries/v1/timeseries.proto
+
+package timeseriespb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TimeSeries_Append_FullMethodName    = "/mongo.proto.timeseries.v1.TimeSeries/Append"
+	TimeSeries_Range_FullMethodName     = "/mongo.proto.timeseries.v1.TimeSeries/Range"
+	TimeSeries_Aggregate_FullMethodName = "/mongo.proto.timeseries.v1.TimeSeries/Aggregate"
+)
+
+// TimeSeriesClient is the client API for TimeSeries service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TimeSeriesClient interface {
+	// Add measurements to a store
+	Append(ctx context.Context, in *TimeSeriesAppendRequest, opts ...grpc.CallOption) (*TimeSeriesAppendResponse, error)
+	// Read the measurements of a time window, oldest first
+	Range(ctx context.Context, in *TimeSeriesRangeRequest, opts ...grpc.CallOption) (*TimeSeriesRangeResponse, error)
+	// Summarise the measurements of a time window in buckets of equal length
+	Aggregate(ctx context.Context, in *TimeSeriesAggregateRequest, opts ...grpc.CallOption) (*TimeSeriesAggregateResponse, error)
+}
+
+type timeSeriesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimeSeriesClient(cc grpc.ClientConnInterface) TimeSeriesClient {
+	return &timeSeriesClient{cc}
+}
+
+func (c *timeSeriesClient) Append(ctx context.Context, in *TimeSeriesAppendRequest, opts ...grpc.CallOption) (*TimeSeriesAppendResponse, error) {
+	out := new(TimeSeriesAppendResponse)
+	err := c.cc.Invoke(ctx, TimeSeries_Append_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeSeriesClient) Range(ctx context.Context, in *TimeSeriesRangeRequest, opts ...grpc.CallOption) (*TimeSeriesRangeResponse, error) {
+	out := new(TimeSeriesRangeResponse)
+	err := c.cc.Invoke(ctx, TimeSeries_Range_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeSeriesClient) Aggregate(ctx context.Context, in *TimeSeriesAggregateRequest, opts ...grpc.CallOption) (*TimeSeriesAggregateResponse, error) {
+	out := new(TimeSeriesAggregateResponse)
+	err := c.cc.Invoke(ctx, TimeSeries_Aggregate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TimeSeriesServer is the server API for TimeSeries service.
+// All implementations should embed UnimplementedTimeSeriesServer
+// for forward compatibility
+type TimeSeriesServer interface {
+	// Add measurements to a store
+	Append(context.Context, *TimeSeriesAppendRequest) (*TimeSeriesAppendResponse, error)
+	// Read the measurements of a time window, oldest first
+	Range(context.Context, *TimeSeriesRangeRequest) (*TimeSeriesRangeResponse, error)
+	// Summarise the measurements of a time window in buckets of equal length
+	Aggregate(context.Context, *TimeSeriesAggregateRequest) (*TimeSeriesAggregateResponse, error)
+}
+
+// UnimplementedTimeSeriesServer should be embedded to have forward compatible implementations.
+type UnimplementedTimeSeriesServer struct {
+}
+
+func (UnimplementedTimeSeriesServer) Append(context.Context, *TimeSeriesAppendRequest) (*TimeSeriesAppendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
+}
+func (UnimplementedTimeSeriesServer) Range(context.Context, *TimeSeriesRangeRequest) (*TimeSeriesRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Range not implemented")
+}
+func (UnimplementedTimeSeriesServer) Aggregate(context.Context, *TimeSeriesAggregateRequest) (*TimeSeriesAggregateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Aggregate not implemented")
+}
+
+// UnsafeTimeSeriesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimeSeriesServer will
+// result in compilation errors.
+type UnsafeTimeSeriesServer interface {
+	mustEmbedUnimplementedTimeSeriesServer()
+}
+
+func RegisterTimeSeriesServer(s grpc.ServiceRegistrar, srv TimeSeriesServer) {
+	s.RegisterService(&TimeSeries_ServiceDesc, srv)
+}
+
+func _TimeSeries_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSeriesAppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeSeriesServer).Append(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeSeries_Append_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeSeriesServer).Append(ctx, req.(*TimeSeriesAppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeSeries_Range_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSeriesRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeSeriesServer).Range(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeSeries_Range_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeSeriesServer).Range(ctx, req.(*TimeSeriesRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeSeries_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSeriesAggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeSeriesServer).Aggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeSeries_Aggregate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeSeriesServer).Aggregate(ctx, req.(*TimeSeriesAggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TimeSeries_ServiceDesc is the grpc.ServiceDesc for TimeSeries service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimeSeries_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.timeseries.v1.TimeSeries",
+	HandlerType: (*TimeSeriesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Append",
+			Handler:    _TimeSeries_Append_Handler,
+		},
+		{
+			MethodName: "Range",
+			Handler:    _TimeSeries_Range_Handler,
+		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _TimeSeries_Aggregate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/timeseries/v1/timeseries.proto",
+}
@@ -0,0 +1,691 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mongo/proto/vectors/v1/vectors.proto
+
+package vectorspb
+
+import (
+	v1 "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VectorsUpsertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key value store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The key of the value
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// The name of the vector, as declared in the store's configuration
+	Vector string `protobuf:"bytes,3,opt,name=vector,proto3" json:"vector,omitempty"`
+	// The vector, with as many values as its declared dimensions
+	Values []float64 `protobuf:"fixed64,4,rep,packed,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *VectorsUpsertRequest) Reset() {
+	*x = VectorsUpsertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorsUpsertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorsUpsertRequest) ProtoMessage() {}
+
+func (x *VectorsUpsertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorsUpsertRequest.ProtoReflect.Descriptor instead.
+func (*VectorsUpsertRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VectorsUpsertRequest) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *VectorsUpsertRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *VectorsUpsertRequest) GetVector() string {
+	if x != nil {
+		return x.Vector
+	}
+	return ""
+}
+
+func (x *VectorsUpsertRequest) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type VectorsUpsertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *VectorsUpsertResponse) Reset() {
+	*x = VectorsUpsertResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorsUpsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorsUpsertResponse) ProtoMessage() {}
+
+func (x *VectorsUpsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorsUpsertResponse.ProtoReflect.Descriptor instead.
+func (*VectorsUpsertResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{1}
+}
+
+type VectorsDeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key value store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The key of the value
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// The name of the vector, as declared in the store's configuration
+	Vector string `protobuf:"bytes,3,opt,name=vector,proto3" json:"vector,omitempty"`
+}
+
+func (x *VectorsDeleteRequest) Reset() {
+	*x = VectorsDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorsDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorsDeleteRequest) ProtoMessage() {}
+
+func (x *VectorsDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorsDeleteRequest.ProtoReflect.Descriptor instead.
+func (*VectorsDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VectorsDeleteRequest) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *VectorsDeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *VectorsDeleteRequest) GetVector() string {
+	if x != nil {
+		return x.Vector
+	}
+	return ""
+}
+
+type VectorsDeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *VectorsDeleteResponse) Reset() {
+	*x = VectorsDeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorsDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorsDeleteResponse) ProtoMessage() {}
+
+func (x *VectorsDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorsDeleteResponse.ProtoReflect.Descriptor instead.
+func (*VectorsDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{3}
+}
+
+type VectorsQueryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key value store name
+	Store string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	// The name of the vector, as declared in the store's configuration
+	Vector string `protobuf:"bytes,2,opt,name=vector,proto3" json:"vector,omitempty"`
+	// The query vector, with as many values as the vector's declared dimensions
+	Values []float64 `protobuf:"fixed64,3,rep,packed,name=values,proto3" json:"values,omitempty"`
+	// Conditions on the declared filter fields every value must match, exists conditions aren't supported
+	Filters []*v1.Filter `protobuf:"bytes,4,rep,name=filters,proto3" json:"filters,omitempty"`
+	// The number of values to return, defaults to 10 and can be at most 1000
+	TopK int32 `protobuf:"varint,5,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`
+	// The number of nearest neighbours to consider, defaults to 10 times top_k and can be at most 10000
+	NumCandidates int32 `protobuf:"varint,6,opt,name=num_candidates,json=numCandidates,proto3" json:"num_candidates,omitempty"`
+	// The tenant to search in key tenancy mode
+	Tenant string `protobuf:"bytes,7,opt,name=tenant,proto3" json:"tenant,omitempty"`
+}
+
+func (x *VectorsQueryRequest) Reset() {
+	*x = VectorsQueryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorsQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorsQueryRequest) ProtoMessage() {}
+
+func (x *VectorsQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorsQueryRequest.ProtoReflect.Descriptor instead.
+func (*VectorsQueryRequest) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VectorsQueryRequest) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *VectorsQueryRequest) GetVector() string {
+	if x != nil {
+		return x.Vector
+	}
+	return ""
+}
+
+func (x *VectorsQueryRequest) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *VectorsQueryRequest) GetFilters() []*v1.Filter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *VectorsQueryRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *VectorsQueryRequest) GetNumCandidates() int32 {
+	if x != nil {
+		return x.NumCandidates
+	}
+	return 0
+}
+
+func (x *VectorsQueryRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+// A value found by a query
+type VectorMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key of the value
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Similarity of the value's vector to the query vector, from 0 to 1 with the most similar highest
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// The value
+	Content *structpb.Struct `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *VectorMatch) Reset() {
+	*x = VectorMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorMatch) ProtoMessage() {}
+
+func (x *VectorMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorMatch.ProtoReflect.Descriptor instead.
+func (*VectorMatch) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VectorMatch) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *VectorMatch) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *VectorMatch) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type VectorsQueryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The most similar values first
+	Matches []*VectorMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+}
+
+func (x *VectorsQueryResponse) Reset() {
+	*x = VectorsQueryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VectorsQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorsQueryResponse) ProtoMessage() {}
+
+func (x *VectorsQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mongo_proto_vectors_v1_vectors_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorsQueryResponse.ProtoReflect.Descriptor instead.
+func (*VectorsQueryResponse) Descriptor() ([]byte, []int) {
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VectorsQueryResponse) GetMatches() []*VectorMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+var File_mongo_proto_vectors_v1_vectors_proto protoreflect.FileDescriptor
+
+var file_mongo_proto_vectors_v1_vectors_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x16, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x1c,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x28, 0x6d, 0x6f,
+	0x6e, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6e, 0x0a, 0x14, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x73, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x16,
+	0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x01, 0x52, 0x06,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x73, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x56, 0x0a, 0x14, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x17, 0x0a, 0x15, 0x56, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0xeb, 0x01, 0x0a, 0x13, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x01, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x3a,
+	0x0a, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x13, 0x0a, 0x05, 0x74, 0x6f,
+	0x70, 0x5f, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x6f, 0x70, 0x4b, 0x12,
+	0x25, 0x0a, 0x0e, 0x6e, 0x75, 0x6d, 0x5f, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6e, 0x75, 0x6d, 0x43, 0x61, 0x6e, 0x64,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0x68,
+	0x0a, 0x0b, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52,
+	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x55, 0x0a, 0x14, 0x56, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3d, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x23, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x32,
+	0xbb, 0x02, 0x0a, 0x07, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x65, 0x0a, 0x06, 0x55,
+	0x70, 0x73, 0x65, 0x72, 0x74, 0x12, 0x2c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x73, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x65, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2c, 0x2e, 0x6d,
+	0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6d, 0x6f, 0x6e,
+	0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x05, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x12, 0x2b, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2c, 0x2e, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x76, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x4a, 0x5a,
+	0x48, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x69, 0x74, 0x72,
+	0x69, 0x63, 0x74, 0x65, 0x63, 0x68, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64, 0x62, 0x2d, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x76, 0x31, 0x3b,
+	0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_mongo_proto_vectors_v1_vectors_proto_rawDescOnce sync.Once
+	file_mongo_proto_vectors_v1_vectors_proto_rawDescData = file_mongo_proto_vectors_v1_vectors_proto_rawDesc
+)
+
+func file_mongo_proto_vectors_v1_vectors_proto_rawDescGZIP() []byte {
+	file_mongo_proto_vectors_v1_vectors_proto_rawDescOnce.Do(func() {
+		file_mongo_proto_vectors_v1_vectors_proto_rawDescData = protoimpl.X.CompressGZIP(file_mongo_proto_vectors_v1_vectors_proto_rawDescData)
+	})
+	return file_mongo_proto_vectors_v1_vectors_proto_rawDescData
+}
+
+var file_mongo_proto_vectors_v1_vectors_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_mongo_proto_vectors_v1_vectors_proto_goTypes = []interface{}{
+	(*VectorsUpsertRequest)(nil),  // 0: mongo.proto.vectors.v1.VectorsUpsertRequest
+	(*VectorsUpsertResponse)(nil), // 1: mongo.proto.vectors.v1.VectorsUpsertResponse
+	(*VectorsDeleteRequest)(nil),  // 2: mongo.proto.vectors.v1.VectorsDeleteRequest
+	(*VectorsDeleteResponse)(nil), // 3: mongo.proto.vectors.v1.VectorsDeleteResponse
+	(*VectorsQueryRequest)(nil),   // 4: mongo.proto.vectors.v1.VectorsQueryRequest
+	(*VectorMatch)(nil),           // 5: mongo.proto.vectors.v1.VectorMatch
+	(*VectorsQueryResponse)(nil),  // 6: mongo.proto.vectors.v1.VectorsQueryResponse
+	(*v1.Filter)(nil),             // 7: mongo.proto.documents.v1.Filter
+	(*structpb.Struct)(nil),       // 8: google.protobuf.Struct
+}
+var file_mongo_proto_vectors_v1_vectors_proto_depIdxs = []int32{
+	7, // 0: mongo.proto.vectors.v1.VectorsQueryRequest.filters:type_name -> mongo.proto.documents.v1.Filter
+	8, // 1: mongo.proto.vectors.v1.VectorMatch.content:type_name -> google.protobuf.Struct
+	5, // 2: mongo.proto.vectors.v1.VectorsQueryResponse.matches:type_name -> mongo.proto.vectors.v1.VectorMatch
+	0, // 3: mongo.proto.vectors.v1.Vectors.Upsert:input_type -> mongo.proto.vectors.v1.VectorsUpsertRequest
+	2, // 4: mongo.proto.vectors.v1.Vectors.Delete:input_type -> mongo.proto.vectors.v1.VectorsDeleteRequest
+	4, // 5: mongo.proto.vectors.v1.Vectors.Query:input_type -> mongo.proto.vectors.v1.VectorsQueryRequest
+	1, // 6: mongo.proto.vectors.v1.Vectors.Upsert:output_type -> mongo.proto.vectors.v1.VectorsUpsertResponse
+	3, // 7: mongo.proto.vectors.v1.Vectors.Delete:output_type -> mongo.proto.vectors.v1.VectorsDeleteResponse
+	6, // 8: mongo.proto.vectors.v1.Vectors.Query:output_type -> mongo.proto.vectors.v1.VectorsQueryResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_mongo_proto_vectors_v1_vectors_proto_init() }
+func file_mongo_proto_vectors_v1_vectors_proto_init() {
+	if File_mongo_proto_vectors_v1_vectors_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorsUpsertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorsUpsertResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorsDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorsDeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorsQueryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mongo_proto_vectors_v1_vectors_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VectorsQueryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mongo_proto_vectors_v1_vectors_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mongo_proto_vectors_v1_vectors_proto_goTypes,
+		DependencyIndexes: file_mongo_proto_vectors_v1_vectors_proto_depIdxs,
+		MessageInfos:      file_mongo_proto_vectors_v1_vectors_proto_msgTypes,
+	}.Build()
+	File_mongo_proto_vectors_v1_vectors_proto = out.File
+	file_mongo_proto_vectors_v1_vectors_proto_rawDesc = nil
+	file_mongo_proto_vectors_v1_vectors_proto_goTypes = nil
+	file_mongo_proto_vectors_v1_vectors_proto_depIdxs = nil
+}
@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: mongo/proto/vectors/v1/vectors.proto
+
+package vectorspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Vectors_Upsert_FullMethodName = "/mongo.proto.vectors.v1.Vectors/Upsert"
+	Vectors_Delete_FullMethodName = "/mongo.proto.vectors.v1.Vectors/Delete"
+	Vectors_Query_FullMethodName  = "/mongo.proto.vectors.v1.Vectors/Query"
+)
+
+// VectorsClient is the client API for Vectors service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VectorsClient interface {
+	// Set a vector of a value, the value must exist
+	Upsert(ctx context.Context, in *VectorsUpsertRequest, opts ...grpc.CallOption) (*VectorsUpsertResponse, error)
+	// Remove a vector from a value
+	Delete(ctx context.Context, in *VectorsDeleteRequest, opts ...grpc.CallOption) (*VectorsDeleteResponse, error)
+	// Find the values whose vectors are most similar to a query vector
+	Query(ctx context.Context, in *VectorsQueryRequest, opts ...grpc.CallOption) (*VectorsQueryResponse, error)
+}
+
+type vectorsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVectorsClient(cc grpc.ClientConnInterface) VectorsClient {
+	return &vectorsClient{cc}
+}
+
+func (c *vectorsClient) Upsert(ctx context.Context, in *VectorsUpsertRequest, opts ...grpc.CallOption) (*VectorsUpsertResponse, error) {
+	out := new(VectorsUpsertResponse)
+	err := c.cc.Invoke(ctx, Vectors_Upsert_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorsClient) Delete(ctx context.Context, in *VectorsDeleteRequest, opts ...grpc.CallOption) (*VectorsDeleteResponse, error) {
+	out := new(VectorsDeleteResponse)
+	err := c.cc.Invoke(ctx, Vectors_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorsClient) Query(ctx context.Context, in *VectorsQueryRequest, opts ...grpc.CallOption) (*VectorsQueryResponse, error) {
+	out := new(VectorsQueryResponse)
+	err := c.cc.Invoke(ctx, Vectors_Query_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VectorsServer is the server API for Vectors service.
+// All implementations should embed UnimplementedVectorsServer
+// for forward compatibility
+type VectorsServer interface {
+	// Set a vector of a value, the value must exist
+	Upsert(context.Context, *VectorsUpsertRequest) (*VectorsUpsertResponse, error)
+	// Remove a vector from a value
+	Delete(context.Context, *VectorsDeleteRequest) (*VectorsDeleteResponse, error)
+	// Find the values whose vectors are most similar to a query vector
+	Query(context.Context, *VectorsQueryRequest) (*VectorsQueryResponse, error)
+}
+
+// UnimplementedVectorsServer should be embedded to have forward compatible implementations.
+type UnimplementedVectorsServer struct {
+}
+
+func (UnimplementedVectorsServer) Upsert(context.Context, *VectorsUpsertRequest) (*VectorsUpsertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Upsert not implemented")
+}
+func (UnimplementedVectorsServer) Delete(context.Context, *VectorsDeleteRequest) (*VectorsDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedVectorsServer) Query(context.Context, *VectorsQueryRequest) (*VectorsQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+
+// UnsafeVectorsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VectorsServer will
+// result in compilation errors.
+type UnsafeVectorsServer interface {
+	mustEmbedUnimplementedVectorsServer()
+}
+
+func RegisterVectorsServer(s grpc.ServiceRegistrar, srv VectorsServer) {
+	s.RegisterService(&Vectors_ServiceDesc, srv)
+}
+
+func _Vectors_Upsert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VectorsUpsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorsServer).Upsert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vectors_Upsert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorsServer).Upsert(ctx, req.(*VectorsUpsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vectors_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VectorsDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorsServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vectors_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorsServer).Delete(ctx, req.(*VectorsDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vectors_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VectorsQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorsServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Vectors_Query_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorsServer).Query(ctx, req.(*VectorsQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Vectors_ServiceDesc is the grpc.ServiceDesc for Vectors service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Vectors_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mongo.proto.vectors.v1.Vectors",
+	HandlerType: (*VectorsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Upsert",
+			Handler:    _Vectors_Upsert_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Vectors_Delete_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _Vectors_Query_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mongo/proto/vectors/v1/vectors.proto",
+}
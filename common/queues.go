@@ -0,0 +1,312 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nitrictech/mongodb-provider/common/env"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	queuespb "github.com/nitrictech/nitric/core/pkg/proto/queues/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// QueueSettings control delivery for a single queue, they are decoded from MONGO_QUEUES_CONFIG
+type QueueSettings struct {
+	// Seconds a dequeued message is leased for before it becomes visible again
+	VisibilityTimeout int `json:"visibility-timeout"`
+	// Number of deliveries after which a message is moved to the dead-letter collection
+	MaxDeliveryAttempts int `json:"max-delivery-attempts"`
+}
+
+var defaultQueueSettings = QueueSettings{
+	VisibilityTimeout:   30,
+	MaxDeliveryAttempts: 5,
+}
+
+// Fill in any unset settings from the given defaults
+func (s QueueSettings) withDefaults(defaults QueueSettings) QueueSettings {
+	if s.VisibilityTimeout <= 0 {
+		s.VisibilityTimeout = defaults.VisibilityTimeout
+	}
+
+	if s.MaxDeliveryAttempts <= 0 {
+		s.MaxDeliveryAttempts = defaults.MaxDeliveryAttempts
+	}
+
+	return s
+}
+
+type queueMessageDocument struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	Payload    []byte             `bson:"payload"`
+	EnqueuedAt time.Time          `bson:"enqueuedAt"`
+	VisibleAt  time.Time          `bson:"visibleAt"`
+	LeaseId    string             `bson:"leaseId,omitempty"`
+	Attempts   int                `bson:"attempts"`
+}
+
+type deadLetterDocument struct {
+	queueMessageDocument `bson:",inline"`
+	DeadLetteredAt       time.Time `bson:"deadLetteredAt"`
+}
+
+// MongoQueuesServer serves Nitric queues from collections in the cluster.
+//
+// Each queue is stored in the "queues.<name>" collection with messages that exceed
+// their maximum delivery attempts moved to "queues.<name>.dead-letter".
+//
+// Messages are delivered in the order they were enqueued. A message that is not completed
+// before its lease expires keeps its original position and is redelivered ahead of newer messages,
+// so consumers must tolerate duplicates and should not rely on strict FIFO once leases start to expire.
+type MongoQueuesServer struct {
+	db       *mongo.Database
+	settings map[string]QueueSettings
+
+	indexed sync.Map
+}
+
+var _ queuespb.QueuesServer = &MongoQueuesServer{}
+
+func (q *MongoQueuesServer) getCollectionHandle(queue string) *mongo.Collection {
+	return q.db.Collection(fmt.Sprintf("queues.%s", queue))
+}
+
+func (q *MongoQueuesServer) getDeadLetterCollectionHandle(queue string) *mongo.Collection {
+	return q.db.Collection(fmt.Sprintf("queues.%s.dead-letter", queue))
+}
+
+func (q *MongoQueuesServer) settingsFor(queue string) QueueSettings {
+	if settings, ok := q.settings[queue]; ok {
+		return settings
+	}
+
+	return q.settings["default"]
+}
+
+// Create the indexes used for leasing the first time a queue is accessed
+func (q *MongoQueuesServer) ensureIndexes(ctx context.Context, queue string) error {
+	if _, ok := q.indexed.Load(queue); ok {
+		return nil
+	}
+
+	_, err := q.getCollectionHandle(queue).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"visibleAt", 1}, {"_id", 1}}},
+		{Keys: bson.D{{"leaseId", 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		return err
+	}
+
+	q.indexed.Store(queue, true)
+
+	return nil
+}
+
+// Send message(s) to a queue
+func (q *MongoQueuesServer) Enqueue(ctx context.Context, req *queuespb.QueueEnqueueRequest) (*queuespb.QueueEnqueueResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoQueuesServer.Enqueue")
+
+	if len(req.Messages) == 0 {
+		return &queuespb.QueueEnqueueResponse{}, nil
+	}
+
+	now := time.Now()
+
+	documents := make([]interface{}, 0, len(req.Messages))
+	for _, message := range req.Messages {
+		payload, err := proto.Marshal(message)
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"error marshalling queue message",
+				err,
+			)
+		}
+
+		documents = append(documents, queueMessageDocument{
+			Id:         primitive.NewObjectID(),
+			Payload:    payload,
+			EnqueuedAt: now,
+			VisibleAt:  now,
+		})
+	}
+
+	_, err := q.getCollectionHandle(req.QueueName).InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+			return nil, newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to enqueue messages to queue %s", req.QueueName),
+				err,
+			)
+		}
+
+		failedMessages := make([]*queuespb.FailedEnqueueMessage, 0, len(bulkErr.WriteErrors))
+		for _, writeErr := range bulkErr.WriteErrors {
+			failedMessages = append(failedMessages, &queuespb.FailedEnqueueMessage{
+				Message: req.Messages[writeErr.Index],
+				Details: writeErr.Message,
+			})
+		}
+
+		return &queuespb.QueueEnqueueResponse{
+			FailedMessages: failedMessages,
+		}, nil
+	}
+
+	return &queuespb.QueueEnqueueResponse{}, nil
+}
+
+// Lease up to depth messages from a queue, messages that have exhausted their delivery attempts are dead-lettered
+func (q *MongoQueuesServer) Dequeue(ctx context.Context, req *queuespb.QueueDequeueRequest) (*queuespb.QueueDequeueResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoQueuesServer.Dequeue")
+
+	if err := q.ensureIndexes(ctx, req.QueueName); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to create indexes for queue %s", req.QueueName),
+			err,
+		)
+	}
+
+	coll := q.getCollectionHandle(req.QueueName)
+	settings := q.settingsFor(req.QueueName)
+
+	depth := int(req.Depth)
+	if depth < 1 {
+		depth = 1
+	}
+
+	messages := make([]*queuespb.DequeuedMessage, 0, depth)
+	for len(messages) < depth {
+		now := time.Now()
+		leaseId := primitive.NewObjectID().Hex()
+
+		filter := bson.D{{"visibleAt", bson.D{{"$lte", now}}}}
+		update := bson.D{
+			{"$set", bson.D{
+				{"visibleAt", now.Add(time.Duration(settings.VisibilityTimeout) * time.Second)},
+				{"leaseId", leaseId},
+			}},
+			{"$inc", bson.D{{"attempts", 1}}},
+		}
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{"_id", 1}}).
+			SetReturnDocument(options.After)
+
+		var doc queueMessageDocument
+		err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		} else if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to lease message from queue %s", req.QueueName),
+				err,
+			)
+		}
+
+		if settings.MaxDeliveryAttempts > 0 && doc.Attempts > settings.MaxDeliveryAttempts {
+			if err := q.deadLetter(ctx, req.QueueName, &doc); err != nil {
+				return nil, newErr(
+					codes.Internal,
+					fmt.Sprintf("unable to dead-letter message %s from queue %s", doc.Id.Hex(), req.QueueName),
+					err,
+				)
+			}
+
+			continue
+		}
+
+		var message queuespb.QueueMessage
+		if err := proto.Unmarshal(doc.Payload, &message); err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"failed unmarshalling queue message",
+				err,
+			)
+		}
+
+		messages = append(messages, &queuespb.DequeuedMessage{
+			LeaseId: leaseId,
+			Message: &message,
+		})
+	}
+
+	return &queuespb.QueueDequeueResponse{
+		Messages: messages,
+	}, nil
+}
+
+// Move a message we hold the lease for into the dead-letter collection
+func (q *MongoQueuesServer) deadLetter(ctx context.Context, queue string, doc *queueMessageDocument) error {
+	// Upsert so a retry after a failed delete doesn't collide with the earlier copy
+	_, err := q.getDeadLetterCollectionHandle(queue).ReplaceOne(
+		ctx,
+		bson.D{{"_id", doc.Id}},
+		deadLetterDocument{
+			queueMessageDocument: *doc,
+			DeadLetteredAt:       time.Now(),
+		},
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.getCollectionHandle(queue).DeleteOne(ctx, bson.D{{"_id", doc.Id}, {"leaseId", doc.LeaseId}})
+
+	return err
+}
+
+// Complete a message previously leased from a queue
+func (q *MongoQueuesServer) Complete(ctx context.Context, req *queuespb.QueueCompleteRequest) (*queuespb.QueueCompleteResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoQueuesServer.Complete")
+
+	res, err := q.getCollectionHandle(req.QueueName).DeleteOne(ctx, bson.D{{"leaseId", req.LeaseId}})
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to complete message in queue %s", req.QueueName),
+			err,
+		)
+	}
+
+	if res.DeletedCount == 0 {
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("lease %s not found in queue %s, it may have expired and been redelivered", req.LeaseId, req.QueueName),
+			fmt.Errorf("no message holds lease %s", req.LeaseId),
+		)
+	}
+
+	return &queuespb.QueueCompleteResponse{}, nil
+}
+
+func NewQueues(db *mongo.Database) (*MongoQueuesServer, error) {
+	settings := map[string]QueueSettings{}
+	if err := json.Unmarshal([]byte(env.MONGO_QUEUES_CONFIG.String()), &settings); err != nil {
+		return nil, fmt.Errorf("unable to parse MONGO_QUEUES_CONFIG: %w", err)
+	}
+
+	defaults := settings["default"].withDefaults(defaultQueueSettings)
+	for name, s := range settings {
+		settings[name] = s.withDefaults(defaults)
+	}
+	settings["default"] = defaults
+
+	return &MongoQueuesServer{
+		db:       db,
+		settings: settings,
+	}, nil
+}
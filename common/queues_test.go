@@ -0,0 +1,178 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	queuespb "github.com/nitrictech/nitric/core/pkg/proto/queues/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestQueueSettingsWithDefaults(t *testing.T) {
+	settings := QueueSettings{VisibilityTimeout: 120}.withDefaults(defaultQueueSettings)
+
+	if settings.VisibilityTimeout != 120 || settings.MaxDeliveryAttempts != defaultQueueSettings.MaxDeliveryAttempts {
+		t.Fatalf("expected the visibility timeout to be kept and the default attempts, got %+v", settings)
+	}
+}
+
+func testQueues(t *testing.T, settings QueueSettings) *MongoQueuesServer {
+	t.Helper()
+
+	return &MongoQueuesServer{
+		db:       testDatabase(t),
+		settings: map[string]QueueSettings{"default": settings},
+	}
+}
+
+func enqueue(t *testing.T, queues *MongoQueuesServer, queue string, ids ...string) {
+	t.Helper()
+
+	messages := []*queuespb.QueueMessage{}
+	for _, id := range ids {
+		payload, err := structpb.NewStruct(map[string]interface{}{"id": id})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		messages = append(messages, &queuespb.QueueMessage{Content: &queuespb.QueueMessage_StructPayload{StructPayload: payload}})
+	}
+
+	res, err := queues.Enqueue(context.Background(), &queuespb.QueueEnqueueRequest{QueueName: queue, Messages: messages})
+	if err != nil {
+		t.Fatalf("unable to enqueue: %v", err)
+	}
+
+	if len(res.FailedMessages) > 0 {
+		t.Fatalf("expected every message to be enqueued, %d failed", len(res.FailedMessages))
+	}
+}
+
+// The ids of the dequeued messages, in the order they were delivered
+func dequeue(t *testing.T, queues *MongoQueuesServer, queue string, depth int32) ([]string, []string) {
+	t.Helper()
+
+	res, err := queues.Dequeue(context.Background(), &queuespb.QueueDequeueRequest{QueueName: queue, Depth: depth})
+	if err != nil {
+		t.Fatalf("unable to dequeue: %v", err)
+	}
+
+	ids, leases := []string{}, []string{}
+	for _, message := range res.Messages {
+		ids = append(ids, message.Message.GetStructPayload().Fields["id"].GetStringValue())
+		leases = append(leases, message.LeaseId)
+	}
+
+	return ids, leases
+}
+
+func TestQueuesEnqueueDequeue(t *testing.T) {
+	queues := testQueues(t, defaultQueueSettings)
+	ctx := context.Background()
+
+	enqueue(t, queues, "orders", "1", "2", "3")
+
+	ids, leases := dequeue(t, queues, "orders", 2)
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("expected messages 1 and 2, got %v", ids)
+	}
+
+	// Leased messages aren't delivered again while the lease holds
+	ids, _ = dequeue(t, queues, "orders", 10)
+	if len(ids) != 1 || ids[0] != "3" {
+		t.Fatalf("expected message 3, got %v", ids)
+	}
+
+	for _, lease := range leases {
+		if _, err := queues.Complete(ctx, &queuespb.QueueCompleteRequest{QueueName: "orders", LeaseId: lease}); err != nil {
+			t.Fatalf("unable to complete lease %s: %v", lease, err)
+		}
+	}
+
+	_, err := queues.Complete(ctx, &queuespb.QueueCompleteRequest{QueueName: "orders", LeaseId: leases[0]})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected completing a lease twice to be NotFound, got %v", err)
+	}
+
+	count, err := queues.getCollectionHandle("orders").CountDocuments(ctx, bson.D{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected one message left in the queue, got %d", count)
+	}
+}
+
+func TestQueuesLeaseExpiry(t *testing.T) {
+	queues := testQueues(t, QueueSettings{VisibilityTimeout: 1, MaxDeliveryAttempts: 5})
+	ctx := context.Background()
+
+	enqueue(t, queues, "orders", "1", "2")
+
+	ids, expired := dequeue(t, queues, "orders", 1)
+	if len(ids) != 1 || ids[0] != "1" {
+		t.Fatalf("expected message 1, got %v", ids)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	// The expired message keeps its position ahead of newer messages
+	ids, leases := dequeue(t, queues, "orders", 2)
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("expected message 1 to be redelivered before 2, got %v", ids)
+	}
+
+	_, err := queues.Complete(ctx, &queuespb.QueueCompleteRequest{QueueName: "orders", LeaseId: expired[0]})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected the expired lease to be NotFound, got %v", err)
+	}
+
+	if _, err := queues.Complete(ctx, &queuespb.QueueCompleteRequest{QueueName: "orders", LeaseId: leases[0]}); err != nil {
+		t.Fatalf("unable to complete the redelivered message: %v", err)
+	}
+}
+
+func TestQueuesDeadLetter(t *testing.T) {
+	queues := testQueues(t, QueueSettings{VisibilityTimeout: 1, MaxDeliveryAttempts: 2})
+	ctx := context.Background()
+
+	enqueue(t, queues, "orders", "1")
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		ids, _ := dequeue(t, queues, "orders", 1)
+		if len(ids) != 1 {
+			t.Fatalf("expected delivery %d of message 1, got %v", attempt, ids)
+		}
+
+		time.Sleep(1500 * time.Millisecond)
+	}
+
+	// The third delivery exceeds the maximum attempts
+	ids, _ := dequeue(t, queues, "orders", 1)
+	if len(ids) != 0 {
+		t.Fatalf("expected no messages, got %v", ids)
+	}
+
+	count, err := queues.getCollectionHandle("orders").CountDocuments(ctx, bson.D{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected the message to leave the queue, %d left", count)
+	}
+
+	var dead deadLetterDocument
+	if err := queues.getDeadLetterCollectionHandle("orders").FindOne(ctx, bson.D{}).Decode(&dead); err != nil {
+		t.Fatalf("expected the message to be dead-lettered: %v", err)
+	}
+
+	if dead.Attempts != 3 || dead.DeadLetteredAt.IsZero() {
+		t.Fatalf("unexpected dead-letter %+v", dead)
+	}
+}
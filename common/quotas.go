@@ -0,0 +1,453 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// QuotaSettings limit the use of a key value store, limits that are zero aren't enforced
+type QuotaSettings struct {
+	// Sustained rate of operations
+	OpsPerSecond float64 `json:"ops-per-second,omitempty"`
+	// Operations that can be made at once after a quiet period, defaults to one second of operations
+	Burst float64 `json:"burst,omitempty"`
+	// Number of values in the store
+	MaxDocuments int64 `json:"max-documents,omitempty"`
+	// Total bson size of the values in the store
+	MaxBytes int64 `json:"max-bytes,omitempty"`
+	// Limits on the share of each calling service, by service name
+	Services map[string]*QuotaSettings `json:"services,omitempty"`
+}
+
+func (q *QuotaSettings) validate() error {
+	if q.OpsPerSecond < 0 || q.Burst < 0 || q.MaxDocuments < 0 || q.MaxBytes < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+
+	for service, settings := range q.Services {
+		if settings == nil {
+			continue
+		}
+
+		if err := settings.validate(); err != nil {
+			return fmt.Errorf("service %s: %w", service, err)
+		}
+	}
+
+	return nil
+}
+
+// quotaError is returned when an operation would exceed a limit
+type quotaError struct {
+	// The exceeded limit, e.g. "ops-per-second of store orders"
+	limit string
+	// When the operation can be retried, zero if it can't succeed until values are removed
+	retryAfter time.Duration
+}
+
+func (e *quotaError) Error() string {
+	if e.retryAfter > 0 {
+		return fmt.Sprintf("%s exceeded, retry after %s", e.limit, e.retryAfter)
+	}
+
+	return fmt.Sprintf("%s exceeded", e.limit)
+}
+
+// The error for an operation refused by a quota, with details of the exceeded limit and when to retry
+func quotaErr(newErr grpc_errors.ScopedErrorFactory, err error) error {
+	var exceeded *quotaError
+	if !errors.As(err, &exceeded) {
+		return newErr(
+			codes.Internal,
+			"unable to check quota",
+			err,
+		)
+	}
+
+	st := status.Convert(newErr(
+		codes.ResourceExhausted,
+		"quota exceeded",
+		err,
+	))
+
+	var withDetails *status.Status
+	if exceeded.retryAfter > 0 {
+		withDetails, err = st.WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(exceeded.retryAfter),
+		})
+	} else {
+		withDetails, err = st.WithDetails(&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     exceeded.limit,
+				Description: "values must be removed before more can be stored",
+			}},
+		})
+	}
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// A store's limits, or those of one service's use of it
+type quotaScope struct {
+	Store   string `bson:"store"`
+	Service string `bson:"service"`
+}
+
+func (s quotaScope) describe(limit string) string {
+	if s.Service == "" {
+		return fmt.Sprintf("%s of store %s", limit, s.Store)
+	}
+
+	return fmt.Sprintf("%s of service %s in store %s", limit, s.Service, s.Store)
+}
+
+type quotaUsage struct {
+	Documents int64 `bson:"documents"`
+	Bytes     int64 `bson:"bytes"`
+}
+
+// quotaLimiter enforces store quotas for the calling service.
+//
+// Token buckets and usage counters are kept in the cluster, so limits are shared by every runtime.
+// Usage is counted from the values stored when a store's quota is first enforced, after which it is
+// adjusted by the writes made through MongoDBServer, so concurrent writes of new keys may be counted more than once.
+type quotaLimiter struct {
+	db *mongo.Database
+	// The calling service, the one this runtime serves
+	service  string
+	settings map[string]*QuotaSettings
+	// The shared collection of a store, counted when its usage counter is created
+	storeCollection func(store string) *mongo.Collection
+
+	// Scopes whose usage counters are known to exist
+	initialised sync.Map
+}
+
+func (q *quotaLimiter) getBucketsCollectionHandle() *mongo.Collection {
+	return q.db.Collection("quotas.buckets")
+}
+
+func (q *quotaLimiter) getUsageCollectionHandle() *mongo.Collection {
+	return q.db.Collection("quotas.usage")
+}
+
+// The limits that apply to the calling service's use of a store
+func (q *quotaLimiter) scopes(store string) map[quotaScope]*QuotaSettings {
+	scopes := map[quotaScope]*QuotaSettings{}
+
+	settings, ok := q.settings[store]
+	if !ok {
+		return scopes
+	}
+
+	scopes[quotaScope{Store: store}] = settings
+
+	if service, ok := settings.Services[q.service]; ok && q.service != "" {
+		scopes[quotaScope{Store: store, Service: q.service}] = service
+	}
+
+	return scopes
+}
+
+// Take a token from each rate limited bucket of a store
+func (q *quotaLimiter) allow(ctx context.Context, store string) error {
+	// No quotas are configured
+	if q == nil {
+		return nil
+	}
+
+	for scope, settings := range q.scopes(store) {
+		if settings.OpsPerSecond <= 0 {
+			continue
+		}
+
+		if err := q.take(ctx, scope, settings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *quotaLimiter) take(ctx context.Context, scope quotaScope, settings *QuotaSettings) error {
+	burst := settings.Burst
+	if burst <= 0 {
+		burst = math.Max(1, settings.OpsPerSecond)
+	}
+
+	// Refill the bucket for the time since it was last used, measured by the cluster's clock, then take a token if there is one
+	update := mongo.Pipeline{
+		bson.D{{"$set", bson.D{
+			{"tokens", bson.D{{"$min", bson.A{
+				burst,
+				bson.D{{"$add", bson.A{
+					bson.D{{"$ifNull", bson.A{"$tokens", burst}}},
+					bson.D{{"$multiply", bson.A{
+						bson.D{{"$divide", bson.A{
+							bson.D{{"$subtract", bson.A{"$$NOW", bson.D{{"$ifNull", bson.A{"$updatedAt", "$$NOW"}}}}}},
+							1000,
+						}}},
+						settings.OpsPerSecond,
+					}}},
+				}}},
+			}}}},
+			{"updatedAt", "$$NOW"},
+		}}},
+		bson.D{{"$set", bson.D{{"allowed", bson.D{{"$gte", bson.A{"$tokens", 1}}}}}}},
+		bson.D{{"$set", bson.D{{"tokens", bson.D{{"$cond", bson.A{
+			"$allowed",
+			bson.D{{"$subtract", bson.A{"$tokens", 1}}},
+			"$tokens",
+		}}}}}}},
+	}
+
+	var bucket struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := q.getBucketsCollectionHandle().FindOneAndUpdate(ctx, bson.D{{"_id", scope}}, update, opts).Decode(&bucket)
+	// Another runtime created the bucket first
+	if mongo.IsDuplicateKeyError(err) {
+		err = q.getBucketsCollectionHandle().FindOneAndUpdate(ctx, bson.D{{"_id", scope}}, update, opts).Decode(&bucket)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !bucket.Allowed {
+		wait := time.Duration((1 - bucket.Tokens) / settings.OpsPerSecond * float64(time.Second))
+
+		return &quotaError{
+			limit:      scope.describe("ops-per-second"),
+			retryAfter: wait.Round(time.Millisecond) + time.Millisecond,
+		}
+	}
+
+	return nil
+}
+
+// Create the usage counter of a scope, counting what the store holds when it is the store's own
+func (q *quotaLimiter) initialise(ctx context.Context, scope quotaScope) error {
+	if _, ok := q.initialised.Load(scope); ok {
+		return nil
+	}
+
+	usage := quotaUsage{}
+
+	if scope.Service == "" {
+		cursor, err := q.storeCollection(scope.Store).Aggregate(ctx, mongo.Pipeline{
+			bson.D{{"$group", bson.D{
+				{"_id", nil},
+				{"documents", bson.D{{"$sum", 1}}},
+				{"bytes", bson.D{{"$sum", bson.D{{"$bsonSize", "$$ROOT"}}}}},
+			}}},
+		})
+		if err != nil {
+			return err
+		}
+
+		var results []quotaUsage
+		if err := cursor.All(ctx, &results); err != nil {
+			return err
+		}
+
+		if len(results) > 0 {
+			usage = results[0]
+		}
+	}
+
+	_, err := q.getUsageCollectionHandle().UpdateOne(
+		ctx,
+		bson.D{{"_id", scope}},
+		bson.D{{"$setOnInsert", usage}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	q.initialised.Store(scope, true)
+
+	return nil
+}
+
+// Size of the document a value is stored as
+func valueSize(id interface{}, stored map[string]interface{}) (int64, error) {
+	doc := bson.M{"_id": id, revisionField: int64(0), hashField: ""}
+	for name, value := range stored {
+		doc[name] = value
+	}
+
+	b, err := bson.Marshal(doc)
+	if err != nil {
+		return 0, err
+	}
+
+	// The hash is a hex encoded sha256
+	return int64(len(b) + 64), nil
+}
+
+// The limits on the size of a store that apply to the calling service
+func (q *quotaLimiter) usageScopes(store string) map[quotaScope]*QuotaSettings {
+	scopes := map[quotaScope]*QuotaSettings{}
+	if q == nil {
+		return scopes
+	}
+
+	for scope, settings := range q.scopes(store) {
+		if settings.MaxDocuments > 0 || settings.MaxBytes > 0 {
+			scopes[scope] = settings
+		}
+	}
+
+	return scopes
+}
+
+// Whether writes to a store count towards its usage
+func (q *quotaLimiter) countsUsage(store string) bool {
+	return len(q.usageScopes(store)) > 0
+}
+
+// The bson size of the document of a value, zero when it doesn't exist
+func documentSize(ctx context.Context, coll *mongo.Collection, id interface{}) (int64, bool, error) {
+	var existing struct {
+		Size int64 `bson:"size"`
+	}
+
+	err := coll.FindOne(ctx, bson.D{{"_id", id}}, options.FindOne().SetProjection(bson.D{{"size", bson.D{{"$bsonSize", "$$ROOT"}}}})).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	return existing.Size, true, nil
+}
+
+// Reserve the change a write makes to a store's usage, returning a function that releases the reservation if the write fails.
+// stored is the fields the value is stored as, nil for deletes.
+func (q *quotaLimiter) reserve(ctx context.Context, coll *mongo.Collection, store string, id interface{}, stored map[string]interface{}) (func(), error) {
+	if !q.countsUsage(store) {
+		return func() {}, nil
+	}
+
+	size, exists, err := documentSize(ctx, coll, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change := quotaUsage{}
+	if stored != nil {
+		newSize, err := valueSize(id, stored)
+		if err != nil {
+			return nil, err
+		}
+
+		change.Bytes = newSize - size
+		if !exists {
+			change.Documents = 1
+		}
+	} else if exists {
+		change.Documents = -1
+		change.Bytes = -size
+	}
+
+	return q.count(ctx, store, change)
+}
+
+// Count a change to a store's usage, returning a function that releases it if the write fails.
+// Growth is refused with a quotaError when it would take the store past a limit.
+func (q *quotaLimiter) count(ctx context.Context, store string, change quotaUsage) (func(), error) {
+	scopes := q.usageScopes(store)
+
+	if len(scopes) == 0 || change == (quotaUsage{}) {
+		return func() {}, nil
+	}
+
+	reserved := []quotaScope{}
+	release := func() {
+		for _, scope := range reserved {
+			_, err := q.getUsageCollectionHandle().UpdateOne(context.WithoutCancel(ctx), bson.D{{"_id", scope}}, bson.D{{"$inc", bson.D{
+				{"documents", -change.Documents},
+				{"bytes", -change.Bytes},
+			}}})
+			if err != nil {
+				logger.Errorf("unable to release quota usage of %s: %v", scope.describe("usage"), err)
+			}
+		}
+	}
+
+	for scope, settings := range scopes {
+		if err := q.initialise(ctx, scope); err != nil {
+			release()
+			return nil, err
+		}
+
+		// Growth is only counted while it stays within the limits
+		filter := bson.D{{"_id", scope}}
+		if settings.MaxDocuments > 0 && change.Documents > 0 {
+			filter = append(filter, bson.E{"documents", bson.D{{"$lte", settings.MaxDocuments - change.Documents}}})
+		}
+		if settings.MaxBytes > 0 && change.Bytes > 0 {
+			filter = append(filter, bson.E{"bytes", bson.D{{"$lte", settings.MaxBytes - change.Bytes}}})
+		}
+
+		res, err := q.getUsageCollectionHandle().UpdateOne(ctx, filter, bson.D{{"$inc", bson.D{
+			{"documents", change.Documents},
+			{"bytes", change.Bytes},
+		}}})
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		if res.MatchedCount == 0 {
+			release()
+
+			limit := "max-bytes"
+			if settings.MaxDocuments > 0 && change.Documents > 0 {
+				limit = "max-documents"
+				// Tell which limit was reached when both could have been
+				var usage quotaUsage
+				if err := q.getUsageCollectionHandle().FindOne(ctx, bson.D{{"_id", scope}}).Decode(&usage); err == nil && usage.Documents+change.Documents <= settings.MaxDocuments {
+					limit = "max-bytes"
+				}
+			}
+
+			return nil, &quotaError{limit: scope.describe(limit)}
+		}
+
+		reserved = append(reserved, scope)
+	}
+
+	return release, nil
+}
+
+func newQuotaLimiter(db *mongo.Database, service string, settings map[string]*QuotaSettings, storeCollection func(string) *mongo.Collection) *quotaLimiter {
+	return &quotaLimiter{
+		db:              db,
+		service:         service,
+		settings:        settings,
+		storeCollection: storeCollection,
+	}
+}
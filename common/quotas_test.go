@@ -0,0 +1,74 @@
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestQuotaErr(t *testing.T) {
+	newErr := grpc_errors.ErrorsWithScope("Test")
+
+	t.Run("rate limit", func(t *testing.T) {
+		err := quotaErr(newErr, &quotaError{limit: "ops-per-second of store orders", retryAfter: 250 * time.Millisecond})
+
+		st := status.Convert(err)
+		if st.Code() != codes.ResourceExhausted {
+			t.Fatalf("expected ResourceExhausted, got %s", st.Code())
+		}
+
+		var retry *errdetails.RetryInfo
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				retry = info
+			}
+		}
+
+		if retry == nil || retry.RetryDelay.AsDuration() != 250*time.Millisecond {
+			t.Fatalf("expected a retry delay of 250ms, got %v", st.Details())
+		}
+	})
+
+	t.Run("usage limit", func(t *testing.T) {
+		err := quotaErr(newErr, &quotaError{limit: "max-documents of service api in store orders"})
+
+		st := status.Convert(err)
+		if st.Code() != codes.ResourceExhausted {
+			t.Fatalf("expected ResourceExhausted, got %s", st.Code())
+		}
+
+		var failure *errdetails.QuotaFailure
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.QuotaFailure); ok {
+				failure = info
+			}
+		}
+
+		if failure == nil || len(failure.Violations) != 1 || failure.Violations[0].Subject != "max-documents of service api in store orders" {
+			t.Fatalf("expected a quota failure for the exceeded limit, got %v", st.Details())
+		}
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		err := quotaErr(newErr, errors.New("connection reset"))
+
+		if code := status.Code(err); code != codes.Internal {
+			t.Fatalf("expected Internal, got %s", code)
+		}
+	})
+}
+
+func TestQuotaScopeDescribe(t *testing.T) {
+	if got := (quotaScope{Store: "orders"}).describe("max-bytes"); got != "max-bytes of store orders" {
+		t.Fatalf("unexpected description %q", got)
+	}
+
+	if got := (quotaScope{Store: "orders", Service: "api"}).describe("max-bytes"); got != "max-bytes of service api in store orders" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
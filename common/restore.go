@@ -0,0 +1,789 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	snapshotspb "github.com/nitrictech/mongodb-provider/common/proto/snapshots/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	storagepb "github.com/nitrictech/nitric/core/pkg/proto/storage/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// Documents loaded between progress updates
+	restoreBatchSize = 500
+	// How long a restore is held by a caller without progress before it can be resumed by another
+	restoreLeaseTimeout = time.Minute
+	// The number of keys sampled for each kind of difference in a dry run
+	restoreDiffSampleSize = 20
+
+	mongoErrNamespaceNotFound  = 26
+	mongoErrNamespaceExists    = 48
+	mongoErrIndexAlreadyExists = 68
+	// Returned for search index commands by deployments without Atlas Search
+	mongoErrSearchNotEnabled = 31082
+	mongoErrUnknownStage     = 40324
+)
+
+var (
+	errRestoreNotFound    = errors.New("restore not found")
+	errRestoreInProgress  = errors.New("restore in progress")
+	errRestoreInvalid     = errors.New("invalid restore")
+	errArchiveUnreadable  = errors.New("unreadable snapshot archive")
+	errRestoreUnsupported = errors.New("unsupported restore")
+)
+
+type restoreStoreDocument struct {
+	Store string `bson:"store"`
+	// The store being replaced
+	Target string `bson:"target"`
+	// The collection the snapshot is loaded into before it replaces the target
+	Staging   string `bson:"staging"`
+	Documents int64  `bson:"documents"`
+	Restored  int64  `bson:"restored"`
+	Completed bool   `bson:"completed"`
+}
+
+type restoreDocument struct {
+	Id          string                  `bson:"_id"`
+	Bucket      string                  `bson:"bucket"`
+	Snapshot    string                  `bson:"snapshot"`
+	Stores      []*restoreStoreDocument `bson:"stores"`
+	StartedAt   time.Time               `bson:"startedAt"`
+	LeaseUntil  time.Time               `bson:"leaseUntil"`
+	CompletedAt *time.Time              `bson:"completedAt,omitempty"`
+}
+
+// MongoSnapshotsServer restores key value stores from snapshots written by the SnapshotScheduler.
+//
+// Each store is loaded into a staging collection which then replaces the target store in a single rename,
+// so readers see either the old or the restored store. Progress is recorded in the "snapshots.restores"
+// collection after every batch, an interrupted restore resumes from its last batch.
+type MongoSnapshotsServer struct {
+	db      *mongo.Database
+	storage storagepb.StorageServer
+}
+
+var _ snapshotspb.SnapshotsServer = &MongoSnapshotsServer{}
+
+func (s *MongoSnapshotsServer) getCollectionHandle(collection string) *mongo.Collection {
+	return s.db.Collection(collection)
+}
+
+func (s *MongoSnapshotsServer) getRestoresCollectionHandle() *mongo.Collection {
+	return s.getCollectionHandle("snapshots.restores")
+}
+
+func (s *MongoSnapshotsServer) readManifest(ctx context.Context, bucket string, id string) (*SnapshotManifest, error) {
+	res, err := s.storage.Read(ctx, &storagepb.StorageReadRequest{
+		BucketName: bucket,
+		Key:        snapshotManifestKey(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &SnapshotManifest{}
+	if err := json.Unmarshal(res.Body, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Read the next document of an archive part, io.EOF is returned after the last document
+func readArchiveDocument(reader *bufio.Reader, format string) (bson.Raw, error) {
+	if format == SnapshotFormatBson {
+		// Each document starts with its length, including the length itself
+		var length int32
+		if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+			return nil, err
+		}
+
+		if length < 5 {
+			return nil, fmt.Errorf("invalid document length %d", length)
+		}
+
+		doc := make(bson.Raw, length)
+		binary.LittleEndian.PutUint32(doc, uint32(length))
+		if _, err := io.ReadFull(reader, doc[4:]); err != nil {
+			return nil, err
+		}
+
+		return doc, nil
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if errors.Is(err, io.EOF) && len(bytes.TrimSpace(line)) == 0 {
+		return nil, io.EOF
+	} else if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var fields bson.D
+	if err := bson.UnmarshalExtJSON(line, true, &fields); err != nil {
+		return nil, err
+	}
+
+	return bson.Marshal(fields)
+}
+
+// Read the documents of a store archive in batches of restoreBatchSize, verifying each part against the manifest.
+// The first skip documents are passed over, parts are read one at a time so only a single part is held in memory.
+func (s *MongoSnapshotsServer) readArchive(ctx context.Context, bucket string, format string, entry *SnapshotManifestStore, skip int64, each func(batch []bson.Raw) error) error {
+	batch := make([]bson.Raw, 0, restoreBatchSize)
+
+	for _, part := range entry.Parts {
+		if skip >= part.Documents {
+			skip -= part.Documents
+			continue
+		}
+
+		res, err := s.storage.Read(ctx, &storagepb.StorageReadRequest{
+			BucketName: bucket,
+			Key:        part.Key,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", errArchiveUnreadable, err)
+		}
+
+		sum := sha256.Sum256(res.Body)
+		if hex.EncodeToString(sum[:]) != part.Sha256 {
+			return fmt.Errorf("%w: archive %s doesn't match its manifest checksum", errArchiveUnreadable, part.Key)
+		}
+
+		gz, err := gzip.NewReader(bytes.NewReader(res.Body))
+		if err != nil {
+			return fmt.Errorf("%w: archive %s: %w", errArchiveUnreadable, part.Key, err)
+		}
+
+		reader := bufio.NewReader(gz)
+
+		var count int64
+		for {
+			doc, err := readArchiveDocument(reader, format)
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				gz.Close()
+				return fmt.Errorf("%w: archive %s contains an invalid document: %w", errArchiveUnreadable, part.Key, err)
+			}
+
+			count++
+			if count <= skip {
+				continue
+			}
+
+			batch = append(batch, doc)
+			if len(batch) == restoreBatchSize {
+				if err := each(batch); err != nil {
+					gz.Close()
+					return err
+				}
+
+				batch = make([]bson.Raw, 0, restoreBatchSize)
+			}
+		}
+		gz.Close()
+
+		if count != part.Documents {
+			return fmt.Errorf("%w: archive %s has %d documents, its manifest lists %d", errArchiveUnreadable, part.Key, count, part.Documents)
+		}
+
+		skip = 0
+	}
+
+	if len(batch) > 0 {
+		return each(batch)
+	}
+
+	return nil
+}
+
+// Match the requested stores to the manifest, every store is restored in place when none are requested
+func restoreTargets(manifest *SnapshotManifest, stores []*snapshotspb.StoreRestore) ([]*snapshotspb.StoreRestore, error) {
+	if len(stores) == 0 {
+		for _, entry := range manifest.Stores {
+			stores = append(stores, &snapshotspb.StoreRestore{Store: entry.Store})
+		}
+	}
+
+	targets := make([]*snapshotspb.StoreRestore, 0, len(stores))
+	for _, store := range stores {
+		if manifestStore(manifest, store.Store) == nil {
+			return nil, fmt.Errorf("store %s is not in snapshot %s", store.Store, manifest.Id)
+		}
+
+		target := store.Target
+		if target == "" {
+			target = store.Store
+		}
+
+		targets = append(targets, &snapshotspb.StoreRestore{Store: store.Store, Target: target})
+	}
+
+	return targets, nil
+}
+
+func manifestStore(manifest *SnapshotManifest, store string) *SnapshotManifestStore {
+	for _, entry := range manifest.Stores {
+		if entry.Store == store {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// A printable form of a document key
+func keyString(id bson.RawValue) string {
+	if key, ok := id.StringValueOK(); ok {
+		return key
+	}
+
+	return id.String()
+}
+
+// Hash of the content of a stored document, the same hash MongoDBServer keeps for the value
+func documentHash(doc bson.Raw) (string, error) {
+	content, err := contentFromDocument(doc)
+	if err != nil {
+		return "", err
+	}
+
+	return contentHash(content)
+}
+
+// Compare a store in the snapshot with its target without changing anything
+func (s *MongoSnapshotsServer) diff(ctx context.Context, bucket string, format string, target *snapshotspb.StoreRestore, entry *SnapshotManifestStore) (*snapshotspb.StoreDiff, error) {
+	coll := s.getCollectionHandle(target.Target)
+
+	diff := &snapshotspb.StoreDiff{
+		Store:  target.Store,
+		Target: target.Target,
+	}
+
+	err := s.readArchive(ctx, bucket, format, entry, 0, func(batch []bson.Raw) error {
+		ids := make(bson.A, 0, len(batch))
+		for _, doc := range batch {
+			ids = append(ids, doc.Lookup("_id"))
+		}
+
+		cursor, err := coll.Find(ctx, bson.D{{"_id", bson.D{{"$in", ids}}}})
+		if err != nil {
+			return err
+		}
+
+		// Keyed by the raw key so keys of different types don't collide
+		current := map[string]bson.Raw{}
+		for cursor.Next(ctx) {
+			id := cursor.Current.Lookup("_id")
+			current[string(id.Type)+string(id.Value)] = append(bson.Raw{}, cursor.Current...)
+		}
+		if err := cursor.Err(); err != nil {
+			cursor.Close(ctx)
+			return err
+		}
+		cursor.Close(ctx)
+
+		for _, doc := range batch {
+			id := doc.Lookup("_id")
+
+			existing, ok := current[string(id.Type)+string(id.Value)]
+			if !ok {
+				diff.Added++
+				if len(diff.AddedKeys) < restoreDiffSampleSize {
+					diff.AddedKeys = append(diff.AddedKeys, keyString(id))
+				}
+				continue
+			}
+
+			snapshotHash, err := documentHash(doc)
+			if err != nil {
+				return err
+			}
+
+			currentHash, err := documentHash(existing)
+			if err != nil {
+				return err
+			}
+
+			if snapshotHash == currentHash {
+				diff.Unchanged++
+			} else {
+				diff.Changed++
+				if len(diff.ChangedKeys) < restoreDiffSampleSize {
+					diff.ChangedKeys = append(diff.ChangedKeys, keyString(id))
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := coll.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	diff.Removed = total - diff.Changed - diff.Unchanged
+
+	return diff, nil
+}
+
+// Start a new restore or take over an interrupted one
+func (s *MongoSnapshotsServer) claimRestore(ctx context.Context, req *snapshotspb.SnapshotsRestoreRequest) (*restoreDocument, *SnapshotManifest, error) {
+	now := time.Now()
+
+	if req.RestoreId != "" {
+		restore := &restoreDocument{}
+		err := s.getRestoresCollectionHandle().FindOneAndUpdate(
+			ctx,
+			bson.D{{"_id", req.RestoreId}, {"leaseUntil", bson.D{{"$lte", now}}}},
+			bson.D{{"$set", bson.D{{"leaseUntil", now.Add(restoreLeaseTimeout)}}}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(restore)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			count, countErr := s.getRestoresCollectionHandle().CountDocuments(ctx, bson.D{{"_id", req.RestoreId}})
+			if countErr == nil && count == 0 {
+				return nil, nil, fmt.Errorf("%w: restore %s not found", errRestoreNotFound, req.RestoreId)
+			}
+
+			return nil, nil, fmt.Errorf("%w: restore %s is in progress", errRestoreInProgress, req.RestoreId)
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		manifest, err := s.readManifest(ctx, restore.Bucket, restore.Snapshot)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return restore, manifest, nil
+	}
+
+	manifest, err := s.readManifest(ctx, req.Bucket, req.SnapshotId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targets, err := restoreTargets(manifest, req.Stores)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errRestoreInvalid, err)
+	}
+
+	for _, target := range targets {
+		spec, err := s.collectionSpec(ctx, target.Target)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A time-series collection can't be renamed, so it can't be replaced by a staging collection
+		if spec != nil && spec.Type == "timeseries" {
+			return nil, nil, fmt.Errorf("%w: store %s is a time-series store", errRestoreUnsupported, target.Target)
+		}
+	}
+
+	restore := &restoreDocument{
+		Id:         primitive.NewObjectID().Hex(),
+		Bucket:     req.Bucket,
+		Snapshot:   req.SnapshotId,
+		StartedAt:  now,
+		LeaseUntil: now.Add(restoreLeaseTimeout),
+	}
+
+	for _, target := range targets {
+		restore.Stores = append(restore.Stores, &restoreStoreDocument{
+			Store:     target.Store,
+			Target:    target.Target,
+			Staging:   fmt.Sprintf("snapshots.restores.%s.%s", restore.Id, target.Target),
+			Documents: manifestStore(manifest, target.Store).Documents,
+		})
+	}
+
+	if _, err := s.getRestoresCollectionHandle().InsertOne(ctx, restore); err != nil {
+		return nil, nil, err
+	}
+
+	return restore, manifest, nil
+}
+
+// Release the lease on a restore so it can be resumed straight away
+func (s *MongoSnapshotsServer) releaseRestore(restore *restoreDocument, completed bool) error {
+	// The caller may have gone away, so the restore is released regardless of the request context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.D{{"leaseUntil", time.Now()}}
+	if completed {
+		update = append(update, bson.E{"completedAt", time.Now()})
+	}
+
+	_, err := s.getRestoresCollectionHandle().UpdateOne(ctx, bson.D{{"_id", restore.Id}}, bson.D{{"$set", update}})
+
+	return err
+}
+
+// Record the progress of a store and extend the lease on the restore
+func (s *MongoSnapshotsServer) recordProgress(ctx context.Context, restore *restoreDocument, store *restoreStoreDocument) error {
+	_, err := s.getRestoresCollectionHandle().UpdateOne(
+		ctx,
+		bson.D{{"_id", restore.Id}, {"stores.target", store.Target}},
+		bson.D{{"$set", bson.D{
+			{"stores.$.restored", store.Restored},
+			{"stores.$.completed", store.Completed},
+			{"leaseUntil", time.Now().Add(restoreLeaseTimeout)},
+		}}},
+	)
+
+	return err
+}
+
+func (s *MongoSnapshotsServer) collectionExists(ctx context.Context, name string) (bool, error) {
+	names, err := s.db.ListCollectionNames(ctx, bson.D{{"name", name}})
+	if err != nil {
+		return false, err
+	}
+
+	return len(names) > 0, nil
+}
+
+// The specification of a collection, nil when it doesn't exist
+func (s *MongoSnapshotsServer) collectionSpec(ctx context.Context, name string) (*mongo.CollectionSpecification, error) {
+	specs, err := s.db.ListCollectionSpecifications(ctx, bson.D{{"name", name}})
+	if err != nil || len(specs) == 0 {
+		return nil, err
+	}
+
+	return specs[0], nil
+}
+
+// Create the staging collection of a store with the options of its target, such as the size of a capped store
+func (s *MongoSnapshotsServer) createStaging(ctx context.Context, store *restoreStoreDocument) error {
+	spec, err := s.collectionSpec(ctx, store.Target)
+	if err != nil || spec == nil {
+		return err
+	}
+
+	command := bson.D{{"create", store.Staging}}
+	if spec.Options != nil {
+		elements, err := spec.Options.Elements()
+		if err != nil {
+			return err
+		}
+
+		for _, element := range elements {
+			command = append(command, bson.E{element.Key(), element.Value()})
+		}
+	}
+
+	var serverErr mongo.ServerError
+
+	err = s.db.RunCommand(ctx, command).Err()
+	if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrNamespaceExists)) {
+		return fmt.Errorf("unable to create staging collection of store %s: %w", store.Target, err)
+	}
+
+	return nil
+}
+
+// Copy the Atlas Search and vector search indexes of the target store to its staging collection.
+//
+// Atlas builds the copies asynchronously, so searches of a restored store may not be served until they are built.
+func (s *MongoSnapshotsServer) copySearchIndexes(ctx context.Context, store *restoreStoreDocument) error {
+	var serverErr mongo.ServerError
+
+	cursor, err := s.getCollectionHandle(store.Target).SearchIndexes().List(ctx, nil)
+	if errors.As(err, &serverErr) && (serverErr.HasErrorCode(mongoErrNamespaceNotFound) ||
+		serverErr.HasErrorCode(mongoErrSearchNotEnabled) || serverErr.HasErrorCode(mongoErrUnknownStage)) {
+		// The target doesn't exist or the deployment has no search indexes
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var indexes []existingSearchIndex
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	create := bson.A{}
+	for _, index := range indexes {
+		spec := bson.D{{"name", index.Name}, {"definition", index.LatestDefinition}}
+		if index.Type != "" {
+			spec = append(spec, bson.E{"type", index.Type})
+		}
+
+		create = append(create, spec)
+	}
+
+	err = s.db.RunCommand(ctx, bson.D{
+		{"createSearchIndexes", store.Staging},
+		{"indexes", create},
+	}).Err()
+	// A resumed restore may have copied them already
+	if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrIndexAlreadyExists)) {
+		return fmt.Errorf("unable to copy search indexes of store %s: %w", store.Target, err)
+	}
+
+	return nil
+}
+
+// Replace the target store with the loaded staging collection, keeping the indexes and search indexes of the target
+func (s *MongoSnapshotsServer) replaceStore(ctx context.Context, store *restoreStoreDocument) error {
+	exists, err := s.collectionExists(ctx, store.Staging)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if store.Documents == 0 {
+			_, err := s.getCollectionHandle(store.Target).DeleteMany(ctx, bson.D{})
+			return err
+		}
+
+		// The staging collection already replaced the target before the restore was interrupted
+		return nil
+	}
+
+	cursor, err := s.getCollectionHandle(store.Target).Indexes().List(ctx)
+	var serverErr mongo.ServerError
+	if err != nil && !(errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrNamespaceNotFound)) {
+		return err
+	}
+
+	if err == nil {
+		indexes := bson.A{}
+		for cursor.Next(ctx) {
+			if name, _ := cursor.Current.Lookup("name").StringValueOK(); name != "_id_" {
+				indexes = append(indexes, append(bson.Raw{}, cursor.Current...))
+			}
+		}
+		cursor.Close(ctx)
+
+		if len(indexes) > 0 {
+			err := s.db.RunCommand(ctx, bson.D{
+				{"createIndexes", store.Staging},
+				{"indexes", indexes},
+			}).Err()
+			if err != nil {
+				return fmt.Errorf("unable to copy indexes of store %s: %w", store.Target, err)
+			}
+		}
+	}
+
+	if err := s.copySearchIndexes(ctx, store); err != nil {
+		return err
+	}
+
+	return s.db.Client().Database("admin").RunCommand(ctx, bson.D{
+		{"renameCollection", s.db.Name() + "." + store.Staging},
+		{"to", s.db.Name() + "." + store.Target},
+		{"dropTarget", true},
+	}).Err()
+}
+
+// Load the remaining documents of a store and replace its target
+func (s *MongoSnapshotsServer) restoreStore(ctx context.Context, restore *restoreDocument, manifest *SnapshotManifest, store *restoreStoreDocument, send func(*restoreStoreDocument) error) error {
+	staging := s.getCollectionHandle(store.Staging)
+
+	// Once documents are loaded the staging collection exists, or has already replaced the target
+	if store.Restored == 0 {
+		if err := s.createStaging(ctx, store); err != nil {
+			return err
+		}
+	}
+
+	err := s.readArchive(ctx, restore.Bucket, manifest.Format, manifestStore(manifest, store.Store), store.Restored, func(batch []bson.Raw) error {
+		// Replacing by key makes a batch safe to load again after an interruption
+		models := make([]mongo.WriteModel, 0, len(batch))
+		for _, doc := range batch {
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(bson.D{{"_id", doc.Lookup("_id")}}).
+				SetReplacement(doc).
+				SetUpsert(true))
+		}
+
+		if _, err := staging.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+
+		store.Restored += int64(len(batch))
+		if err := s.recordProgress(ctx, restore, store); err != nil {
+			return err
+		}
+
+		return send(store)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.replaceStore(ctx, store); err != nil {
+		return err
+	}
+
+	store.Completed = true
+	if err := s.recordProgress(ctx, restore, store); err != nil {
+		return err
+	}
+
+	return send(store)
+}
+
+// Restore key value stores from a snapshot, streaming progress as documents are loaded
+func (s *MongoSnapshotsServer) Restore(req *snapshotspb.SnapshotsRestoreRequest, stream snapshotspb.Snapshots_RestoreServer) error {
+	newErr := grpc_errors.ErrorsWithScope("MongoSnapshotsServer.Restore")
+
+	ctx := stream.Context()
+
+	if req.Mode == snapshotspb.RestoreMode_DryRun {
+		manifest, err := s.readManifest(ctx, req.Bucket, req.SnapshotId)
+		if err != nil {
+			return newErr(
+				codes.NotFound,
+				fmt.Sprintf("unable to read manifest of snapshot %s", req.SnapshotId),
+				err,
+			)
+		}
+
+		targets, err := restoreTargets(manifest, req.Stores)
+		if err != nil {
+			return newErr(
+				codes.InvalidArgument,
+				"invalid restore request",
+				err,
+			)
+		}
+
+		for _, target := range targets {
+			diff, err := s.diff(ctx, req.Bucket, manifest.Format, target, manifestStore(manifest, target.Store))
+			if errors.Is(err, errArchiveUnreadable) {
+				return newErr(
+					codes.FailedPrecondition,
+					fmt.Sprintf("unable to read snapshot of store %s", target.Store),
+					err,
+				)
+			} else if err != nil {
+				return newErr(
+					codes.Internal,
+					fmt.Sprintf("unable to compare store %s", target.Target),
+					err,
+				)
+			}
+
+			if err := stream.Send(&snapshotspb.SnapshotsRestoreResponse{
+				Update: &snapshotspb.SnapshotsRestoreResponse_Diff{Diff: diff},
+			}); err != nil {
+				return newErr(
+					codes.Internal,
+					"failed to send response",
+					err,
+				)
+			}
+		}
+
+		return nil
+	}
+
+	restore, manifest, err := s.claimRestore(ctx, req)
+	if errors.Is(err, errRestoreNotFound) {
+		return newErr(codes.NotFound, "unable to resume restore", err)
+	} else if errors.Is(err, errRestoreInProgress) {
+		return newErr(codes.FailedPrecondition, "unable to resume restore", err)
+	} else if errors.Is(err, errRestoreInvalid) {
+		return newErr(codes.InvalidArgument, "invalid restore request", err)
+	} else if errors.Is(err, errRestoreUnsupported) {
+		return newErr(codes.FailedPrecondition, "unable to start restore", err)
+	} else if err != nil {
+		return newErr(
+			codes.Internal,
+			"unable to start restore",
+			err,
+		)
+	}
+
+	send := func(store *restoreStoreDocument) error {
+		return stream.Send(&snapshotspb.SnapshotsRestoreResponse{
+			RestoreId: restore.Id,
+			Update: &snapshotspb.SnapshotsRestoreResponse_Progress{
+				Progress: &snapshotspb.StoreProgress{
+					Store:     store.Store,
+					Target:    store.Target,
+					Documents: store.Documents,
+					Restored:  store.Restored,
+					Completed: store.Completed,
+				},
+			},
+		})
+	}
+
+	for _, store := range restore.Stores {
+		if store.Completed {
+			if err := send(store); err != nil {
+				return newErr(
+					codes.Internal,
+					"failed to send response",
+					err,
+				)
+			}
+			continue
+		}
+
+		if err := s.restoreStore(ctx, restore, manifest, store, send); err != nil {
+			_ = s.releaseRestore(restore, false)
+
+			if errors.Is(err, errArchiveUnreadable) {
+				return newErr(
+					codes.FailedPrecondition,
+					fmt.Sprintf("unable to read snapshot of store %s, resume with restore id %s", store.Store, restore.Id),
+					err,
+				)
+			}
+
+			return newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to restore store %s, resume with restore id %s", store.Target, restore.Id),
+				err,
+			)
+		}
+	}
+
+	if err := s.releaseRestore(restore, true); err != nil {
+		return newErr(
+			codes.Internal,
+			"unable to record restore completion",
+			err,
+		)
+	}
+
+	return nil
+}
+
+func NewSnapshots(db *mongo.Database, storage storagepb.StorageServer) *MongoSnapshotsServer {
+	return &MongoSnapshotsServer{
+		db:      db,
+		storage: storage,
+	}
+}
@@ -0,0 +1,323 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	snapshotspb "github.com/nitrictech/mongodb-provider/common/proto/snapshots/v1"
+	storagepb "github.com/nitrictech/nitric/core/pkg/proto/storage/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A storage plugin that keeps blobs in memory
+type testStorage struct {
+	storagepb.UnimplementedStorageServer
+
+	blobs map[string][]byte
+}
+
+func newTestStorage() *testStorage {
+	return &testStorage{blobs: map[string][]byte{}}
+}
+
+func (s *testStorage) Read(ctx context.Context, req *storagepb.StorageReadRequest) (*storagepb.StorageReadResponse, error) {
+	body, ok := s.blobs[req.BucketName+"/"+req.Key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "key %s not found", req.Key)
+	}
+
+	return &storagepb.StorageReadResponse{Body: body}, nil
+}
+
+func (s *testStorage) Write(ctx context.Context, req *storagepb.StorageWriteRequest) (*storagepb.StorageWriteResponse, error) {
+	s.blobs[req.BucketName+"/"+req.Key] = req.Body
+
+	return &storagepb.StorageWriteResponse{}, nil
+}
+
+// Write an archive part of the documents to the backups bucket
+func writeTestPart(t *testing.T, storage *testStorage, key string, format string, docs ...bson.D) *SnapshotManifestPart {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+
+	for _, doc := range docs {
+		record, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if format == SnapshotFormatJsonl {
+			line, err := bson.MarshalExtJSON(doc, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			record = append(line, '\n')
+		}
+
+		if _, err := gz.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	storage.blobs["backups/"+key] = buf.Bytes()
+	sum := sha256.Sum256(buf.Bytes())
+
+	return &SnapshotManifestPart{
+		Key:       key,
+		Documents: int64(len(docs)),
+		Size:      int64(buf.Len()),
+		Sha256:    hex.EncodeToString(sum[:]),
+	}
+}
+
+func testDocuments(ids ...string) []bson.D {
+	docs := []bson.D{}
+	for _, id := range ids {
+		docs = append(docs, bson.D{{"_id", id}, {"value", bson.D{{"name", id}}}})
+	}
+
+	return docs
+}
+
+// The ids of the documents read from an archive, skipping the first documents
+func readTestArchive(snapshots *MongoSnapshotsServer, format string, entry *SnapshotManifestStore, skip int64) ([]string, error) {
+	ids := []string{}
+
+	err := snapshots.readArchive(context.Background(), "backups", format, entry, skip, func(batch []bson.Raw) error {
+		for _, doc := range batch {
+			ids = append(ids, keyString(doc.Lookup("_id")))
+		}
+		return nil
+	})
+
+	return ids, err
+}
+
+func TestReadArchive(t *testing.T) {
+	for _, format := range []string{SnapshotFormatJsonl, SnapshotFormatBson} {
+		t.Run(format, func(t *testing.T) {
+			storage := newTestStorage()
+			snapshots := NewSnapshots(nil, storage)
+
+			entry := &SnapshotManifestStore{
+				Store:     "orders",
+				Documents: 3,
+				Parts: []*SnapshotManifestPart{
+					writeTestPart(t, storage, snapshotArchiveKey("s", "orders", 0, format), format, testDocuments("a", "b")...),
+					writeTestPart(t, storage, snapshotArchiveKey("s", "orders", 1, format), format, testDocuments("c")...),
+				},
+			}
+
+			ids, err := readTestArchive(snapshots, format, entry, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(ids) != "[a b c]" {
+				t.Fatalf("expected every document in order, got %v", ids)
+			}
+
+			// Resuming skips whole parts and then the documents already restored
+			ids, err = readTestArchive(snapshots, format, entry, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(ids) != "[c]" {
+				t.Fatalf("expected the document after the first 2, got %v", ids)
+			}
+		})
+	}
+}
+
+func TestReadArchiveUnreadable(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(storage *testStorage, part *SnapshotManifestPart)
+	}{
+		{
+			name: "checksum mismatch",
+			change: func(storage *testStorage, part *SnapshotManifestPart) {
+				storage.blobs["backups/"+part.Key][10] ^= 0xff
+			},
+		},
+		{
+			name: "document count mismatch",
+			change: func(storage *testStorage, part *SnapshotManifestPart) {
+				part.Documents = 3
+			},
+		},
+		{
+			name: "missing part",
+			change: func(storage *testStorage, part *SnapshotManifestPart) {
+				delete(storage.blobs, "backups/"+part.Key)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storage := newTestStorage()
+			part := writeTestPart(t, storage, snapshotArchiveKey("s", "orders", 0, SnapshotFormatJsonl), SnapshotFormatJsonl, testDocuments("a", "b")...)
+			test.change(storage, part)
+
+			_, err := readTestArchive(NewSnapshots(nil, storage), SnapshotFormatJsonl, &SnapshotManifestStore{Store: "orders", Parts: []*SnapshotManifestPart{part}}, 0)
+			if !errors.Is(err, errArchiveUnreadable) {
+				t.Fatalf("expected the archive to be unreadable, got %v", err)
+			}
+		})
+	}
+}
+
+// Take a snapshot of the stores to the backups bucket
+func testSnapshot(t *testing.T, scheduler *SnapshotScheduler, id string) *SnapshotManifest {
+	t.Helper()
+
+	manifest, err := scheduler.Snapshot(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unable to take snapshot %s: %v", id, err)
+	}
+
+	return manifest
+}
+
+func restore(snapshots *MongoSnapshotsServer, req *snapshotspb.SnapshotsRestoreRequest) ([]*snapshotspb.SnapshotsRestoreResponse, error) {
+	stream := &testStream[*snapshotspb.SnapshotsRestoreResponse]{}
+	err := snapshots.Restore(req, stream)
+
+	return stream.sent, err
+}
+
+func storeIds(t *testing.T, snapshots *MongoSnapshotsServer, store string) string {
+	t.Helper()
+
+	cursor, err := snapshots.getCollectionHandle(store).Find(context.Background(), bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var docs []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for _, doc := range docs {
+		ids = append(ids, doc.Id)
+	}
+
+	return fmt.Sprint(ids)
+}
+
+func TestRestore(t *testing.T) {
+	db := testDatabase(t)
+	storage := newTestStorage()
+	snapshots := NewSnapshots(db, storage)
+	ctx := context.Background()
+
+	orders := db.Collection("orders")
+	if _, err := orders.InsertMany(ctx, []interface{}{testDocuments("a", "b")[0], testDocuments("a", "b")[1]}); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduler := &SnapshotScheduler{db: db, storage: storage, settings: SnapshotSettings{Bucket: "backups", Format: SnapshotFormatJsonl, Stores: []string{"orders"}}}
+	testSnapshot(t, scheduler, "first")
+
+	// Change the store after the snapshot
+	if _, err := orders.DeleteOne(ctx, bson.D{{"_id", "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.InsertOne(ctx, testDocuments("c")[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := restore(snapshots, &snapshotspb.SnapshotsRestoreRequest{Bucket: "backups", SnapshotId: "first", Mode: snapshotspb.RestoreMode_DryRun})
+	if err != nil {
+		t.Fatalf("unable to compare the snapshot: %v", err)
+	}
+
+	if len(res) != 1 || res[0].GetDiff().Added != 1 || res[0].GetDiff().Removed != 1 || res[0].GetDiff().Unchanged != 1 {
+		t.Fatalf("expected a to be added, c removed and b unchanged, got %v", res)
+	}
+
+	if _, err := restore(snapshots, &snapshotspb.SnapshotsRestoreRequest{Bucket: "backups", SnapshotId: "first"}); err != nil {
+		t.Fatalf("unable to restore the snapshot: %v", err)
+	}
+
+	if ids := storeIds(t, snapshots, "orders"); ids != "[a b]" {
+		t.Fatalf("expected the store as it was snapshot, got %s", ids)
+	}
+
+	// A part that doesn't match its checksum is found before the store is replaced
+	if _, err := orders.InsertOne(ctx, testDocuments("d")[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := testSnapshot(t, scheduler, "second")
+	storage.blobs["backups/"+manifest.Stores[0].Parts[0].Key][10] ^= 0xff
+
+	if _, err := orders.DeleteOne(ctx, bson.D{{"_id", "d"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = restore(snapshots, &snapshotspb.SnapshotsRestoreRequest{Bucket: "backups", SnapshotId: "second"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected the corrupt snapshot to be FailedPrecondition, got %v", err)
+	}
+
+	if ids := storeIds(t, snapshots, "orders"); ids != "[a b]" {
+		t.Fatalf("expected the store to be unchanged by the failed restore, got %s", ids)
+	}
+}
+
+func TestRestoreTimeSeries(t *testing.T) {
+	db := testDatabase(t)
+	storage := newTestStorage()
+	snapshots := NewSnapshots(db, storage)
+	ctx := context.Background()
+
+	err := db.CreateCollection(ctx, "metrics", options.CreateCollection().SetTimeSeriesOptions(options.TimeSeries().SetTimeField("at")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := &SnapshotManifest{
+		Id:     "first",
+		Format: SnapshotFormatJsonl,
+		Stores: []*SnapshotManifestStore{{
+			Store:     "metrics",
+			Documents: 1,
+			Parts:     []*SnapshotManifestPart{writeTestPart(t, storage, snapshotArchiveKey("first", "metrics", 0, SnapshotFormatJsonl), SnapshotFormatJsonl, testDocuments("a")...)},
+		}},
+	}
+
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage.blobs["backups/"+snapshotManifestKey("first")] = body
+
+	_, err = restore(snapshots, &snapshotspb.SnapshotsRestoreRequest{Bucket: "backups", SnapshotId: "first"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected restoring over a time-series store to be FailedPrecondition, got %v", err)
+	}
+}
@@ -2,10 +2,13 @@ package common
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"strings"
 
 	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
 	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
@@ -14,63 +17,125 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type MongoDBServer struct {
 	client *mongo.Client
+	// Whether the server connected the client, rather than being given it
+	ownsClient bool
+	// Database of the stores that aren't routed to a target
+	database string
+	// Databases of the stores routed to other targets, the rest are kept in the client's nitric database
+	storeDatabases map[string]*mongo.Database
+	// Clients of the other targets, by target name
+	targetClients map[string]*mongo.Client
+	// One of the TenancyMode values
+	tenancy string
+	// Run key value operations in causally consistent sessions resumed from the caller's token
+	causal bool
+	// Limits on the use of stores, nil when no store has a quota
+	quotas *quotaLimiter
+	// Creates the declared indexes of stores, nil when none are declared
+	indexes *indexReconciler
+	// Declared vectors of the stores, by store and vector name
+	vectors map[string]map[string]VectorSettings
+	// Declared full-text search settings of the stores, by store name
+	search map[string]SearchSettings
+	// Declared geo fields of the stores, by store name and path
+	geo map[string]map[string]bool
+	// Creates the collections of time-series and capped stores, nil when none are declared
+	collections *storeCollections
+	// Compresses the values of stores with compression enabled, nil when none are
+	compressor *valueCompressor
+	// Shared by the stores with caching enabled, nil when none are
+	cache        *valueCache
+	cachedStores map[string]bool
+	// Stores whose documents may be written by other clients, their _ids and values are mapped to keys and plain content
+	interopStores map[string]bool
 }
 
 var _ kvstorepb.KvStoreServer = &MongoDBServer{}
 
+// The database a store is kept in
+func (k *MongoDBServer) getDatabaseHandle(store string) *mongo.Database {
+	if db, ok := k.storeDatabases[store]; ok {
+		return db
+	}
+
+	return k.client.Database(k.database)
+}
+
 func (k *MongoDBServer) getCollectionHandle(collection string) *mongo.Collection {
-	return k.client.Database("nitric").Collection(collection)
+	return k.getDatabaseHandle(collection).Collection(collection)
+}
+
+// Read a value from its document
+func (k *MongoDBServer) readValue(ctx context.Context, coll *mongo.Collection, store string, id interface{}) (*structpb.Struct, error) {
+	res, err := coll.FindOne(ctx, bson.D{{"_id", id}}).Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	return k.storeContent(store, res)
 }
 
-// Updates a secret, creating a new one if it doesn't already exist
 // Get an existing document
 func (k *MongoDBServer) GetValue(ctx context.Context, req *kvstorepb.KvStoreGetValueRequest) (*kvstorepb.KvStoreGetValueResponse, error) {
 	newErr := grpc_errors.ErrorsWithScope("MongoDBServer.GetValue")
 
-	coll := k.getCollectionHandle(req.Ref.Store)
-
-	filter := bson.D{{"_id", req.Ref.Key}}
-
-	res := coll.FindOne(ctx, filter)
-	if res == nil {
-		return nil, newErr(
-			codes.NotFound,
-			fmt.Sprintf("key %s not found in store %s", req.Ref.Key, req.Ref.Store),
-			fmt.Errorf(""),
-		)
+	if err := k.timeSeriesErr(newErr, req.Ref.Store); err != nil {
+		return nil, err
 	}
 
-	var result primitive.M
-	err := res.Decode(&result)
+	coll, key, err := k.scopedCollection(ctx, req.Ref.Store, req.Ref.Key)
 	if err != nil {
 		return nil, newErr(
-			codes.Internal,
-			"unable to convert value to raw bson",
+			codes.InvalidArgument,
+			"unable to determine the tenant",
 			err,
 		)
 	}
 
-	b, err := json.Marshal(result)
+	id, err := k.storeId(req.Ref.Store, key)
 	if err != nil {
 		return nil, newErr(
-			codes.Internal,
-			"unable to convert value to raw json",
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid key %s", req.Ref.Key),
 			err,
 		)
 	}
 
-	var structContent structpb.Struct
-	err = proto.Unmarshal(b, &structContent)
+	if err := k.quotas.allow(ctx, req.Ref.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	ctx, coll, causal, err := k.causalScope(ctx, newErr, req.Ref.Store, coll)
 	if err != nil {
+		return nil, err
+	}
+	defer causal.end(ctx)
+
+	var structContent *structpb.Struct
+	if k.cachedStores[req.Ref.Store] && !causal.bypassCache() {
+		structContent, err = k.cache.get(ctx, req.Ref.Store, key, func(ctx context.Context) (*structpb.Struct, error) {
+			return k.readValue(ctx, coll, req.Ref.Store, id)
+		})
+	} else {
+		structContent, err = k.readValue(ctx, coll, req.Ref.Store, id)
+	}
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("key %s not found in store %s", req.Ref.Key, req.Ref.Store),
+			err,
+		)
+	} else if err != nil {
 		return nil, newErr(
 			codes.Internal,
-			"unable to convert value to pb struct",
+			fmt.Sprintf("unable to read %s from %s store", req.Ref.Key, req.Ref.Store),
 			err,
 		)
 	}
@@ -78,29 +143,214 @@ func (k *MongoDBServer) GetValue(ctx context.Context, req *kvstorepb.KvStoreGetV
 	return &kvstorepb.KvStoreGetValueResponse{
 		Value: &kvstorepb.Value{
 			Ref:     req.Ref,
-			Content: &structContent,
+			Content: structContent,
 		},
 	}, nil
 }
 
+const (
+	// Fields stored alongside the content of each value
+	revisionField = "_revision"
+	hashField     = "_hash"
+)
+
+// Whether a top level field of a stored document is used by the provider rather than the value content
+func isReservedField(name string) bool {
+	return name == "_id" || name == revisionField || name == hashField || name == compressedField || name == vectorsField
+}
+
+// Hash of the content of a value, used as its ETag
+func contentHash(content *structpb.Struct) (string, error) {
+	// Maps are marshalled with sorted keys, so equal content always has the same hash
+	b, err := json.Marshal(content.AsMap())
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Update that replaces the content of a value and advances its revision, it creates the value when upserted
+func setValueUpdate(content *structpb.Struct) (mongo.Pipeline, error) {
+	return setStoredValueUpdate(content, content.AsMap())
+}
+
+// Update that replaces a value with the fields it is stored as, which may be compressed
+func setStoredValueUpdate(content *structpb.Struct, stored map[string]interface{}) (mongo.Pipeline, error) {
+	hash, err := contentHash(content)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$" + revisionField, 0}}}, 1}}}
+
+	return mongo.Pipeline{
+		bson.D{{"$replaceWith", bson.D{{"$mergeObjects", bson.A{
+			// The content is literal so string values starting with $ aren't read as field paths
+			bson.D{{"$literal", stored}},
+			bson.D{
+				{"_id", "$_id"},
+				{revisionField, revision},
+				{hashField, hash},
+				// Vectors are set apart from the content, missing fields aren't added
+				{vectorsField, "$" + vectorsField},
+			},
+		}}}}},
+	}, nil
+}
+
+// The content of a stored document, without the reserved fields
+func contentFromDocument(doc bson.Raw) (*structpb.Struct, error) {
+	var fields bson.D
+	if err := bson.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+
+	content := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		if field.Key == compressedField {
+			decompressed, err := decompressFields(field.Value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decompress value: %w", err)
+			}
+
+			content = append(content, decompressed...)
+		} else if !isReservedField(field.Key) {
+			content = append(content, field)
+		}
+	}
+
+	// Relaxed extended json keeps numbers as plain json numbers
+	b, err := bson.MarshalExtJSON(content, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	structContent := &structpb.Struct{}
+	if err := protojson.Unmarshal(b, structContent); err != nil {
+		return nil, err
+	}
+
+	return structContent, nil
+}
+
 // Create a new or overwrite an existing document
 func (k *MongoDBServer) SetValue(ctx context.Context, req *kvstorepb.KvStoreSetValueRequest) (*kvstorepb.KvStoreSetValueResponse, error) {
 	newErr := grpc_errors.ErrorsWithScope("MongoDBServer.SetValue")
 
-	coll := k.getCollectionHandle(req.Ref.Store)
+	if err := k.timeSeriesErr(newErr, req.Ref.Store); err != nil {
+		return nil, err
+	}
 
-	contents := req.Content.AsMap()
-	contents["_id"] = req.Ref.Key
+	coll, key, err := k.scopedCollection(ctx, req.Ref.Store, req.Ref.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
 
-	_, err := coll.InsertOne(ctx, contents)
+	id, err := k.storeId(req.Ref.Store, key)
 	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid key %s", req.Ref.Key),
+			err,
+		)
+	}
+
+	if err := k.quotas.allow(ctx, req.Ref.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	if err := k.collections.ensure(ctx, req.Ref.Store, coll); err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("unable to create the collection of %s store", req.Ref.Store),
+			err,
+		)
+	}
+
+	stored, err := k.compressor.stored(ctx, req.Ref.Store, req.Content)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to compress value content",
+			err,
+		)
+	}
+
+	update, err := setStoredValueUpdate(req.Content, stored)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to hash value content",
+			err,
+		)
+	}
+
+	release, err := k.quotas.reserve(ctx, coll, req.Ref.Store, id, stored)
+	if err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	// Quota usage is kept in the stack's cluster, so the session only covers the store's own operations
+	ctx, coll, causal, err := k.causalScope(ctx, newErr, req.Ref.Store, coll)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	defer causal.end(ctx)
+
+	_, err = coll.UpdateOne(ctx, bson.D{{"_id", id}}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		release()
+
+		// Upserts of the same key retry on their own, so duplicates are from the store's unique indexes
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, newErr(
+				codes.AlreadyExists,
+				fmt.Sprintf("unable to set %s in %s store, a value with the same unique index fields exists", req.Ref.Key, req.Ref.Store),
+				err,
+			)
+		}
+
+		var serverErr mongo.ServerError
+		if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCannotExtractGeoKeys) {
+			return nil, newErr(
+				codes.InvalidArgument,
+				fmt.Sprintf("unable to set %s in %s store, a geo field isn't valid GeoJSON", req.Ref.Key, req.Ref.Store),
+				err,
+			)
+		}
+
+		if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCappedDocumentSize) {
+			return nil, newErr(
+				codes.FailedPrecondition,
+				fmt.Sprintf("unable to set %s in %s store, values of capped stores can only be replaced with values of the same size", req.Ref.Key, req.Ref.Store),
+				err,
+			)
+		}
+
 		return nil, newErr(
 			codes.Internal,
-			fmt.Sprintf("unable to insert %s into %s store", req.Ref.Key, req.Ref.Store),
+			fmt.Sprintf("unable to set %s in %s store", req.Ref.Key, req.Ref.Store),
 			err,
 		)
 	}
 
+	// Tenant databases are created by their first write, so they are indexed then
+	if k.tenancy != TenancyModeNone {
+		k.indexes.ensureTenant(req.Ref.Store, coll)
+	}
+
+	if k.cachedStores[req.Ref.Store] {
+		k.cache.invalidate(req.Ref.Store, key)
+	}
+
 	return &kvstorepb.KvStoreSetValueResponse{}, nil
 }
 
@@ -108,12 +358,53 @@ func (k *MongoDBServer) SetValue(ctx context.Context, req *kvstorepb.KvStoreSetV
 func (k *MongoDBServer) DeleteKey(ctx context.Context, req *kvstorepb.KvStoreDeleteKeyRequest) (*kvstorepb.KvStoreDeleteKeyResponse, error) {
 	newErr := grpc_errors.ErrorsWithScope("MongoDBServer.DeleteValue")
 
-	coll := k.getCollectionHandle(req.Ref.Store)
+	if err := k.timeSeriesErr(newErr, req.Ref.Store); err != nil {
+		return nil, err
+	}
+
+	if err := k.cappedDeleteErr(newErr, req.Ref.Store); err != nil {
+		return nil, err
+	}
 
-	filter := bson.D{{"_id", req.Ref.Key}}
+	coll, key, err := k.scopedCollection(ctx, req.Ref.Store, req.Ref.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
 
-	_, err := coll.DeleteOne(ctx, filter)
+	id, err := k.storeId(req.Ref.Store, key)
 	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid key %s", req.Ref.Key),
+			err,
+		)
+	}
+
+	if err := k.quotas.allow(ctx, req.Ref.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	filter := bson.D{{"_id", id}}
+
+	release, err := k.quotas.reserve(ctx, coll, req.Ref.Store, id, nil)
+	if err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	ctx, coll, causal, err := k.causalScope(ctx, newErr, req.Ref.Store, coll)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	defer causal.end(ctx)
+
+	_, err = coll.DeleteOne(ctx, filter)
+	if err != nil {
+		release()
 		return nil, newErr(
 			codes.Internal,
 			fmt.Sprintf("unable to delete %s from %s store", req.Ref.Key, req.Ref.Store),
@@ -121,6 +412,10 @@ func (k *MongoDBServer) DeleteKey(ctx context.Context, req *kvstorepb.KvStoreDel
 		)
 	}
 
+	if k.cachedStores[req.Ref.Store] {
+		k.cache.invalidate(req.Ref.Store, key)
+	}
+
 	return &kvstorepb.KvStoreDeleteKeyResponse{}, nil
 }
 
@@ -128,20 +423,48 @@ func (k *MongoDBServer) DeleteKey(ctx context.Context, req *kvstorepb.KvStoreDel
 func (k *MongoDBServer) ScanKeys(req *kvstorepb.KvStoreScanKeysRequest, stream kvstorepb.KvStore_ScanKeysServer) error {
 	newErr := grpc_errors.ErrorsWithScope("MongoDBServer.ScanKeys")
 
-	coll := k.getCollectionHandle(req.Store.Name)
+	if err := k.timeSeriesErr(newErr, req.Store.Name); err != nil {
+		return err
+	}
+
+	// In key tenancy mode the prefix starts with the tenant, which is added back to the keys found
+	coll, prefix, err := k.scopedCollection(stream.Context(), req.Store.Name, req.Prefix)
+	if err != nil {
+		return newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := k.quotas.allow(stream.Context(), req.Store.Name); err != nil {
+		return quotaErr(newErr, err)
+	}
+
+	ctx, coll, causal, err := k.causalScope(stream.Context(), newErr, req.Store.Name, coll)
+	if err != nil {
+		return err
+	}
+	defer causal.end(ctx)
 
-	regex := primitive.Regex{Pattern: "^" + req.Prefix, Options: ""}
+	tenantPrefix := strings.TrimSuffix(req.Prefix, prefix)
+
+	regex := primitive.Regex{Pattern: "^" + prefix, Options: ""}
+	match := bson.D{{"_id", bson.D{{"$regex", regex}}}}
+
+	// The keys of typed _ids in interop stores start with $, so scans that can reach them compare the keys of every _id
+	if k.interopStores[req.Store.Name] && (prefix == "" || strings.HasPrefix(prefix, interopKeyPrefix)) {
+		match = bson.D{}
+	}
 
 	// Define your aggregation pipeline
 	pipeline := mongo.Pipeline{
-		bson.D{
-			{"$match", bson.D{{"_id", bson.D{{"$regex", regex}}}}},
-			// Add other stages as needed
-		},
+		bson.D{{"$match", match}},
+		bson.D{{"$project", bson.D{{"_id", 1}}}},
 	}
 
 	// Perform the aggregation
-	cursor, err := coll.Aggregate(context.Background(), pipeline)
+	cursor, err := coll.Aggregate(ctx, pipeline)
 	if err != nil {
 		return newErr(
 			codes.Internal,
@@ -152,16 +475,15 @@ func (k *MongoDBServer) ScanKeys(req *kvstorepb.KvStoreScanKeysRequest, stream k
 	defer cursor.Close(context.Background())
 
 	// Iterate over the results
-	for cursor.Next(context.Background()) {
-		var result bson.M
-		if err := cursor.Decode(&result); err != nil {
-			log.Fatal(err)
+	for cursor.Next(ctx) {
+		// Documents written by other clients may have _ids that no key maps to, they can't be read so they aren't listed
+		key, ok := k.storeKey(req.Store.Name, cursor.Current.Lookup("_id"))
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
 		}
 
-		key := result["_id"].(string)
-
 		if err := stream.Send(&kvstorepb.KvStoreScanKeysResponse{
-			Key: key,
+			Key: tenantPrefix + key,
 		}); err != nil {
 			return newErr(
 				codes.Internal,
@@ -174,19 +496,28 @@ func (k *MongoDBServer) ScanKeys(req *kvstorepb.KvStoreScanKeysRequest, stream k
 	return nil
 }
 
-func New() (*MongoDBServer, error) {
-	ctx := context.TODO()
+// Connect to the cluster configured by MONGO_CLUSTER_CONNECTION_STRING and MONGO_TLS_CONFIG, options override the environment.
+// The returned database is the one the plugins served from the cluster share
+func Connect(ctx context.Context, opts ...Option) (*mongo.Database, error) {
+	config, err := NewConfig(append([]Option{WithEnv()}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
 
-	url := os.Getenv("MONGO_CLUSTER_CONNECTION_STRING")
-	if url == "" {
-		return nil, fmt.Errorf("MONGO_CLUSTER_CONNECTION_STRING is unset")
+	clientOpts, err := config.clusterOptions(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Use the SetServerAPIOptions() method to set the version of the Stable API on the client
-	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
+	client, err := connect(ctx, clientOpts)
+	if err != nil {
+		return nil, err
+	}
 
-	opts := options.Client().ApplyURI(url).SetServerAPIOptions(serverAPI)
+	return client.Database(config.Database), nil
+}
 
+func connect(ctx context.Context, opts *options.ClientOptions) (*mongo.Client, error) {
 	// Create a new client and connect to the server
 	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
@@ -195,10 +526,228 @@ func New() (*MongoDBServer, error) {
 
 	// Send a ping to confirm a successful connection
 	if err := client.Database("admin").RunCommand(ctx, bson.D{{"ping", 1}}).Err(); err != nil {
+		client.Disconnect(ctx)
 		return nil, err
 	}
 
-	return &MongoDBServer{
-		client: client,
-	}, nil
+	return client, nil
+}
+
+// The database of the stack's cluster, shared with the other plugins served from it
+func (k *MongoDBServer) Database() *mongo.Database {
+	return k.client.Database(k.database)
+}
+
+// Create a server configured by the MONGO_ environment variables, options override the environment
+func New(opts ...Option) (*MongoDBServer, error) {
+	return NewWithOptions(context.TODO(), append([]Option{WithEnv()}, opts...)...)
+}
+
+// Create a server configured by the MONGO_ environment variables that serves the stores from a connected client
+func NewWithClient(client *mongo.Client) (*MongoDBServer, error) {
+	return NewWithOptions(context.TODO(), WithEnv(), WithClient(client))
+}
+
+// Create a server configured by options, see NewConfig
+func NewWithOptions(ctx context.Context, opts ...Option) (*MongoDBServer, error) {
+	config, err := NewConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(ctx, config)
+}
+
+// Create a server from a config, connecting to its clusters
+func NewWithConfig(ctx context.Context, config *Config) (*MongoDBServer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	client := config.Client
+	if client == nil {
+		clientOpts, err := config.clusterOptions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to configure the cluster connection: %w", err)
+		}
+
+		client, err = connect(ctx, clientOpts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to the cluster: %w", err)
+		}
+	}
+
+	server := &MongoDBServer{
+		client:         client,
+		ownsClient:     config.Client == nil,
+		database:       config.Database,
+		tenancy:        config.Tenancy,
+		causal:         config.CausalConsistency,
+		storeDatabases: map[string]*mongo.Database{},
+		targetClients:  map[string]*mongo.Client{},
+		cachedStores:   map[string]bool{},
+		interopStores:  map[string]bool{},
+		vectors:        map[string]map[string]VectorSettings{},
+		search:         map[string]SearchSettings{},
+		geo:            map[string]map[string]bool{},
+	}
+
+	for name, target := range config.Targets {
+		targetOpts, err := config.targetOptions(ctx, target)
+		if err != nil {
+			server.Disconnect(context.TODO())
+			return nil, fmt.Errorf("unable to configure the connection to target %s: %w", name, err)
+		}
+
+		targetClient, err := connect(ctx, targetOpts)
+		if err != nil {
+			server.Disconnect(context.TODO())
+			return nil, fmt.Errorf("unable to connect to target %s: %w", name, err)
+		}
+
+		server.targetClients[name] = targetClient
+	}
+
+	settings := config.Stores
+
+	// Cached stores are watched per database
+	cachedStores := map[*mongo.Database][]string{}
+	for name, store := range settings {
+		if store.Target != "" {
+			database := config.Targets[store.Target].Database
+			if database == "" {
+				database = defaultDatabase
+			}
+
+			server.storeDatabases[name] = server.targetClients[store.Target].Database(database)
+		}
+
+		if store.Cache {
+			server.cachedStores[name] = true
+
+			db := server.getDatabaseHandle(name)
+			cachedStores[db] = append(cachedStores[db], name)
+		}
+
+		if store.Interop {
+			server.interopStores[name] = true
+		}
+	}
+
+	quotas := map[string]*QuotaSettings{}
+	for name, store := range settings {
+		if store.Quota != nil {
+			quotas[name] = store.Quota
+		}
+	}
+
+	indexes := map[string][]IndexSettings{}
+	for name, store := range settings {
+		if len(store.Indexes) > 0 {
+			indexes[name] = store.Indexes
+		}
+
+		for _, field := range store.Geo {
+			if server.geo[name] == nil {
+				server.geo[name] = map[string]bool{}
+			}
+			server.geo[name][field] = true
+
+			indexes[name] = append(indexes[name], IndexSettings{Keys: []IndexKey{{Field: field, Geo: true}}})
+		}
+	}
+
+	searchIndexes := map[string][]searchIndex{}
+	for name, store := range settings {
+		if declared := store.searchIndexes(); len(declared) > 0 {
+			searchIndexes[name] = declared
+		}
+
+		if len(store.Vectors) > 0 {
+			server.vectors[name] = store.Vectors
+		}
+
+		if store.Search != nil {
+			server.search[name] = *store.Search
+		}
+	}
+
+	timeSeries := map[string]TimeSeriesSettings{}
+	capped := map[string]CappedSettings{}
+	for name, store := range settings {
+		if store.TimeSeries != nil {
+			timeSeries[name] = *store.TimeSeries
+		}
+
+		if store.Capped != nil {
+			capped[name] = *store.Capped
+		}
+	}
+
+	if len(timeSeries) > 0 || len(capped) > 0 {
+		server.collections = &storeCollections{
+			timeSeries: timeSeries,
+			capped:     capped,
+		}
+	}
+
+	thresholds := map[string]int{}
+	for name, store := range settings {
+		if store.Compression == CompressionZstd {
+			thresholds[name] = store.CompressionThreshold
+			if thresholds[name] <= 0 {
+				thresholds[name] = defaultCompressionThreshold
+			}
+		}
+	}
+
+	if len(thresholds) > 0 {
+		server.compressor = newValueCompressor(thresholds)
+	}
+
+	if len(quotas) > 0 {
+		// Usage is kept with the other shared state in the cluster
+		server.quotas = newQuotaLimiter(server.Database(), config.ServiceName, quotas, server.getCollectionHandle)
+	}
+
+	if len(indexes) > 0 || len(searchIndexes) > 0 {
+		server.indexes = &indexReconciler{
+			indexes: indexes,
+			search:  searchIndexes,
+			dryRun:  config.IndexesDryRun,
+		}
+	}
+
+	if server.indexes != nil || server.collections != nil {
+		// Collections and indexes are created in the background, requests are served while they are.
+		// Time-series and capped collections are created first, creating indexes would create them as regular collections
+		go func() {
+			server.collections.ensureAll(context.Background(), server)
+			server.indexes.reconcileAll(context.Background(), server)
+		}()
+	}
+
+	if len(cachedStores) > 0 {
+		server.cache = newValueCache(int64(config.CacheSizeMb) << 20)
+		for db, stores := range cachedStores {
+			go server.cache.watch(context.Background(), db, stores)
+		}
+	}
+
+	return server, nil
+}
+
+// Disconnect the clients the server connected, a client it was given is left connected
+func (k *MongoDBServer) Disconnect(ctx context.Context) {
+	for name, client := range k.targetClients {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("unable to disconnect from target %s: %v", name, err)
+		}
+	}
+
+	if k.ownsClient {
+		if err := k.client.Disconnect(ctx); err != nil {
+			log.Printf("unable to disconnect from the cluster: %v", err)
+		}
+	}
 }
@@ -0,0 +1,468 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	searchpb "github.com/nitrictech/mongodb-provider/common/proto/search/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// Name of the Atlas Search index of a store
+	searchIndexName = "search"
+
+	defaultSearchLimit    = 20
+	maxSearchLimit        = 100
+	defaultSearchMaxEdits = 2
+	// The number of values counted for each facet
+	searchFacetBuckets = 20
+)
+
+// SearchFieldSettings declare a text field of the values of a key value store
+type SearchFieldSettings struct {
+	// Dot separated path of the field
+	Path string `json:"path"`
+	// Analyzer of the field's text, defaults to the index analyzer
+	Analyzer string `json:"analyzer,omitempty"`
+}
+
+// SearchSettings declare the Atlas Search index of a key value store
+type SearchSettings struct {
+	// Analyzer of the text fields, defaults to lucene.standard
+	Analyzer string                `json:"analyzer,omitempty"`
+	Fields   []SearchFieldSettings `json:"fields"`
+	// Dot separated paths of the string fields queries can count and filter on
+	Facets []string `json:"facets,omitempty"`
+}
+
+func (s SearchSettings) validate() error {
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("requires at least one text field")
+	}
+
+	for _, field := range s.Fields {
+		if err := validateFieldPath(field.Path); err != nil {
+			return fmt.Errorf("invalid field: %w", err)
+		}
+	}
+
+	for _, facet := range s.Facets {
+		if err := validateFieldPath(facet); err != nil {
+			return fmt.Errorf("invalid facet: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// A field of an index mapping, fields of documents are mapped within their parent
+type searchMappingNode struct {
+	types    bson.A
+	children map[string]*searchMappingNode
+}
+
+func (n *searchMappingNode) add(path string, types ...bson.D) {
+	node := n
+	for _, segment := range strings.Split(path, ".") {
+		if node.children == nil {
+			node.children = map[string]*searchMappingNode{}
+		}
+
+		if node.children[segment] == nil {
+			node.children[segment] = &searchMappingNode{}
+		}
+
+		node = node.children[segment]
+	}
+
+	for _, t := range types {
+		node.types = append(node.types, t)
+	}
+}
+
+// The fields of a mapping, ordered by name so the definition is the same on every start
+func (n *searchMappingNode) fields() bson.D {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := bson.D{}
+	for _, name := range names {
+		child := n.children[name]
+
+		types := child.types
+		if len(child.children) > 0 {
+			types = append(types, bson.D{{"type", "document"}, {"fields", child.fields()}})
+		}
+
+		if len(types) == 1 {
+			fields = append(fields, bson.E{name, types[0]})
+		} else {
+			fields = append(fields, bson.E{name, types})
+		}
+	}
+
+	return fields
+}
+
+// The search index of a store, only the declared fields are indexed
+func (s SearchSettings) searchIndex() searchIndex {
+	root := &searchMappingNode{}
+
+	for _, field := range s.Fields {
+		text := bson.D{{"type", "string"}}
+		if field.Analyzer != "" {
+			text = append(text, bson.E{"analyzer", field.Analyzer})
+		}
+
+		root.add(field.Path, text)
+	}
+
+	// Token fields are matched whole by filters, string facets are counted
+	for _, facet := range s.Facets {
+		root.add(facet, bson.D{{"type", "token"}}, bson.D{{"type", "stringFacet"}})
+	}
+
+	definition := bson.D{}
+	if s.Analyzer != "" {
+		definition = append(definition, bson.E{"analyzer", s.Analyzer})
+	}
+	definition = append(definition, bson.E{"mappings", bson.D{{"dynamic", false}, {"fields", root.fields()}}})
+
+	return searchIndex{
+		Name:       searchIndexName,
+		Type:       "search",
+		Definition: definition,
+	}
+}
+
+// MongoSearchServer searches the text of the values stored by MongoDBServer with Atlas Search.
+//
+// Only the fields declared in a store's search settings are indexed, so compressed values are never found.
+type MongoSearchServer struct {
+	kv *MongoDBServer
+}
+
+var _ searchpb.SearchServer = &MongoSearchServer{}
+
+// The search operator of a filter on a facet field
+func searchFilter(filter *documentspb.Filter) (bson.D, error) {
+	switch c := filter.Condition.(type) {
+	case *documentspb.Filter_Equals:
+		value, ok := c.Equals.AsInterface().(string)
+		if !ok {
+			return nil, fmt.Errorf("filter on %s requires a string value", filter.Path)
+		}
+
+		return bson.D{{"equals", bson.D{{"path", filter.Path}, {"value", value}}}}, nil
+	case *documentspb.Filter_In:
+		values := bson.A{}
+		for _, v := range c.In.GetValues() {
+			value, ok := v.AsInterface().(string)
+			if !ok {
+				return nil, fmt.Errorf("filter on %s requires string values", filter.Path)
+			}
+
+			values = append(values, value)
+		}
+
+		if len(values) == 0 {
+			return nil, fmt.Errorf("filter on %s requires a value", filter.Path)
+		}
+
+		return bson.D{{"in", bson.D{{"path", filter.Path}, {"value", values}}}}, nil
+	default:
+		return nil, fmt.Errorf("filter on %s must be an equals or in condition", filter.Path)
+	}
+}
+
+// searchMatch is the shape matches are read in, so the score and highlights are kept apart from the content
+type searchMatch struct {
+	Id         bson.RawValue `bson:"_id"`
+	Score      float64       `bson:"score"`
+	Highlights []struct {
+		Path  string `bson:"path"`
+		Texts []struct {
+			Value string `bson:"value"`
+			Type  string `bson:"type"`
+		} `bson:"texts"`
+		Score float64 `bson:"score"`
+	} `bson:"highlights"`
+	Content bson.Raw `bson:"content"`
+}
+
+// searchMeta is the count and facets of a query
+type searchMeta struct {
+	Count struct {
+		LowerBound int64 `bson:"lowerBound"`
+	} `bson:"count"`
+	Facet map[string]struct {
+		Buckets []struct {
+			Id    interface{} `bson:"_id"`
+			Count int64       `bson:"count"`
+		} `bson:"buckets"`
+	} `bson:"facet"`
+}
+
+// searchResults is the page of matches and the metadata of a query
+type searchResults struct {
+	Matches []searchMatch `bson:"matches"`
+	Meta    []searchMeta  `bson:"meta"`
+}
+
+// Find the values of a store that best match a text query
+func (s *MongoSearchServer) Query(ctx context.Context, req *searchpb.SearchQueryRequest) (*searchpb.SearchQueryResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoSearchServer.Query")
+
+	settings, ok := s.kv.search[req.Store]
+	if !ok {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			"search not declared",
+			fmt.Errorf("store %s has no search settings", req.Store),
+		)
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	if req.Text == "" || limit < 0 || limit > maxSearchLimit || req.Offset < 0 || req.MaxEdits < 0 || req.MaxEdits > 2 {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("text is required, limit must be between 1 and %d, offset must not be negative and max_edits must be 1 or 2", maxSearchLimit),
+			fmt.Errorf("limit %d, offset %d, max_edits %d", req.Limit, req.Offset, req.MaxEdits),
+		)
+	}
+
+	textFields := map[string]bool{}
+	for _, field := range settings.Fields {
+		textFields[field.Path] = true
+	}
+
+	facetFields := map[string]bool{}
+	for _, facet := range settings.Facets {
+		facetFields[facet] = true
+	}
+
+	paths := req.Paths
+	if len(paths) == 0 {
+		for _, field := range settings.Fields {
+			paths = append(paths, field.Path)
+		}
+	}
+
+	for _, path := range paths {
+		if !textFields[path] {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid path",
+				fmt.Errorf("%s isn't a declared text field", path),
+			)
+		}
+	}
+
+	text := bson.D{{"query", req.Text}, {"path", paths}}
+	if req.Fuzzy {
+		maxEdits := int(req.MaxEdits)
+		if maxEdits == 0 {
+			maxEdits = defaultSearchMaxEdits
+		}
+
+		text = append(text, bson.E{"fuzzy", bson.D{{"maxEdits", maxEdits}}})
+	}
+
+	filters := bson.A{}
+	for _, filter := range req.Filters {
+		operator, err := searchFilter(filter)
+		if err == nil && !facetFields[filter.Path] {
+			err = fmt.Errorf("%s isn't a declared facet field", filter.Path)
+		}
+
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid filter",
+				err,
+			)
+		}
+
+		filters = append(filters, operator)
+	}
+
+	operator := bson.D{{"compound", bson.D{
+		{"must", bson.A{bson.D{{"text", text}}}},
+		{"filter", filters},
+	}}}
+	if len(filters) == 0 {
+		operator = bson.D{{"text", text}}
+	}
+
+	search := bson.D{{"index", searchIndexName}}
+
+	// Facets are named by position, since paths can't be used as names
+	if len(req.Facets) > 0 {
+		facets := bson.D{}
+		for i, path := range req.Facets {
+			if !facetFields[path] {
+				return nil, newErr(
+					codes.InvalidArgument,
+					"invalid facet",
+					fmt.Errorf("%s isn't a declared facet field", path),
+				)
+			}
+
+			facets = append(facets, bson.E{fmt.Sprintf("f%d", i), bson.D{
+				{"type", "string"},
+				{"path", path},
+				{"numBuckets", searchFacetBuckets},
+			}})
+		}
+
+		search = append(search, bson.E{"facet", bson.D{{"operator", operator}, {"facets", facets}}})
+	} else {
+		search = append(search, operator...)
+	}
+
+	search = append(search, bson.E{"count", bson.D{{"type", "lowerBound"}}})
+	if req.Highlight {
+		search = append(search, bson.E{"highlight", bson.D{{"path", paths}}})
+	}
+
+	// Queries aren't made with a key, so in key tenancy mode the tenant is given as a key prefix would be
+	tenantPrefix := ""
+	if s.kv.tenancy == TenancyModeKey {
+		tenantPrefix = req.Tenant + tenantKeySeparator
+	}
+
+	coll, _, err := s.kv.scopedCollection(ctx, req.Store, tenantPrefix)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := s.kv.quotas.allow(ctx, req.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	match := bson.D{
+		{"_id", "$_id"},
+		{"score", bson.D{{"$meta", "searchScore"}}},
+		{"content", "$$ROOT"},
+	}
+	if req.Highlight {
+		match = append(match, bson.E{"highlights", bson.D{{"$meta", "searchHighlights"}}})
+	}
+
+	// The count and facets are read alongside the page, so they are returned for pages after the last match
+	pipeline := mongo.Pipeline{
+		bson.D{{"$search", search}},
+		bson.D{{"$facet", bson.D{
+			{"matches", bson.A{
+				bson.D{{"$skip", req.Offset}},
+				bson.D{{"$limit", limit}},
+				bson.D{{"$replaceWith", match}},
+			}},
+			{"meta", bson.A{
+				bson.D{{"$replaceWith", "$$SEARCH_META"}},
+				bson.D{{"$limit", 1}},
+			}},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to search store %s", req.Store),
+			err,
+		)
+	}
+
+	var results []searchResults
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to search store %s", req.Store),
+			err,
+		)
+	}
+
+	res := &searchpb.SearchQueryResponse{
+		Matches: []*searchpb.SearchMatch{},
+		Facets:  map[string]*searchpb.Facet{},
+	}
+
+	for _, path := range req.Facets {
+		res.Facets[path] = &searchpb.Facet{Buckets: []*searchpb.FacetBucket{}}
+	}
+
+	// $facet returns a single document, the metadata is empty when nothing matched
+	if len(results) == 0 {
+		return res, nil
+	}
+
+	if len(results[0].Meta) > 0 {
+		meta := results[0].Meta[0]
+
+		res.Total = meta.Count.LowerBound
+		for i, path := range req.Facets {
+			for _, bucket := range meta.Facet[fmt.Sprintf("f%d", i)].Buckets {
+				res.Facets[path].Buckets = append(res.Facets[path].Buckets, &searchpb.FacetBucket{
+					Value: fmt.Sprint(bucket.Id),
+					Count: bucket.Count,
+				})
+			}
+		}
+	}
+
+	for _, result := range results[0].Matches {
+		content, err := contentFromDocument(result.Content)
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"unable to convert value to pb struct",
+				err,
+			)
+		}
+
+		m := &searchpb.SearchMatch{
+			Key:        tenantPrefix + keyString(result.Id),
+			Score:      result.Score,
+			Content:    content,
+			Highlights: []*searchpb.Highlight{},
+		}
+
+		for _, highlight := range result.Highlights {
+			h := &searchpb.Highlight{Path: highlight.Path, Score: highlight.Score}
+			for _, t := range highlight.Texts {
+				h.Texts = append(h.Texts, &searchpb.HighlightText{Value: t.Value, Hit: t.Type == "hit"})
+			}
+
+			m.Highlights = append(m.Highlights, h)
+		}
+
+		res.Matches = append(res.Matches, m)
+	}
+
+	return res, nil
+}
+
+func NewSearch(kv *MongoDBServer) *MongoSearchServer {
+	return &MongoSearchServer{
+		kv: kv,
+	}
+}
@@ -0,0 +1,338 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nitrictech/mongodb-provider/common/env"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	storagepb "github.com/nitrictech/nitric/core/pkg/proto/storage/v1"
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// One canonical extended json document per line
+	SnapshotFormatJsonl = "jsonl"
+	// Concatenated bson documents, as written by mongodump
+	SnapshotFormatBson = "bson"
+
+	// Folder of the bucket snapshots are written to
+	snapshotPrefix = "snapshots"
+	// Format of snapshot ids, snapshots are taken at most once a minute
+	snapshotIdFormat = "20060102T1504Z"
+	// Uncompressed bytes written to an archive part before the next part is started,
+	// this bounds the memory held while a store is archived or restored
+	snapshotPartSize = 64 << 20
+)
+
+// SnapshotSettings control scheduled snapshots, they are decoded from MONGO_SNAPSHOTS_CONFIG
+type SnapshotSettings struct {
+	// The Nitric bucket snapshots are written to
+	Bucket string `json:"bucket"`
+	// Cron expression for when snapshots are taken
+	Schedule string `json:"schedule"`
+	// Archive format, jsonl or bson
+	Format string `json:"format"`
+	// The key value stores to snapshot
+	Stores []string `json:"stores"`
+}
+
+// SnapshotManifest describes the archives of a snapshot, it is written alongside them as manifest.json
+type SnapshotManifest struct {
+	Id        string                   `json:"id"`
+	CreatedAt time.Time                `json:"createdAt"`
+	Format    string                   `json:"format"`
+	Stores    []*SnapshotManifestStore `json:"stores"`
+}
+
+type SnapshotManifestStore struct {
+	// The key value store name
+	Store string `json:"store"`
+	// Number of documents in the archive
+	Documents int64 `json:"documents"`
+	// The archive parts in document order
+	Parts []*SnapshotManifestPart `json:"parts"`
+}
+
+type SnapshotManifestPart struct {
+	// Key of the gzip compressed part in the bucket
+	Key string `json:"key"`
+	// Number of documents in the part
+	Documents int64 `json:"documents"`
+	// Size of the compressed part in bytes
+	Size int64 `json:"size"`
+	// Hex encoded SHA-256 of the compressed part
+	Sha256 string `json:"sha256"`
+}
+
+type snapshotRunDocument struct {
+	Id          string     `bson:"_id"`
+	StartedAt   time.Time  `bson:"startedAt"`
+	CompletedAt *time.Time `bson:"completedAt,omitempty"`
+	Error       string     `bson:"error,omitempty"`
+}
+
+func snapshotArchiveKey(id string, store string, part int, format string) string {
+	return fmt.Sprintf("%s/%s/%s.%04d.%s.gz", snapshotPrefix, id, store, part, format)
+}
+
+func snapshotManifestKey(id string) string {
+	return fmt.Sprintf("%s/%s/manifest.json", snapshotPrefix, id)
+}
+
+// SnapshotScheduler exports key value stores to a Nitric bucket on a schedule.
+//
+// Every runtime runs the schedule, the first to record a run in the "snapshots.runs" collection takes the snapshot.
+// Each store is read in a single pass, so writes made while a snapshot is taken may or may not be included.
+type SnapshotScheduler struct {
+	db       *mongo.Database
+	storage  storagepb.StorageServer
+	settings SnapshotSettings
+	schedule cron.Schedule
+	cron     *cron.Cron
+}
+
+func (s *SnapshotScheduler) getRunsCollectionHandle() *mongo.Collection {
+	return s.db.Collection("snapshots.runs")
+}
+
+// Write every document of a store to gzip compressed archive parts of at most snapshotPartSize uncompressed bytes
+func (s *SnapshotScheduler) archive(ctx context.Context, id string, store string) (*SnapshotManifestStore, error) {
+	cursor, err := s.db.Collection(store).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entry := &SnapshotManifestStore{
+		Store: store,
+		Parts: []*SnapshotManifestPart{},
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+
+	var written, count int64
+
+	// Write the current part to the bucket and start the next one
+	flush := func() error {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+
+		key := snapshotArchiveKey(id, store, len(entry.Parts), s.settings.Format)
+		sum := sha256.Sum256(buf.Bytes())
+
+		_, err := s.storage.Write(ctx, &storagepb.StorageWriteRequest{
+			BucketName: s.settings.Bucket,
+			Key:        key,
+			Body:       buf.Bytes(),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write archive %s: %w", key, err)
+		}
+
+		entry.Parts = append(entry.Parts, &SnapshotManifestPart{
+			Key:       key,
+			Documents: count,
+			Size:      int64(buf.Len()),
+			Sha256:    hex.EncodeToString(sum[:]),
+		})
+		entry.Documents += count
+
+		buf.Reset()
+		gz.Reset(&buf)
+		written, count = 0, 0
+
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		record := []byte(cursor.Current)
+		if s.settings.Format != SnapshotFormatBson {
+			// Canonical extended json keeps the bson types so documents restore unchanged
+			line, err := bson.MarshalExtJSON(cursor.Current, true, false)
+			if err != nil {
+				return nil, err
+			}
+
+			record = append(line, '\n')
+		}
+
+		if _, err := gz.Write(record); err != nil {
+			return nil, err
+		}
+
+		written += int64(len(record))
+		count++
+
+		if written >= snapshotPartSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// Empty stores still get a part, so every store in the manifest has an archive
+	if count > 0 || len(entry.Parts) == 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// Snapshot writes the archive parts of each store and then the manifest to the snapshot bucket
+func (s *SnapshotScheduler) Snapshot(ctx context.Context, id string) (*SnapshotManifest, error) {
+	manifest := &SnapshotManifest{
+		Id:        id,
+		CreatedAt: time.Now().UTC(),
+		Format:    s.settings.Format,
+		Stores:    []*SnapshotManifestStore{},
+	}
+
+	for _, store := range s.settings.Stores {
+		entry, err := s.archive(ctx, id, store)
+		if err != nil {
+			return nil, fmt.Errorf("unable to archive store %s: %w", store, err)
+		}
+
+		manifest.Stores = append(manifest.Stores, entry)
+	}
+
+	// The manifest is written last, so a snapshot without one is incomplete
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.storage.Write(ctx, &storagepb.StorageWriteRequest{
+		BucketName: s.settings.Bucket,
+		Key:        snapshotManifestKey(id),
+		Body:       body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to write manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Take the snapshot for the current schedule slot unless another runtime already has
+func (s *SnapshotScheduler) run() {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	id := now.Truncate(time.Minute).Format(snapshotIdFormat)
+
+	_, err := s.getRunsCollectionHandle().InsertOne(ctx, snapshotRunDocument{
+		Id:        id,
+		StartedAt: now,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return
+	} else if err != nil {
+		logger.Errorf("snapshot %s: unable to record run: %v", id, err)
+		return
+	}
+
+	manifest, err := s.Snapshot(ctx, id)
+
+	update := bson.D{{"completedAt", time.Now().UTC()}}
+	if err != nil {
+		logger.Errorf("snapshot %s: %v", id, err)
+		update = append(update, bson.E{"error", err.Error()})
+	} else {
+		logger.Infof("snapshot %s: wrote %d stores to bucket %s", id, len(manifest.Stores), s.settings.Bucket)
+	}
+
+	_, err = s.getRunsCollectionHandle().UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{{"$set", update}})
+	if err != nil {
+		logger.Errorf("snapshot %s: unable to record completion: %v", id, err)
+	}
+}
+
+// Start taking snapshots on the schedule
+func (s *SnapshotScheduler) Start() {
+	s.cron.Schedule(s.schedule, cron.FuncJob(s.run))
+	s.cron.Start()
+}
+
+// Stop the schedule, waiting for a snapshot in progress to finish
+func (s *SnapshotScheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// Stores are archived from the collections of the stack's database, so tenant databases and targets can't be snapshot
+func checkSnapshotStores(snapshot []string, stores map[string]StoreSettings, tenancy string) error {
+	if tenancy != TenancyModeNone {
+		return fmt.Errorf("snapshots can't be taken with tenancy mode %s", tenancy)
+	}
+
+	for _, store := range snapshot {
+		if target := stores[store].Target; target != "" {
+			return fmt.Errorf("store %s is routed to target %s, only stores in the stack's cluster can be snapshot", store, target)
+		}
+	}
+
+	return nil
+}
+
+func NewSnapshotScheduler(db *mongo.Database, storage storagepb.StorageServer) (*SnapshotScheduler, error) {
+	if db == nil {
+		return nil, fmt.Errorf("snapshots require the stack's cluster")
+	}
+
+	settings := SnapshotSettings{}
+	if err := json.Unmarshal([]byte(env.MONGO_SNAPSHOTS_CONFIG.String()), &settings); err != nil {
+		return nil, fmt.Errorf("unable to parse MONGO_SNAPSHOTS_CONFIG: %w", err)
+	}
+
+	if settings.Bucket == "" {
+		return nil, fmt.Errorf("a snapshot bucket is required")
+	}
+
+	if settings.Format == "" {
+		settings.Format = SnapshotFormatJsonl
+	}
+
+	if settings.Format != SnapshotFormatJsonl && settings.Format != SnapshotFormatBson {
+		return nil, fmt.Errorf("unknown snapshot format %s", settings.Format)
+	}
+
+	stores, err := storeSettingsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSnapshotStores(settings.Stores, stores, env.MONGO_TENANCY_MODE.String()); err != nil {
+		return nil, err
+	}
+
+	schedule, err := cron.ParseStandard(settings.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot schedule %q: %w", settings.Schedule, err)
+	}
+
+	return &SnapshotScheduler{
+		db:       db,
+		storage:  storage,
+		settings: settings,
+		schedule: schedule,
+		cron:     cron.New(),
+	}, nil
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestNewSnapshotSchedulerWithoutCluster(t *testing.T) {
+	if _, err := NewSnapshotScheduler(nil, nil); err == nil {
+		t.Fatal("expected snapshots without the stack's cluster to be rejected")
+	}
+}
+
+func TestCheckSnapshotStores(t *testing.T) {
+	stores := map[string]StoreSettings{
+		"orders": {},
+		"events": {Target: "analytics"},
+	}
+
+	if err := checkSnapshotStores([]string{"orders", "unconfigured"}, stores, TenancyModeNone); err != nil {
+		t.Fatalf("expected stores in the stack's cluster to be accepted, got %v", err)
+	}
+
+	if err := checkSnapshotStores([]string{"orders", "events"}, stores, TenancyModeNone); err == nil {
+		t.Fatal("expected a store routed to a target to be rejected")
+	}
+
+	if err := checkSnapshotStores([]string{"orders"}, stores, TenancyModeKey); err == nil {
+		t.Fatal("expected snapshots with tenancy to be rejected")
+	}
+}
@@ -0,0 +1,430 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	fasthttprouter "github.com/fasthttp/router"
+	"github.com/nitrictech/mongodb-provider/common/env"
+	"github.com/nitrictech/nitric/core/pkg/gateway"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	storagepb "github.com/nitrictech/nitric/core/pkg/proto/storage/v1"
+	"github.com/valyala/fasthttp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The gateway route presigned urls are served from
+const PresignRoute = "/x-nitric-storage/{token}"
+
+// MongoStorageServer serves Nitric buckets from GridFS, each bucket is stored in the "buckets.<name>" GridFS bucket.
+//
+// Presigned urls are signed tokens that are redeemed through the PresignRoute on the membrane's http gateway.
+type MongoStorageServer struct {
+	db *mongo.Database
+
+	signingKey []byte
+	presignUrl string
+	// Set once the presign route has been registered with a gateway
+	presignRouted atomic.Bool
+}
+
+var _ storagepb.StorageServer = &MongoStorageServer{}
+
+type presignClaims struct {
+	Bucket    string                                       `json:"b"`
+	Key       string                                       `json:"k"`
+	Operation storagepb.StoragePreSignUrlRequest_Operation `json:"o"`
+	Expiry    int64                                        `json:"e"`
+}
+
+func (s *MongoStorageServer) getBucketHandle(ctx context.Context, bucket string) (*gridfs.Bucket, error) {
+	b, err := gridfs.NewBucket(
+		s.db,
+		options.GridFSBucket().SetName(fmt.Sprintf("buckets.%s", bucket)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// GridFS doesn't take contexts for streaming operations, so carry over the request deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = b.SetReadDeadline(deadline)
+		_ = b.SetWriteDeadline(deadline)
+	}
+
+	return b, nil
+}
+
+// Find the ids of every revision stored for a key
+func (s *MongoStorageServer) revisions(ctx context.Context, bucket *gridfs.Bucket, key string) ([]primitive.ObjectID, error) {
+	cursor, err := bucket.FindContext(ctx, bson.D{{"filename", key}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var file struct {
+			Id primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, file.Id)
+	}
+
+	return ids, cursor.Err()
+}
+
+// Retrieve an item from a bucket
+func (s *MongoStorageServer) Read(ctx context.Context, req *storagepb.StorageReadRequest) (*storagepb.StorageReadResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoStorageServer.Read")
+
+	bucket, err := s.getBucketHandle(ctx, req.BucketName)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to open bucket %s", req.BucketName),
+			err,
+		)
+	}
+
+	var body bytes.Buffer
+	// Revision -1 (the default) is the most recently uploaded file with the name
+	_, err = bucket.DownloadToStreamByName(req.Key, &body)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("key %s not found in bucket %s", req.Key, req.BucketName),
+			err,
+		)
+	} else if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read %s from bucket %s", req.Key, req.BucketName),
+			err,
+		)
+	}
+
+	return &storagepb.StorageReadResponse{
+		Body: body.Bytes(),
+	}, nil
+}
+
+// Store an item to a bucket, replacing any existing item with the same key
+func (s *MongoStorageServer) Write(ctx context.Context, req *storagepb.StorageWriteRequest) (*storagepb.StorageWriteResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoStorageServer.Write")
+
+	bucket, err := s.getBucketHandle(ctx, req.BucketName)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to open bucket %s", req.BucketName),
+			err,
+		)
+	}
+
+	previous, err := s.revisions(ctx, bucket, req.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to find existing revisions of %s in bucket %s", req.Key, req.BucketName),
+			err,
+		)
+	}
+
+	_, err = bucket.UploadFromStream(req.Key, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to write %s to bucket %s", req.Key, req.BucketName),
+			err,
+		)
+	}
+
+	// The new revision is already visible to readers, so older revisions can be removed
+	for _, id := range previous {
+		if err := bucket.DeleteContext(ctx, id); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to remove previous revision of %s from bucket %s", req.Key, req.BucketName),
+				err,
+			)
+		}
+	}
+
+	return &storagepb.StorageWriteResponse{}, nil
+}
+
+// Delete an item from a bucket
+func (s *MongoStorageServer) Delete(ctx context.Context, req *storagepb.StorageDeleteRequest) (*storagepb.StorageDeleteResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoStorageServer.Delete")
+
+	bucket, err := s.getBucketHandle(ctx, req.BucketName)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to open bucket %s", req.BucketName),
+			err,
+		)
+	}
+
+	ids, err := s.revisions(ctx, bucket, req.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to find %s in bucket %s", req.Key, req.BucketName),
+			err,
+		)
+	}
+
+	for _, id := range ids {
+		if err := bucket.DeleteContext(ctx, id); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to delete %s from bucket %s", req.Key, req.BucketName),
+				err,
+			)
+		}
+	}
+
+	return &storagepb.StorageDeleteResponse{}, nil
+}
+
+// List the keys in a bucket that start with the requested prefix
+func (s *MongoStorageServer) ListBlobs(ctx context.Context, req *storagepb.StorageListBlobsRequest) (*storagepb.StorageListBlobsResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoStorageServer.ListBlobs")
+
+	bucket, err := s.getBucketHandle(ctx, req.BucketName)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to open bucket %s", req.BucketName),
+			err,
+		)
+	}
+
+	filter := bson.D{{"filename", bson.D{{"$regex", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(req.Prefix)}}}}}
+
+	keys, err := bucket.GetFilesCollection().Distinct(ctx, "filename", filter)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to list keys with prefix %s from bucket %s", req.Prefix, req.BucketName),
+			err,
+		)
+	}
+
+	blobs := make([]*storagepb.Blob, 0, len(keys))
+	for _, key := range keys {
+		if k, ok := key.(string); ok {
+			blobs = append(blobs, &storagepb.Blob{Key: k})
+		}
+	}
+
+	return &storagepb.StorageListBlobsResponse{
+		Blobs: blobs,
+	}, nil
+}
+
+// Determine if an item exists in a bucket
+func (s *MongoStorageServer) Exists(ctx context.Context, req *storagepb.StorageExistsRequest) (*storagepb.StorageExistsResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoStorageServer.Exists")
+
+	bucket, err := s.getBucketHandle(ctx, req.BucketName)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to open bucket %s", req.BucketName),
+			err,
+		)
+	}
+
+	count, err := bucket.GetFilesCollection().CountDocuments(ctx, bson.D{{"filename", req.Key}}, options.Count().SetLimit(1))
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to check for %s in bucket %s", req.Key, req.BucketName),
+			err,
+		)
+	}
+
+	return &storagepb.StorageExistsResponse{
+		Exists: count > 0,
+	}, nil
+}
+
+// Generate a url containing a signed token that can be redeemed through the gateway's presign route
+func (s *MongoStorageServer) PreSignUrl(ctx context.Context, req *storagepb.StoragePreSignUrlRequest) (*storagepb.StoragePreSignUrlResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoStorageServer.PreSignUrl")
+
+	if s.presignUrl == "" || !s.presignRouted.Load() {
+		return nil, newErr(
+			codes.Unimplemented,
+			"presigned urls require storage presign-url to be configured and a gateway that serves the presign route",
+			fmt.Errorf("presign route unavailable"),
+		)
+	}
+
+	expiry := time.Hour
+	if req.Expiry != nil {
+		expiry = req.Expiry.AsDuration()
+	}
+
+	token, err := s.signToken(presignClaims{
+		Bucket:    req.BucketName,
+		Key:       req.Key,
+		Operation: req.Operation,
+		Expiry:    time.Now().Add(expiry).Unix(),
+	})
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			"unable to sign presign token",
+			err,
+		)
+	}
+
+	path := strings.Replace(PresignRoute, "{token}", url.PathEscape(token), 1)
+
+	return &storagepb.StoragePreSignUrlResponse{
+		Url: strings.TrimSuffix(s.presignUrl, "/") + path,
+	}, nil
+}
+
+func (s *MongoStorageServer) signToken(claims presignClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (s *MongoStorageServer) verifyToken(token string) (*presignClaims, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token payload: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	claims := &presignClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	if time.Now().Unix() > claims.Expiry {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return claims, nil
+}
+
+// RegisterRoutes adds the presign route to an http gateway, it can be used as a RouteRegistrationHook
+func (s *MongoStorageServer) RegisterRoutes(r *fasthttprouter.Router, opts *gateway.GatewayStartOpts) {
+	r.GET(PresignRoute, s.presignHandler(storagepb.StoragePreSignUrlRequest_READ))
+	r.PUT(PresignRoute, s.presignHandler(storagepb.StoragePreSignUrlRequest_WRITE))
+
+	s.presignRouted.Store(true)
+}
+
+func (s *MongoStorageServer) presignHandler(operation storagepb.StoragePreSignUrlRequest_Operation) fasthttp.RequestHandler {
+	return func(rc *fasthttp.RequestCtx) {
+		token, _ := rc.UserValue("token").(string)
+
+		claims, err := s.verifyToken(token)
+		if err != nil {
+			rc.Error("invalid or expired token", fasthttp.StatusForbidden)
+			return
+		}
+
+		if claims.Operation != operation {
+			rc.Error("token does not permit this operation", fasthttp.StatusForbidden)
+			return
+		}
+
+		switch operation {
+		case storagepb.StoragePreSignUrlRequest_READ:
+			resp, err := s.Read(rc, &storagepb.StorageReadRequest{
+				BucketName: claims.Bucket,
+				Key:        claims.Key,
+			})
+			if status.Code(err) == codes.NotFound {
+				rc.Error("file not found", fasthttp.StatusNotFound)
+				return
+			} else if err != nil {
+				logger.Errorf("presigned read failed: %v", err)
+				rc.Error("unable to read file", fasthttp.StatusInternalServerError)
+				return
+			}
+
+			rc.SetBody(resp.Body)
+		case storagepb.StoragePreSignUrlRequest_WRITE:
+			_, err := s.Write(rc, &storagepb.StorageWriteRequest{
+				BucketName: claims.Bucket,
+				Key:        claims.Key,
+				Body:       rc.Request.Body(),
+			})
+			if err != nil {
+				logger.Errorf("presigned write failed: %v", err)
+				rc.Error("unable to write file", fasthttp.StatusInternalServerError)
+				return
+			}
+
+			rc.SetStatusCode(fasthttp.StatusOK)
+		}
+	}
+}
+
+func NewStorage(db *mongo.Database) (*MongoStorageServer, error) {
+	presignUrl := env.MONGO_STORAGE_PRESIGN_URL.String()
+	signingKey := env.MONGO_STORAGE_SIGNING_KEY.String()
+
+	if presignUrl != "" && signingKey == "" {
+		return nil, fmt.Errorf("MONGO_STORAGE_SIGNING_KEY is required when MONGO_STORAGE_PRESIGN_URL is set")
+	}
+
+	return &MongoStorageServer{
+		db:         db,
+		signingKey: []byte(signingKey),
+		presignUrl: presignUrl,
+	}, nil
+}
@@ -0,0 +1,157 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	storagepb "github.com/nitrictech/nitric/core/pkg/proto/storage/v1"
+	"github.com/valyala/fasthttp"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStorageTokens(t *testing.T) {
+	storage := &MongoStorageServer{signingKey: []byte("key")}
+	other := &MongoStorageServer{signingKey: []byte("other")}
+
+	claims := presignClaims{
+		Bucket:    "images",
+		Key:       "a/b.png",
+		Operation: storagepb.StoragePreSignUrlRequest_WRITE,
+		Expiry:    time.Now().Add(time.Minute).Unix(),
+	}
+
+	token, err := storage.signToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verified, err := storage.verifyToken(token)
+	if err != nil {
+		t.Fatalf("expected the token to verify, got %v", err)
+	}
+
+	if *verified != claims {
+		t.Fatalf("expected claims %+v, got %+v", claims, *verified)
+	}
+
+	if _, err := other.verifyToken(token); err == nil {
+		t.Fatal("expected a token signed with another key to be rejected")
+	}
+
+	payload, signature, _ := strings.Cut(token, ".")
+	if _, err := storage.verifyToken(payload + "x." + signature); err == nil {
+		t.Fatal("expected a tampered token to be rejected")
+	}
+
+	if _, err := storage.verifyToken(payload); err == nil {
+		t.Fatal("expected a token without a signature to be rejected")
+	}
+
+	claims.Expiry = time.Now().Add(-time.Minute).Unix()
+	expired, err := storage.signToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := storage.verifyToken(expired); err == nil {
+		t.Fatal("expected an expired token to be rejected")
+	}
+}
+
+func TestStoragePreSignUrl(t *testing.T) {
+	storage := &MongoStorageServer{signingKey: []byte("key"), presignUrl: "https://api.example.com/"}
+	req := &storagepb.StoragePreSignUrlRequest{BucketName: "images", Key: "a.png", Operation: storagepb.StoragePreSignUrlRequest_READ}
+
+	// Urls can't be redeemed until a gateway serves the route
+	if _, err := storage.PreSignUrl(context.Background(), req); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented without the presign route, got %v", err)
+	}
+
+	storage.presignRouted.Store(true)
+
+	res, err := storage.PreSignUrl(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, ok := strings.CutPrefix(res.Url, "https://api.example.com/x-nitric-storage/")
+	if !ok {
+		t.Fatalf("unexpected url %s", res.Url)
+	}
+
+	// The token only permits the operation it was signed for
+	rc := &fasthttp.RequestCtx{}
+	rc.SetUserValue("token", token)
+	storage.presignHandler(storagepb.StoragePreSignUrlRequest_WRITE)(rc)
+
+	if rc.Response.StatusCode() != fasthttp.StatusForbidden {
+		t.Fatalf("expected a write with a read token to be forbidden, got %d", rc.Response.StatusCode())
+	}
+}
+
+func TestStorageReadWrite(t *testing.T) {
+	storage := &MongoStorageServer{db: testDatabase(t)}
+	ctx := context.Background()
+
+	for _, write := range []struct{ key, body string }{{"a/1", "first"}, {"a/1", "second"}, {"a/2", "other"}, {"b/1", "other"}} {
+		if _, err := storage.Write(ctx, &storagepb.StorageWriteRequest{BucketName: "files", Key: write.key, Body: []byte(write.body)}); err != nil {
+			t.Fatalf("unable to write %s: %v", write.key, err)
+		}
+	}
+
+	read, err := storage.Read(ctx, &storagepb.StorageReadRequest{BucketName: "files", Key: "a/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(read.Body) != "second" {
+		t.Fatalf("expected the latest write, got %q", read.Body)
+	}
+
+	// Overwrites don't leave earlier revisions behind
+	revisions, err := storage.db.Collection("buckets.files.files").CountDocuments(ctx, bson.D{{"filename", "a/1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if revisions != 1 {
+		t.Fatalf("expected one revision of a/1, got %d", revisions)
+	}
+
+	list, err := storage.ListBlobs(ctx, &storagepb.StorageListBlobsRequest{BucketName: "files", Prefix: "a/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{}
+	for _, blob := range list.Blobs {
+		keys = append(keys, blob.Key)
+	}
+	sort.Strings(keys)
+
+	if strings.Join(keys, ",") != "a/1,a/2" {
+		t.Fatalf("expected keys a/1 and a/2, got %v", keys)
+	}
+
+	if _, err := storage.Delete(ctx, &storagepb.StorageDeleteRequest{BucketName: "files", Key: "a/1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	exists, err := storage.Exists(ctx, &storagepb.StorageExistsRequest{BucketName: "files", Key: "a/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists.Exists {
+		t.Fatal("expected a/1 to be deleted")
+	}
+
+	if _, err := storage.Read(ctx, &storagepb.StorageReadRequest{BucketName: "files", Key: "a/1"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected reading a deleted key to be NotFound, got %v", err)
+	}
+}
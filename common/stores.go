@@ -0,0 +1,159 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nitrictech/mongodb-provider/common/env"
+)
+
+// StoreSettings configure a single key value store, they are decoded from MONGO_STORES_CONFIG
+type StoreSettings struct {
+	// Serve reads of the store from the in-process cache
+	Cache bool `json:"cache,omitempty"`
+	// Map the ObjectId, UUID and numeric _ids of documents written by other clients to keys, and their values to plain content
+	Interop bool `json:"interop,omitempty"`
+	// Name of the target the store is kept in, the cluster's nitric database when empty
+	Target string `json:"target,omitempty"`
+	// Limits on the use of the store, it is unlimited when nil
+	Quota *QuotaSettings `json:"quota,omitempty"`
+	// Compress large values, zstd or empty to store values uncompressed
+	Compression string `json:"compression,omitempty"`
+	// Values with at least this many bytes of bson are compressed, defaults to 4096
+	CompressionThreshold int `json:"compression-threshold,omitempty"`
+	// Secondary indexes of the store's values, created when the runtime starts
+	Indexes []IndexSettings `json:"indexes,omitempty"`
+	// Vectors of the store's values by name, indexed for vector search
+	Vectors map[string]VectorSettings `json:"vectors,omitempty"`
+	// Text fields of the store's values indexed for full-text search, search is disabled when nil
+	Search *SearchSettings `json:"search,omitempty"`
+	// Dot separated paths of the GeoJSON fields of the store's values, each is indexed with a 2dsphere index
+	Geo []string `json:"geo,omitempty"`
+	// Keep the store's measurements in a time-series collection, the store can't hold values when set
+	TimeSeries *TimeSeriesSettings `json:"timeseries,omitempty"`
+	// Keep the store's values in a capped collection, which drops the oldest values when it is full
+	Capped *CappedSettings `json:"capped,omitempty"`
+}
+
+// Check the settings of a store can be served with the rest of a config
+func (s StoreSettings) validate(config *Config) error {
+	if s.Target != "" {
+		if _, ok := config.Targets[s.Target]; !ok {
+			return fmt.Errorf("routed to unknown target %s", s.Target)
+		}
+	}
+
+	// Cached values are shared by every caller of the store
+	if s.Cache && config.Tenancy != TenancyModeNone {
+		return fmt.Errorf("can't be cached with tenancy enabled")
+	}
+
+	if s.Quota != nil {
+		if err := s.Quota.validate(); err != nil {
+			return fmt.Errorf("quota is invalid: %w", err)
+		}
+	}
+
+	switch s.Compression {
+	case "", CompressionZstd:
+	default:
+		return fmt.Errorf("unknown compression %s", s.Compression)
+	}
+
+	if s.CompressionThreshold < 0 {
+		return fmt.Errorf("compression threshold must not be negative")
+	}
+
+	// Compressed values are stored as binary, so their fields can't be indexed or matched
+	if s.Compression != "" && (len(s.Indexes) > 0 || len(s.Geo) > 0 || len(s.Vectors) > 0 || s.Search != nil) {
+		return fmt.Errorf("compressed stores can't have indexes, geo fields, vectors or search")
+	}
+
+	for _, index := range s.Indexes {
+		if len(index.Keys) == 0 {
+			return fmt.Errorf("index %s has no keys", index.Name)
+		}
+
+		for _, key := range index.Keys {
+			if err := validateFieldPath(key.Field); err != nil {
+				return fmt.Errorf("index %s is invalid: %w", index.name(), err)
+			}
+		}
+	}
+
+	for _, field := range s.Geo {
+		if err := validateFieldPath(field); err != nil {
+			return fmt.Errorf("has invalid geo field: %w", err)
+		}
+	}
+
+	for vector, vectorSettings := range s.Vectors {
+		if err := validatePathSegment(vector); err != nil {
+			return fmt.Errorf("has invalid vector name: %w", err)
+		}
+
+		if err := vectorSettings.validate(); err != nil {
+			return fmt.Errorf("vector %s is invalid: %w", vector, err)
+		}
+	}
+
+	if s.Search != nil {
+		if err := s.Search.validate(); err != nil {
+			return fmt.Errorf("search is invalid: %w", err)
+		}
+	}
+
+	if s.TimeSeries != nil {
+		err := s.TimeSeries.validate()
+		// Time-series collections only hold measurements, so the features of values don't apply to them
+		if err == nil && (s.Cache || s.Compression != "" || len(s.Vectors) > 0 || s.Search != nil) {
+			err = fmt.Errorf("time-series stores can't be cached, compressed or searched")
+		}
+
+		if err != nil {
+			return fmt.Errorf("time-series is invalid: %w", err)
+		}
+	}
+
+	if s.Capped != nil {
+		err := s.Capped.validate()
+		if err == nil && s.TimeSeries != nil {
+			err = fmt.Errorf("capped stores can't be time-series stores")
+		}
+
+		if err != nil {
+			return fmt.Errorf("capped is invalid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TargetSettings configure a cluster and database stores can be routed to, they are decoded from MONGO_TARGETS_CONFIG
+type TargetSettings struct {
+	ConnectionString string `json:"connectionString"`
+	// Defaults to nitric
+	Database string `json:"database,omitempty"`
+	// TLS settings of the target's cluster, the connection string decides when nil
+	TLS *TLSSettings `json:"tls,omitempty"`
+}
+
+// Settings per store name, stores that aren't listed use the zero settings
+func storeSettingsFromEnv() (map[string]StoreSettings, error) {
+	settings := map[string]StoreSettings{}
+	if err := json.Unmarshal([]byte(env.MONGO_STORES_CONFIG.String()), &settings); err != nil {
+		return nil, fmt.Errorf("unable to parse MONGO_STORES_CONFIG: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Settings per target name
+func targetSettingsFromEnv() (map[string]TargetSettings, error) {
+	settings := map[string]TargetSettings{}
+	if err := json.Unmarshal([]byte(env.MONGO_TARGETS_CONFIG.String()), &settings); err != nil {
+		return nil, fmt.Errorf("unable to parse MONGO_TARGETS_CONFIG: %w", err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Tenancy is disabled, every caller shares the stores
+	TenancyModeNone = ""
+	// The tenant is read from the x-nitric-tenant grpc metadata of each request
+	TenancyModeMetadata = "metadata"
+	// The tenant is the first segment of each key, e.g. acme/orders/1
+	TenancyModeKey = "key"
+
+	// Metadata key of the tenant in metadata tenancy mode
+	TenantMetadataKey = "x-nitric-tenant"
+	// Separates the tenant from the rest of the key in key tenancy mode
+	tenantKeySeparator = "/"
+)
+
+// Tenant ids are used in database names, so they are restricted to characters mongo allows in them.
+// They are lowercase, because mongo rejects databases whose names only differ from an existing one by case.
+var tenantIdPattern = regexp.MustCompile(`^[a-z0-9_-]{1,40}$`)
+
+// The longest tenant id tenantIdPattern allows
+const maxTenantLength = 40
+
+func validateTenant(tenant string) error {
+	if !tenantIdPattern.MatchString(tenant) {
+		return fmt.Errorf("invalid tenant %q, tenants must be 1 to 40 lowercase letters, digits, underscores or hyphens", tenant)
+	}
+
+	return nil
+}
+
+// ValidateTenantDatabase checks that the tenant databases of a database fit mongo's limit on database names, whatever the tenant
+func ValidateTenantDatabase(database string) error {
+	maxLength := maxDatabaseLength - len(tenantDatabaseName("", ""))
+	if len(database) > maxLength-maxTenantLength {
+		return fmt.Errorf("database %q is too long for tenancy, databases can be at most %d characters so their tenant databases fit in %d", database, maxLength-maxTenantLength, maxDatabaseLength)
+	}
+
+	return nil
+}
+
+// Each tenant's stores are kept in its own database alongside the shared one
+func tenantDatabaseName(database string, tenant string) string {
+	return fmt.Sprintf("%s-tenant-%s", database, tenant)
+}
+
+// Split the tenant from a key or key prefix, returning the tenant and the key within its stores
+func (k *MongoDBServer) tenantOf(ctx context.Context, key string) (string, string, error) {
+	switch k.tenancy {
+	case TenancyModeMetadata:
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		tenants := md.Get(TenantMetadataKey)
+		if len(tenants) != 1 {
+			return "", "", fmt.Errorf("requests must include exactly one %s metadata value", TenantMetadataKey)
+		}
+
+		if err := validateTenant(tenants[0]); err != nil {
+			return "", "", err
+		}
+
+		return tenants[0], key, nil
+	case TenancyModeKey:
+		tenant, rest, ok := strings.Cut(key, tenantKeySeparator)
+		if !ok {
+			return "", "", fmt.Errorf("keys must start with the tenant followed by %q", tenantKeySeparator)
+		}
+
+		if err := validateTenant(tenant); err != nil {
+			return "", "", err
+		}
+
+		return tenant, rest, nil
+	default:
+		return "", key, nil
+	}
+}
+
+// The collection of a store that a key is kept in and the key within it.
+//
+// With tenancy enabled the collection is in the tenant's database, so requests can't reach the values of other tenants.
+func (k *MongoDBServer) scopedCollection(ctx context.Context, store string, key string) (*mongo.Collection, string, error) {
+	tenant, key, err := k.tenantOf(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if tenant == "" {
+		return k.getCollectionHandle(store), key, nil
+	}
+
+	return k.tenantDatabase(store, tenant).Collection(store), key, nil
+}
+
+// The database a tenant's values of a store are kept in, on the store's target
+func (k *MongoDBServer) tenantDatabase(store string, tenant string) *mongo.Database {
+	db := k.getDatabaseHandle(store)
+
+	return db.Client().Database(tenantDatabaseName(db.Name(), tenant))
+}
+
+// The databases holding a tenant's stores, one for the cluster and one for each distinct target database
+func (k *MongoDBServer) tenantDatabases(tenant string) []*mongo.Database {
+	type databaseRef struct {
+		client *mongo.Client
+		name   string
+	}
+
+	databases := []*mongo.Database{}
+	seen := map[databaseRef]bool{}
+
+	shared := []*mongo.Database{k.client.Database(k.database)}
+	for _, db := range k.storeDatabases {
+		shared = append(shared, db)
+	}
+
+	for _, db := range shared {
+		ref := databaseRef{client: db.Client(), name: db.Name()}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		databases = append(databases, db.Client().Database(tenantDatabaseName(db.Name(), tenant)))
+	}
+
+	return databases
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTenant(t *testing.T) {
+	tests := []struct {
+		tenant string
+		valid  bool
+	}{
+		{tenant: "acme", valid: true},
+		{tenant: "acme_corp-2", valid: true},
+		{tenant: strings.Repeat("a", maxTenantLength), valid: true},
+		{tenant: ""},
+		{tenant: strings.Repeat("a", maxTenantLength+1)},
+		// Mongo rejects databases whose names only differ by case, so Acme and acme can't both have one
+		{tenant: "Acme"},
+		{tenant: "acme.corp"},
+		{tenant: "acme/orders"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tenant, func(t *testing.T) {
+			err := validateTenant(test.tenant)
+			if test.valid && err != nil {
+				t.Fatalf("expected %q to be valid, got %v", test.tenant, err)
+			}
+
+			if !test.valid && err == nil {
+				t.Fatalf("expected %q to be rejected", test.tenant)
+			}
+		})
+	}
+}
+
+func TestValidateTenantDatabase(t *testing.T) {
+	longest := maxDatabaseLength - len("-tenant-") - maxTenantLength
+
+	if err := ValidateTenantDatabase(strings.Repeat("d", longest)); err != nil {
+		t.Fatalf("expected a %d character database to fit, got %v", longest, err)
+	}
+
+	if name := tenantDatabaseName(strings.Repeat("d", longest), strings.Repeat("t", maxTenantLength)); len(name) > maxDatabaseLength {
+		t.Fatalf("expected the longest tenant database to fit in %d characters, got %d", maxDatabaseLength, len(name))
+	}
+
+	if err := ValidateTenantDatabase(strings.Repeat("d", longest+1)); err == nil {
+		t.Fatal("expected a database whose tenant databases don't fit to be rejected")
+	}
+}
@@ -0,0 +1,162 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	tenantspb "github.com/nitrictech/mongodb-provider/common/proto/tenants/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+)
+
+// MongoTenantsServer exports and deletes the values a tenant has in the stores served by MongoDBServer.
+//
+// It isn't scoped to a tenant itself, so it should only be reachable by administrators.
+type MongoTenantsServer struct {
+	kv *MongoDBServer
+}
+
+var _ tenantspb.TenantsServer = &MongoTenantsServer{}
+
+// The stores a tenant has values in, by name
+func (t *MongoTenantsServer) tenantStores(ctx context.Context, tenant string) (map[string]*mongo.Collection, error) {
+	stores := map[string]*mongo.Collection{}
+
+	for _, db := range t.kv.tenantDatabases(tenant) {
+		names, err := db.ListCollectionNames(ctx, bson.D{{"name", bson.D{{"$not", bson.D{{"$regex", "^system\\."}}}}}})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			// A store is only read from the database of the target it is routed to
+			routed := t.kv.tenantDatabase(name, tenant)
+			if routed.Client() != db.Client() || routed.Name() != db.Name() {
+				continue
+			}
+
+			stores[name] = db.Collection(name)
+		}
+	}
+
+	return stores, nil
+}
+
+// Stream every value a tenant has in the key value stores
+func (t *MongoTenantsServer) Export(req *tenantspb.TenantsExportRequest, stream tenantspb.Tenants_ExportServer) error {
+	newErr := grpc_errors.ErrorsWithScope("MongoTenantsServer.Export")
+
+	if err := validateTenant(req.Tenant); err != nil {
+		return newErr(
+			codes.InvalidArgument,
+			"invalid tenant",
+			err,
+		)
+	}
+
+	stores, err := t.tenantStores(stream.Context(), req.Tenant)
+	if err != nil {
+		return newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to list the stores of tenant %s", req.Tenant),
+			err,
+		)
+	}
+
+	names := req.Stores
+	if len(names) == 0 {
+		for name := range stores {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		coll, ok := stores[name]
+		if !ok {
+			continue
+		}
+
+		if err := t.exportStore(stream, name, coll); err != nil {
+			return newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to export store %s of tenant %s", name, req.Tenant),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (t *MongoTenantsServer) exportStore(stream tenantspb.Tenants_ExportServer, store string, coll *mongo.Collection) error {
+	ctx := stream.Context()
+
+	cursor, err := coll.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		content, err := contentFromDocument(cursor.Current)
+		if err != nil {
+			return err
+		}
+
+		err = stream.Send(&tenantspb.TenantsExportResponse{
+			Store:   store,
+			Key:     keyString(cursor.Current.Lookup("_id")),
+			Content: content,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// Delete every value a tenant has in the key value stores by dropping its databases
+func (t *MongoTenantsServer) Delete(ctx context.Context, req *tenantspb.TenantsDeleteRequest) (*tenantspb.TenantsDeleteResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoTenantsServer.Delete")
+
+	if err := validateTenant(req.Tenant); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid tenant",
+			err,
+		)
+	}
+
+	stores, err := t.tenantStores(ctx, req.Tenant)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to list the stores of tenant %s", req.Tenant),
+			err,
+		)
+	}
+
+	for _, db := range t.kv.tenantDatabases(req.Tenant) {
+		if err := db.Drop(ctx); err != nil {
+			return nil, newErr(
+				codes.Internal,
+				fmt.Sprintf("unable to delete database %s of tenant %s", db.Name(), req.Tenant),
+				err,
+			)
+		}
+	}
+
+	return &tenantspb.TenantsDeleteResponse{
+		Stores: int64(len(stores)),
+	}, nil
+}
+
+func NewTenants(kv *MongoDBServer) *MongoTenantsServer {
+	return &MongoTenantsServer{
+		kv: kv,
+	}
+}
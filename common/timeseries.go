@@ -0,0 +1,571 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	timeseriespb "github.com/nitrictech/mongodb-provider/common/proto/timeseries/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	TimeSeriesGranularitySeconds = "seconds"
+	TimeSeriesGranularityMinutes = "minutes"
+	TimeSeriesGranularityHours   = "hours"
+
+	defaultTimeSeriesTimeField = "time"
+	defaultTimeSeriesMetaField = "meta"
+
+	maxTimeSeriesAppend    = 10000
+	defaultTimeSeriesLimit = 1000
+	maxTimeSeriesLimit     = 10000
+	maxTimeSeriesBuckets   = 10000
+)
+
+// TimeSeriesSettings declare a key value store as a time-series store, kept in a mongo time-series collection
+type TimeSeriesSettings struct {
+	// Field of the measurement times, defaults to time
+	TimeField string `json:"time-field,omitempty"`
+	// Field of the measurement sources, defaults to meta
+	MetaField string `json:"meta-field,omitempty"`
+	// seconds, minutes or hours, the typical interval between measurements of a source, defaults to seconds
+	Granularity string `json:"granularity,omitempty"`
+	// Measurements are removed this many seconds after their time, they are kept when zero
+	ExpireAfterSeconds int64 `json:"expire-after-seconds,omitempty"`
+}
+
+func (t TimeSeriesSettings) timeField() string {
+	if t.TimeField == "" {
+		return defaultTimeSeriesTimeField
+	}
+
+	return t.TimeField
+}
+
+func (t TimeSeriesSettings) metaField() string {
+	if t.MetaField == "" {
+		return defaultTimeSeriesMetaField
+	}
+
+	return t.MetaField
+}
+
+func (t TimeSeriesSettings) granularity() string {
+	if t.Granularity == "" {
+		return TimeSeriesGranularitySeconds
+	}
+
+	return t.Granularity
+}
+
+func (t TimeSeriesSettings) validate() error {
+	for _, field := range []string{t.timeField(), t.metaField()} {
+		if err := validatePathSegment(field); err != nil {
+			return err
+		}
+
+		if isReservedField(field) {
+			return fmt.Errorf("field %s is reserved", field)
+		}
+	}
+
+	if t.timeField() == t.metaField() {
+		return fmt.Errorf("the time and meta fields must differ")
+	}
+
+	switch t.granularity() {
+	case TimeSeriesGranularitySeconds, TimeSeriesGranularityMinutes, TimeSeriesGranularityHours:
+	default:
+		return fmt.Errorf("unknown granularity %s", t.Granularity)
+	}
+
+	if t.ExpireAfterSeconds < 0 {
+		return fmt.Errorf("expire-after-seconds must not be negative")
+	}
+
+	return nil
+}
+
+// Create a time-series collection, or check the existing collection is one and update its expiry
+func ensureTimeSeries(ctx context.Context, coll *mongo.Collection, settings TimeSeriesSettings) error {
+	ns := coll.Database().Name() + "." + coll.Name()
+
+	opts := options.CreateCollection().SetTimeSeriesOptions(options.TimeSeries().
+		SetTimeField(settings.timeField()).
+		SetMetaField(settings.metaField()).
+		SetGranularity(settings.granularity()))
+	if settings.ExpireAfterSeconds > 0 {
+		opts.SetExpireAfterSeconds(settings.ExpireAfterSeconds)
+	}
+
+	spec, err := createCollection(ctx, coll, opts)
+	if err != nil || spec == nil {
+		return err
+	}
+
+	if spec.Type != "timeseries" {
+		return fmt.Errorf("%s exists and isn't a time-series collection", ns)
+	}
+
+	if timeField, _ := spec.Options.Lookup("timeseries", "timeField").StringValueOK(); timeField != settings.timeField() {
+		return fmt.Errorf("%s has time field %s, not %s", ns, timeField, settings.timeField())
+	}
+
+	if metaField, _ := spec.Options.Lookup("timeseries", "metaField").StringValueOK(); metaField != settings.metaField() {
+		return fmt.Errorf("%s has meta field %s, not %s", ns, metaField, settings.metaField())
+	}
+
+	if granularity, _ := spec.Options.Lookup("timeseries", "granularity").StringValueOK(); granularity != settings.granularity() {
+		logger.Warnf("time-series %s has granularity %s, not %s, it is left unchanged", ns, granularity, settings.granularity())
+	}
+
+	// Only the expiry of an existing collection can be changed freely
+	expireAfterSeconds, _ := spec.Options.Lookup("expireAfterSeconds").AsInt64OK()
+	if expireAfterSeconds != settings.ExpireAfterSeconds {
+		var expiry interface{} = settings.ExpireAfterSeconds
+		if settings.ExpireAfterSeconds == 0 {
+			expiry = "off"
+		}
+
+		err := coll.Database().RunCommand(ctx, bson.D{{"collMod", coll.Name()}, {"expireAfterSeconds", expiry}}).Err()
+		if err != nil {
+			return fmt.Errorf("unable to update the expiry of %s: %w", ns, err)
+		}
+
+		logger.Infof("time-series %s: expiry updated", ns)
+	}
+
+	return nil
+}
+
+// Time-series stores hold measurements rather than values, so they can't be used through the key value store
+func (k *MongoDBServer) timeSeriesErr(newErr grpc_errors.ScopedErrorFactory, store string) error {
+	if _, ok := k.collections.timeSeriesOf(store); !ok {
+		return nil
+	}
+
+	return newErr(
+		codes.FailedPrecondition,
+		fmt.Sprintf("store %s is a time-series store, use the time-series service", store),
+		fmt.Errorf("time-series store"),
+	)
+}
+
+// MongoTimeSeriesServer writes and reads the measurements of the time-series stores of MongoDBServer
+type MongoTimeSeriesServer struct {
+	kv *MongoDBServer
+}
+
+var _ timeseriespb.TimeSeriesServer = &MongoTimeSeriesServer{}
+
+// The settings and collection of a time-series store, the tenant is given as a key prefix would be in key tenancy mode
+func (t *MongoTimeSeriesServer) scope(ctx context.Context, newErr grpc_errors.ScopedErrorFactory, store string, tenant string) (TimeSeriesSettings, *mongo.Collection, error) {
+	settings, ok := t.kv.collections.timeSeriesOf(store)
+	if !ok {
+		return settings, nil, newErr(
+			codes.FailedPrecondition,
+			"time-series store not declared",
+			fmt.Errorf("store %s isn't a time-series store", store),
+		)
+	}
+
+	tenantPrefix := ""
+	if t.kv.tenancy == TenancyModeKey {
+		tenantPrefix = tenant + tenantKeySeparator
+	}
+
+	coll, _, err := t.kv.scopedCollection(ctx, store, tenantPrefix)
+	if err != nil {
+		return settings, nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := t.kv.quotas.allow(ctx, store); err != nil {
+		return settings, nil, quotaErr(newErr, err)
+	}
+
+	return settings, coll, nil
+}
+
+// Conditions on the time and meta of measurements
+func timeSeriesConditions(settings TimeSeriesSettings, start *timestamppb.Timestamp, end *timestamppb.Timestamp, meta *structpb.Struct) (bson.D, error) {
+	window := bson.D{}
+	if start != nil {
+		window = append(window, bson.E{"$gte", start.AsTime()})
+	}
+	if end != nil {
+		window = append(window, bson.E{"$lt", end.AsTime()})
+	}
+
+	conditions := bson.D{}
+	if len(window) > 0 {
+		conditions = append(conditions, bson.E{settings.timeField(), window})
+	}
+
+	for field, value := range meta.AsMap() {
+		if err := validatePathSegment(field); err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, bson.E{settings.metaField() + "." + field, bson.D{{"$eq", value}}})
+	}
+
+	return conditions, nil
+}
+
+// Add measurements to a store
+func (t *MongoTimeSeriesServer) Append(ctx context.Context, req *timeseriespb.TimeSeriesAppendRequest) (*timeseriespb.TimeSeriesAppendResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoTimeSeriesServer.Append")
+
+	if len(req.Measurements) == 0 || len(req.Measurements) > maxTimeSeriesAppend {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("between 1 and %d measurements can be appended at a time", maxTimeSeriesAppend),
+			fmt.Errorf("%d measurements", len(req.Measurements)),
+		)
+	}
+
+	settings, coll, err := t.scope(ctx, newErr, req.Store, req.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]interface{}, 0, len(req.Measurements))
+	for i, measurement := range req.Measurements {
+		if measurement.Time == nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid measurement",
+				fmt.Errorf("measurement %d has no time", i),
+			)
+		}
+
+		doc := bson.D{{settings.timeField(), measurement.Time.AsTime()}}
+		if measurement.Meta != nil {
+			doc = append(doc, bson.E{settings.metaField(), measurement.Meta.AsMap()})
+		}
+
+		for field, value := range measurement.Values.AsMap() {
+			err := validatePathSegment(field)
+			if err == nil && (field == settings.timeField() || field == settings.metaField() || isReservedField(field)) {
+				err = fmt.Errorf("field %s is reserved", field)
+			}
+
+			if err != nil {
+				return nil, newErr(
+					codes.InvalidArgument,
+					"invalid measurement",
+					fmt.Errorf("measurement %d: %w", i, err),
+				)
+			}
+
+			doc = append(doc, bson.E{field, value})
+		}
+
+		documents = append(documents, doc)
+	}
+
+	if err := t.kv.collections.ensure(ctx, req.Store, coll); err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			fmt.Sprintf("unable to create time-series store %s", req.Store),
+			err,
+		)
+	}
+
+	if t.kv.tenancy != TenancyModeNone {
+		t.kv.indexes.ensureTenant(req.Store, coll)
+	}
+
+	if _, err := coll.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false)); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to append measurements to %s store", req.Store),
+			err,
+		)
+	}
+
+	return &timeseriespb.TimeSeriesAppendResponse{}, nil
+}
+
+// Read the measurements of a time window, oldest first
+func (t *MongoTimeSeriesServer) Range(ctx context.Context, req *timeseriespb.TimeSeriesRangeRequest) (*timeseriespb.TimeSeriesRangeResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoTimeSeriesServer.Range")
+
+	limit := int64(req.Limit)
+	if limit == 0 {
+		limit = defaultTimeSeriesLimit
+	}
+
+	if limit < 0 || limit > maxTimeSeriesLimit {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("limit must be between 1 and %d", maxTimeSeriesLimit),
+			fmt.Errorf("limit %d", req.Limit),
+		)
+	}
+
+	settings, coll, err := t.scope(ctx, newErr, req.Store, req.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := timeSeriesConditions(settings, req.Start, req.End, req.Meta)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid meta",
+			err,
+		)
+	}
+
+	direction := 1
+	if req.Descending {
+		direction = -1
+	}
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{settings.timeField(), direction}}).SetLimit(limit))
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read store %s", req.Store),
+			err,
+		)
+	}
+
+	var docs []bson.D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to read store %s", req.Store),
+			err,
+		)
+	}
+
+	res := &timeseriespb.TimeSeriesRangeResponse{
+		Measurements: []*timeseriespb.Measurement{},
+	}
+
+	for _, doc := range docs {
+		measurement, err := measurementFromDocument(settings, doc)
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"unable to convert measurement to pb",
+				err,
+			)
+		}
+
+		res.Measurements = append(res.Measurements, measurement)
+	}
+
+	return res, nil
+}
+
+// Convert fields to a pb struct the same way values are
+func structFromFields(fields bson.D) (*structpb.Struct, error) {
+	b, err := bson.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return contentFromDocument(b)
+}
+
+func measurementFromDocument(settings TimeSeriesSettings, doc bson.D) (*timeseriespb.Measurement, error) {
+	measurement := &timeseriespb.Measurement{}
+	values := bson.D{}
+
+	for _, field := range doc {
+		switch field.Key {
+		case settings.timeField():
+			at, ok := field.Value.(primitive.DateTime)
+			if !ok {
+				return nil, fmt.Errorf("time is %T, not a date", field.Value)
+			}
+
+			measurement.Time = timestamppb.New(at.Time())
+		case settings.metaField():
+			// Meta that isn't a document is returned as a value field
+			meta, ok := field.Value.(bson.D)
+			if !ok {
+				values = append(values, field)
+				continue
+			}
+
+			s, err := structFromFields(meta)
+			if err != nil {
+				return nil, err
+			}
+
+			measurement.Meta = s
+		default:
+			values = append(values, field)
+		}
+	}
+
+	s, err := structFromFields(values)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement.Values = s
+
+	return measurement, nil
+}
+
+// A numeric aggregation result as a float, zero when there were no numeric values
+func statValue(v interface{}) float64 {
+	switch n := v.(type) {
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	default:
+		return 0
+	}
+}
+
+// Summarise the measurements of a time window in buckets of equal length
+func (t *MongoTimeSeriesServer) Aggregate(ctx context.Context, req *timeseriespb.TimeSeriesAggregateRequest) (*timeseriespb.TimeSeriesAggregateResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoTimeSeriesServer.Aggregate")
+
+	if req.Start == nil || req.End == nil || req.Bucket == nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"start, end and bucket are required",
+			fmt.Errorf("window or bucket not set"),
+		)
+	}
+
+	window := req.End.AsTime().Sub(req.Start.AsTime())
+	bucket := req.Bucket.AsDuration()
+
+	if bucket < time.Millisecond || window <= 0 || window/bucket > maxTimeSeriesBuckets {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("end must be after start and the window can have at most %d buckets of at least a millisecond", maxTimeSeriesBuckets),
+			fmt.Errorf("window %s, bucket %s", window, bucket),
+		)
+	}
+
+	for _, field := range req.Fields {
+		if err := validateFieldPath(field); err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid field",
+				err,
+			)
+		}
+	}
+
+	settings, coll, err := t.scope(ctx, newErr, req.Store, req.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := timeSeriesConditions(settings, req.Start, req.End, req.Meta)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid meta",
+			err,
+		)
+	}
+
+	// Buckets start at whole multiples of their length since the epoch
+	millis := bson.D{{"$toLong", "$" + settings.timeField()}}
+	group := bson.D{
+		{"_id", bson.D{{"$toDate", bson.D{{"$subtract", bson.A{
+			millis,
+			bson.D{{"$mod", bson.A{millis, bucket.Milliseconds()}}},
+		}}}}}},
+		{"count", bson.D{{"$sum", 1}}},
+	}
+
+	// Fields are named by position, since paths can't be used as names. Values that aren't numbers are ignored
+	for i, field := range req.Fields {
+		isNumber := bson.D{{"$isNumber", "$" + field}}
+		number := bson.D{{"$cond", bson.A{isNumber, "$" + field, nil}}}
+
+		group = append(group,
+			bson.E{fmt.Sprintf("f%d_count", i), bson.D{{"$sum", bson.D{{"$cond", bson.A{isNumber, 1, 0}}}}}},
+			bson.E{fmt.Sprintf("f%d_min", i), bson.D{{"$min", number}}},
+			bson.E{fmt.Sprintf("f%d_max", i), bson.D{{"$max", number}}},
+			bson.E{fmt.Sprintf("f%d_avg", i), bson.D{{"$avg", number}}},
+			bson.E{fmt.Sprintf("f%d_sum", i), bson.D{{"$sum", number}}},
+		)
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{"$match", filter}},
+		bson.D{{"$group", group}},
+		bson.D{{"$sort", bson.D{{"_id", 1}}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to aggregate store %s", req.Store),
+			err,
+		)
+	}
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to aggregate store %s", req.Store),
+			err,
+		)
+	}
+
+	res := &timeseriespb.TimeSeriesAggregateResponse{
+		Buckets: []*timeseriespb.Bucket{},
+	}
+
+	for _, result := range results {
+		start, _ := result["_id"].(primitive.DateTime)
+
+		b := &timeseriespb.Bucket{
+			Start:  timestamppb.New(start.Time()),
+			Count:  int64(statValue(result["count"])),
+			Fields: map[string]*timeseriespb.FieldStats{},
+		}
+
+		for i, field := range req.Fields {
+			b.Fields[field] = &timeseriespb.FieldStats{
+				Count: int64(statValue(result[fmt.Sprintf("f%d_count", i)])),
+				Min:   statValue(result[fmt.Sprintf("f%d_min", i)]),
+				Max:   statValue(result[fmt.Sprintf("f%d_max", i)]),
+				Avg:   statValue(result[fmt.Sprintf("f%d_avg", i)]),
+				Sum:   statValue(result[fmt.Sprintf("f%d_sum", i)]),
+			}
+		}
+
+		res.Buckets = append(res.Buckets, b)
+	}
+
+	return res, nil
+}
+
+func NewTimeSeries(kv *MongoDBServer) *MongoTimeSeriesServer {
+	return &MongoTimeSeriesServer{
+		kv: kv,
+	}
+}
@@ -0,0 +1,232 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	timeseriespb "github.com/nitrictech/mongodb-provider/common/proto/timeseries/v1"
+	kvstorepb "github.com/nitrictech/nitric/core/pkg/proto/kvstore/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTimeSeriesSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings TimeSeriesSettings
+		err      bool
+	}{
+		{name: "defaults"},
+		{name: "custom fields", settings: TimeSeriesSettings{TimeField: "at", MetaField: "sensor", Granularity: TimeSeriesGranularityHours}},
+		{name: "same time and meta fields", settings: TimeSeriesSettings{TimeField: "at", MetaField: "at"}, err: true},
+		{name: "reserved field", settings: TimeSeriesSettings{TimeField: "_id"}, err: true},
+		{name: "nested field", settings: TimeSeriesSettings{MetaField: "a.b"}, err: true},
+		{name: "unknown granularity", settings: TimeSeriesSettings{Granularity: "days"}, err: true},
+		{name: "negative expiry", settings: TimeSeriesSettings{ExpireAfterSeconds: -1}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.settings.validate(); test.err != (err != nil) {
+				t.Fatalf("expected error to be %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+// Time-series stores hold measurements, so the key value store and the time-series service don't mix
+func TestTimeSeriesRejected(t *testing.T) {
+	ctx := context.Background()
+	server := &MongoDBServer{collections: &storeCollections{timeSeries: map[string]TimeSeriesSettings{"metrics": {}}}}
+	timeSeries := NewTimeSeries(server)
+	now := timestamppb.Now()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "get value",
+			call: func() error {
+				_, err := server.GetValue(ctx, &kvstorepb.KvStoreGetValueRequest{Ref: &kvstorepb.ValueRef{Store: "metrics", Key: "a"}})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "set value",
+			call: func() error {
+				_, err := server.SetValue(ctx, &kvstorepb.KvStoreSetValueRequest{Ref: &kvstorepb.ValueRef{Store: "metrics", Key: "a"}})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "delete key",
+			call: func() error {
+				_, err := server.DeleteKey(ctx, &kvstorepb.KvStoreDeleteKeyRequest{Ref: &kvstorepb.ValueRef{Store: "metrics", Key: "a"}})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "append to a store that isn't time-series",
+			call: func() error {
+				_, err := timeSeries.Append(ctx, &timeseriespb.TimeSeriesAppendRequest{Store: "orders", Measurements: []*timeseriespb.Measurement{{Time: now}}})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "append nothing",
+			call: func() error {
+				_, err := timeSeries.Append(ctx, &timeseriespb.TimeSeriesAppendRequest{Store: "metrics"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "range limit above maximum",
+			call: func() error {
+				_, err := timeSeries.Range(ctx, &timeseriespb.TimeSeriesRangeRequest{Store: "metrics", Limit: maxTimeSeriesLimit + 1})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "aggregate too many buckets",
+			call: func() error {
+				_, err := timeSeries.Aggregate(ctx, &timeseriespb.TimeSeriesAggregateRequest{
+					Store:  "metrics",
+					Start:  timestamppb.New(now.AsTime().Add(-time.Hour)),
+					End:    now,
+					Bucket: durationpb.New(time.Millisecond),
+				})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.call(); status.Code(err) != test.code {
+				t.Fatalf("expected %s, got %v", test.code, err)
+			}
+		})
+	}
+}
+
+func TestTimeSeries(t *testing.T) {
+	ctx := context.Background()
+	server := testServer(t, WithStore("metrics", StoreSettings{TimeSeries: &TimeSeriesSettings{TimeField: "at", MetaField: "sensor"}}))
+	timeSeries := NewTimeSeries(server)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	measurements := []*timeseriespb.Measurement{}
+	for i := 0; i < 4; i++ {
+		sensor := "a"
+		if i == 3 {
+			sensor = "b"
+		}
+
+		measurements = append(measurements, &timeseriespb.Measurement{
+			Time:   timestamppb.New(start.Add(time.Duration(i) * 30 * time.Second)),
+			Meta:   mustStruct(t, map[string]interface{}{"id": sensor}),
+			Values: mustStruct(t, map[string]interface{}{"celsius": float64(20 + i)}),
+		})
+	}
+
+	if _, err := timeSeries.Append(ctx, &timeseriespb.TimeSeriesAppendRequest{Store: "metrics", Measurements: measurements}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The time and meta fields can't also be values
+	_, err := timeSeries.Append(ctx, &timeseriespb.TimeSeriesAppendRequest{Store: "metrics", Measurements: []*timeseriespb.Measurement{{
+		Time:   timestamppb.New(start),
+		Values: mustStruct(t, map[string]interface{}{"at": 1}),
+	}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected a value named after the time field to be InvalidArgument, got %v", err)
+	}
+
+	res, err := timeSeries.Range(ctx, &timeseriespb.TimeSeriesRangeRequest{
+		Store:      "metrics",
+		Meta:       mustStruct(t, map[string]interface{}{"id": "a"}),
+		Descending: true,
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	celsius := []float64{}
+	for _, measurement := range res.Measurements {
+		if measurement.Meta.Fields["id"].GetStringValue() != "a" {
+			t.Fatalf("expected only measurements of sensor a, got %v", measurement.Meta)
+		}
+
+		celsius = append(celsius, measurement.Values.Fields["celsius"].GetNumberValue())
+	}
+
+	if fmt.Sprint(celsius) != "[22 21]" {
+		t.Fatalf("expected the latest 2 measurements of sensor a, got %v", celsius)
+	}
+
+	agg, err := timeSeries.Aggregate(ctx, &timeseriespb.TimeSeriesAggregateRequest{
+		Store:  "metrics",
+		Start:  timestamppb.New(start),
+		End:    timestamppb.New(start.Add(2 * time.Minute)),
+		Bucket: durationpb.New(time.Minute),
+		Fields: []string{"celsius"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(agg.Buckets) != 2 {
+		t.Fatalf("expected 2 one minute buckets, got %v", agg.Buckets)
+	}
+
+	first, second := agg.Buckets[0], agg.Buckets[1]
+	if !first.Start.AsTime().Equal(start) || first.Count != 2 || first.Fields["celsius"].Avg != 20.5 {
+		t.Fatalf("expected the first minute to hold 20 and 21, got %v", first)
+	}
+
+	if second.Count != 2 || second.Fields["celsius"].Min != 22 || second.Fields["celsius"].Max != 23 || second.Fields["celsius"].Sum != 45 {
+		t.Fatalf("expected the second minute to hold 22 and 23, got %v", second)
+	}
+}
+
+func TestEnsureTimeSeriesExisting(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+
+	if err := db.CreateCollection(ctx, "orders"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ensureTimeSeries(ctx, db.Collection("orders"), TimeSeriesSettings{}); err == nil {
+		t.Fatal("expected a regular collection not to be used as a time-series store")
+	}
+
+	settings := TimeSeriesSettings{ExpireAfterSeconds: 3600}
+	if err := ensureTimeSeries(ctx, db.Collection("metrics"), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// The expiry of an existing time-series collection is updated, its fields must match
+	settings.ExpireAfterSeconds = 60
+	if err := ensureTimeSeries(ctx, db.Collection("metrics"), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ensureTimeSeries(ctx, db.Collection("metrics"), TimeSeriesSettings{TimeField: "at"}); err == nil {
+		t.Fatal("expected a time-series collection with a different time field to be rejected")
+	}
+}
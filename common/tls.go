@@ -0,0 +1,261 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nitrictech/mongodb-provider/common/env"
+	"github.com/nitrictech/nitric/core/pkg/logger"
+	secretspb "github.com/nitrictech/nitric/core/pkg/proto/secrets/v1"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Prefix of PEM given inline
+	pemSourcePrefix = "-----BEGIN"
+	// Prefix of PEM read from a file, followed by its path
+	fileSourcePrefix = "file:"
+	// Prefix of PEM read from the latest version of a secret in the cloud's secret manager, followed by the secret's name
+	secretSourcePrefix = "secret:"
+)
+
+// TLSSettings configure the TLS connections to a cluster with a private CA or client certificates.
+//
+// Certificates, keys and CRLs are PEM given inline, as file:<path> or as secret:<name> to read them from the cloud's secret manager.
+type TLSSettings struct {
+	// CA certificates trusted as well as the system's
+	CA string `json:"ca,omitempty"`
+	// Client certificate presented to the cluster, it may include the key
+	Certificate string `json:"certificate,omitempty"`
+	// Key of the client certificate, when it isn't included with the certificate
+	Key string `json:"key,omitempty"`
+	// Revocation lists, certificates of the cluster they revoke are rejected
+	CRL string `json:"crl,omitempty"`
+	// Don't ask OCSP responders whether the cluster's certificates are revoked, stapled responses are still checked
+	DisableOCSPEndpointCheck bool `json:"disable-ocsp-endpoint-check,omitempty"`
+	// Skip verifying the cluster's certificates and host names, for local development only
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// The sources of the settings, by what they are used as
+func (t TLSSettings) sources() map[string]string {
+	return map[string]string{
+		"ca":          t.CA,
+		"certificate": t.Certificate,
+		"key":         t.Key,
+		"crl":         t.CRL,
+	}
+}
+
+func (t TLSSettings) usesSecrets() bool {
+	for _, source := range t.sources() {
+		if strings.HasPrefix(source, secretSourcePrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks each source is PEM, a file or a secret and the settings can be combined.
+// Deployments validate their tls settings with it too, so the runtime and the stack configuration accept the same settings.
+func (t TLSSettings) Validate() error {
+	for name, source := range t.sources() {
+		if source == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(source, pemSourcePrefix) && !strings.HasPrefix(source, fileSourcePrefix) && !strings.HasPrefix(source, secretSourcePrefix) {
+			return fmt.Errorf("%s must be PEM, %s<path> or %s<name>", name, fileSourcePrefix, secretSourcePrefix)
+		}
+	}
+
+	if t.Key != "" && t.Certificate == "" {
+		return fmt.Errorf("a key requires a certificate")
+	}
+
+	if t.Insecure && (t.CA != "" || t.CRL != "") {
+		return fmt.Errorf("insecure connections don't verify certificates, so they can't have a ca or crl")
+	}
+
+	return nil
+}
+
+// Read the PEM of a source
+func loadPem(ctx context.Context, source string, secrets secretspb.SecretManagerServer) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, fileSourcePrefix):
+		return os.ReadFile(strings.TrimPrefix(source, fileSourcePrefix))
+	case strings.HasPrefix(source, secretSourcePrefix):
+		name := strings.TrimPrefix(source, secretSourcePrefix)
+		if secrets == nil {
+			return nil, fmt.Errorf("secret %s can't be read without a secret manager", name)
+		}
+
+		res, err := secrets.Access(ctx, &secretspb.SecretAccessRequest{
+			SecretVersion: &secretspb.SecretVersion{
+				Secret:  &secretspb.Secret{Name: name},
+				Version: "latest",
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret %s: %w", name, err)
+		}
+
+		return res.Value, nil
+	default:
+		return []byte(source), nil
+	}
+}
+
+// Parse the PEM revocation lists of a source
+func parseCrls(data []byte) ([]*x509.RevocationList, error) {
+	crls := []*x509.RevocationList{}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "X509 CRL" {
+			continue
+		}
+
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		crls = append(crls, crl)
+	}
+
+	if len(crls) == 0 {
+		return nil, fmt.Errorf("no X509 CRL blocks found")
+	}
+
+	return crls, nil
+}
+
+// Reject the verified chains of a cluster that include a certificate revoked by a list its issuer signed
+func verifyNotRevoked(crls []*x509.RevocationList) func([][]byte, [][]*x509.Certificate) error {
+	return func(_ [][]byte, chains [][]*x509.Certificate) error {
+		for _, chain := range chains {
+			for i := 0; i+1 < len(chain); i++ {
+				cert, issuer := chain[i], chain[i+1]
+
+				for _, crl := range crls {
+					if !bytes.Equal(crl.RawIssuer, cert.RawIssuer) || crl.CheckSignatureFrom(issuer) != nil {
+						continue
+					}
+
+					for _, revoked := range crl.RevokedCertificateEntries {
+						if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+							return fmt.Errorf("certificate %s is revoked", cert.Subject)
+						}
+					}
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// Apply the settings to the options of a client, reading their certificates
+func (t TLSSettings) apply(ctx context.Context, opts *options.ClientOptions, secrets secretspb.SecretManagerServer) error {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if t.CA != "" {
+		ca, err := loadPem(ctx, t.CA, secrets)
+		if err != nil {
+			return fmt.Errorf("unable to read the ca: %w", err)
+		}
+
+		// The private CA is trusted along with the public ones, so clusters with either can be reached
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(ca) {
+			return fmt.Errorf("the ca has no PEM certificates")
+		}
+
+		config.RootCAs = pool
+	}
+
+	if t.Certificate != "" {
+		certificate, err := loadPem(ctx, t.Certificate, secrets)
+		if err != nil {
+			return fmt.Errorf("unable to read the certificate: %w", err)
+		}
+
+		key := certificate
+		if t.Key != "" {
+			key, err = loadPem(ctx, t.Key, secrets)
+			if err != nil {
+				return fmt.Errorf("unable to read the key: %w", err)
+			}
+		}
+
+		pair, err := tls.X509KeyPair(certificate, key)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	if t.CRL != "" {
+		data, err := loadPem(ctx, t.CRL, secrets)
+		if err != nil {
+			return fmt.Errorf("unable to read the crl: %w", err)
+		}
+
+		crls, err := parseCrls(data)
+		if err != nil {
+			return fmt.Errorf("invalid crl: %w", err)
+		}
+
+		config.VerifyPeerCertificate = verifyNotRevoked(crls)
+	}
+
+	if t.Insecure {
+		logger.Warnf("TLS certificates and host names of the cluster aren't verified, this is only safe for local development")
+		config.InsecureSkipVerify = true
+	}
+
+	opts.SetTLSConfig(config)
+
+	if t.DisableOCSPEndpointCheck {
+		opts.SetDisableOCSPEndpointCheck(true)
+	}
+
+	return nil
+}
+
+// TLS settings of the stack's cluster, nil when MONGO_TLS_CONFIG is unset
+func tlsSettingsFromEnv() (*TLSSettings, error) {
+	value := env.MONGO_TLS_CONFIG.String()
+	if value == "" {
+		return nil, nil
+	}
+
+	settings := &TLSSettings{}
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return nil, fmt.Errorf("unable to parse MONGO_TLS_CONFIG: %w", err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,187 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings TLSSettings
+		err      string
+	}{
+		{name: "empty"},
+		{name: "inline ca", settings: TLSSettings{CA: "-----BEGIN CERTIFICATE-----\n"}},
+		{name: "file and secret", settings: TLSSettings{Certificate: "file:/etc/client.pem", Key: "secret:client-key"}},
+		{name: "unknown source", settings: TLSSettings{CA: "https://example.com/ca.pem"}, err: "ca must be PEM"},
+		{name: "key without certificate", settings: TLSSettings{Key: "secret:client-key"}, err: "a key requires a certificate"},
+		{name: "insecure with ca", settings: TLSSettings{Insecure: true, CA: "file:/etc/ca.pem"}, err: "can't have a ca or crl"},
+		{name: "insecure with crl", settings: TLSSettings{Insecure: true, CRL: "file:/etc/crl.pem"}, err: "can't have a ca or crl"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.settings.Validate()
+
+			if test.err == "" {
+				if err != nil {
+					t.Fatalf("expected valid settings, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+type testAuthority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestAuthority(t *testing.T, name string) *testAuthority {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testAuthority{cert: cert, key: key}
+}
+
+func (a *testAuthority) issue(t *testing.T, serial int64) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "cluster"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+// A PEM revocation list of the authority revoking the serial numbers
+func (a *testAuthority) crl(t *testing.T, serials ...int64) []byte {
+	t.Helper()
+
+	entries := []x509.RevocationListEntry{}
+	for _, serial := range serials {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                time.Now().Add(-time.Hour),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: entries,
+	}, a.cert, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func TestParseCrls(t *testing.T) {
+	first := newTestAuthority(t, "first")
+	second := newTestAuthority(t, "second")
+
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: first.cert.Raw})
+
+	// Blocks other than revocation lists are skipped
+	crls, err := parseCrls(append(append(first.crl(t, 2), certificate...), second.crl(t)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(crls) != 2 {
+		t.Fatalf("expected 2 revocation lists, got %d", len(crls))
+	}
+
+	if _, err := parseCrls(certificate); err == nil {
+		t.Fatal("expected an error without revocation lists")
+	}
+
+	if _, err := parseCrls([]byte("-----BEGIN X509 CRL-----\nAAAA\n-----END X509 CRL-----\n")); err == nil {
+		t.Fatal("expected an error for an invalid revocation list")
+	}
+}
+
+func TestVerifyNotRevoked(t *testing.T) {
+	authority := newTestAuthority(t, "authority")
+	other := newTestAuthority(t, "other")
+
+	revoked := authority.issue(t, 2)
+	valid := authority.issue(t, 3)
+
+	crls, err := parseCrls(append(authority.crl(t, 2), other.crl(t, 3)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := verifyNotRevoked(crls)
+
+	if err := verify(nil, [][]*x509.Certificate{{revoked, authority.cert}}); err == nil {
+		t.Fatal("expected the revoked certificate to be rejected")
+	}
+
+	// The other authority's list revokes serial 3, but it didn't issue the certificate
+	if err := verify(nil, [][]*x509.Certificate{{valid, authority.cert}}); err != nil {
+		t.Fatalf("expected the certificate to be accepted, got %v", err)
+	}
+
+	// Any verified chain with a revoked certificate is rejected
+	if err := verify(nil, [][]*x509.Certificate{{valid, authority.cert}, {revoked, authority.cert}}); err == nil {
+		t.Fatal("expected the chain with the revoked certificate to be rejected")
+	}
+}
@@ -0,0 +1,402 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	documentspb "github.com/nitrictech/mongodb-provider/common/proto/documents/v1"
+	vectorspb "github.com/nitrictech/mongodb-provider/common/proto/vectors/v1"
+	grpc_errors "github.com/nitrictech/nitric/core/pkg/grpc/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	VectorSimilarityCosine     = "cosine"
+	VectorSimilarityEuclidean  = "euclidean"
+	VectorSimilarityDotProduct = "dotProduct"
+
+	// Field holding the vectors of a value by name, it isn't part of the value's content
+	vectorsField = "_vectors"
+	// The most dimensions Atlas Vector Search indexes
+	maxVectorDimensions = 8192
+
+	defaultVectorTopK       = 10
+	maxVectorTopK           = 1000
+	maxVectorNumCandidates  = 10000
+	vectorCandidatesPerTopK = 10
+)
+
+// VectorSettings declare a vector of the values of a key value store, each is indexed by Atlas Vector Search
+type VectorSettings struct {
+	Dimensions int `json:"dimensions"`
+	// cosine, euclidean or dotProduct, defaults to cosine
+	Similarity string `json:"similarity,omitempty"`
+	// Dot separated paths of the content fields queries can filter on
+	Filters []string `json:"filters,omitempty"`
+}
+
+func (v VectorSettings) similarity() string {
+	if v.Similarity == "" {
+		return VectorSimilarityCosine
+	}
+
+	return v.Similarity
+}
+
+func (v VectorSettings) validate() error {
+	if v.Dimensions < 1 || v.Dimensions > maxVectorDimensions {
+		return fmt.Errorf("dimensions must be between 1 and %d", maxVectorDimensions)
+	}
+
+	switch v.similarity() {
+	case VectorSimilarityCosine, VectorSimilarityEuclidean, VectorSimilarityDotProduct:
+	default:
+		return fmt.Errorf("unknown similarity %s", v.Similarity)
+	}
+
+	for _, filter := range v.Filters {
+		if err := validateFieldPath(filter); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// The name of the search index of a vector
+func vectorIndexName(vector string) string {
+	return "vector-" + vector
+}
+
+// The search index of a vector
+func (v VectorSettings) searchIndex(vector string) searchIndex {
+	fields := bson.A{bson.D{
+		{"type", "vector"},
+		{"path", vectorsField + "." + vector},
+		{"numDimensions", v.Dimensions},
+		{"similarity", v.similarity()},
+	}}
+
+	for _, filter := range v.Filters {
+		fields = append(fields, bson.D{{"type", "filter"}, {"path", filter}})
+	}
+
+	return searchIndex{
+		Name:       vectorIndexName(vector),
+		Type:       "vectorSearch",
+		Definition: bson.D{{"fields", fields}},
+	}
+}
+
+// Check a vector can be indexed with the similarity, Atlas skips vectors it can't compare
+func (v VectorSettings) validateValues(values []float64) error {
+	if len(values) != v.Dimensions {
+		return fmt.Errorf("the vector has %d dimensions, not %d", len(values), v.Dimensions)
+	}
+
+	magnitude := 0.0
+	for _, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("vector values must be finite")
+		}
+
+		magnitude += value * value
+	}
+	magnitude = math.Sqrt(magnitude)
+
+	switch v.similarity() {
+	case VectorSimilarityCosine:
+		if magnitude == 0 {
+			return fmt.Errorf("cosine similarity requires a vector with a magnitude")
+		}
+	case VectorSimilarityDotProduct:
+		if math.Abs(magnitude-1) > 1e-3 {
+			return fmt.Errorf("dotProduct similarity requires a unit length vector, its length is %f", magnitude)
+		}
+	}
+
+	return nil
+}
+
+// MongoVectorsServer sets the vectors of the values stored by MongoDBServer and finds the values nearest to a vector.
+//
+// Vectors are kept in the values' documents apart from their content, so they are removed with the value and kept when it is set.
+type MongoVectorsServer struct {
+	kv *MongoDBServer
+}
+
+var _ vectorspb.VectorsServer = &MongoVectorsServer{}
+
+// The declared settings of a store's vector
+func (v *MongoVectorsServer) vectorSettings(store string, vector string) (VectorSettings, error) {
+	settings, ok := v.kv.vectors[store][vector]
+	if !ok {
+		return settings, fmt.Errorf("store %s has no vector %s", store, vector)
+	}
+
+	return settings, nil
+}
+
+// Set a vector of a value, the value must exist
+func (v *MongoVectorsServer) Upsert(ctx context.Context, req *vectorspb.VectorsUpsertRequest) (*vectorspb.VectorsUpsertResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoVectorsServer.Upsert")
+
+	settings, err := v.vectorSettings(req.Store, req.Vector)
+	if err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			"vector not declared",
+			err,
+		)
+	}
+
+	if err := settings.validateValues(req.Values); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid vector",
+			err,
+		)
+	}
+
+	coll, key, err := v.kv.scopedCollection(ctx, req.Store, req.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := v.kv.quotas.allow(ctx, req.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	// The revision and hash describe the content, so they don't change
+	res, err := coll.UpdateOne(ctx, bson.D{{"_id", key}}, bson.D{{"$set", bson.D{{vectorsField + "." + req.Vector, req.Values}}}})
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to set vector %s of %s in %s store", req.Vector, req.Key, req.Store),
+			err,
+		)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("%s not found in %s store", req.Key, req.Store),
+			fmt.Errorf("value not found"),
+		)
+	}
+
+	return &vectorspb.VectorsUpsertResponse{}, nil
+}
+
+// Remove a vector from a value
+func (v *MongoVectorsServer) Delete(ctx context.Context, req *vectorspb.VectorsDeleteRequest) (*vectorspb.VectorsDeleteResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoVectorsServer.Delete")
+
+	if _, err := v.vectorSettings(req.Store, req.Vector); err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			"vector not declared",
+			err,
+		)
+	}
+
+	coll, key, err := v.kv.scopedCollection(ctx, req.Store, req.Key)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := v.kv.quotas.allow(ctx, req.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	res, err := coll.UpdateOne(ctx, bson.D{{"_id", key}}, bson.D{{"$unset", bson.D{{vectorsField + "." + req.Vector, ""}}}})
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to delete vector %s of %s in %s store", req.Vector, req.Key, req.Store),
+			err,
+		)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, newErr(
+			codes.NotFound,
+			fmt.Sprintf("%s not found in %s store", req.Key, req.Store),
+			fmt.Errorf("value not found"),
+		)
+	}
+
+	return &vectorspb.VectorsDeleteResponse{}, nil
+}
+
+// vectorResult is the shape matches are read in, so the score is kept apart from the content
+type vectorResult struct {
+	Id      bson.RawValue `bson:"_id"`
+	Score   float64       `bson:"score"`
+	Content bson.Raw      `bson:"content"`
+}
+
+// Find the values whose vectors are most similar to a query vector
+func (v *MongoVectorsServer) Query(ctx context.Context, req *vectorspb.VectorsQueryRequest) (*vectorspb.VectorsQueryResponse, error) {
+	newErr := grpc_errors.ErrorsWithScope("MongoVectorsServer.Query")
+
+	settings, err := v.vectorSettings(req.Store, req.Vector)
+	if err != nil {
+		return nil, newErr(
+			codes.FailedPrecondition,
+			"vector not declared",
+			err,
+		)
+	}
+
+	if err := settings.validateValues(req.Values); err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"invalid query vector",
+			err,
+		)
+	}
+
+	topK := int(req.TopK)
+	if topK == 0 {
+		topK = defaultVectorTopK
+	}
+
+	numCandidates := int(req.NumCandidates)
+	if numCandidates == 0 {
+		numCandidates = min(topK*vectorCandidatesPerTopK, maxVectorNumCandidates)
+	}
+
+	if topK < 0 || topK > maxVectorTopK || numCandidates < topK || numCandidates > maxVectorNumCandidates {
+		return nil, newErr(
+			codes.InvalidArgument,
+			fmt.Sprintf("top_k must be between 1 and %d and num_candidates between top_k and %d", maxVectorTopK, maxVectorNumCandidates),
+			fmt.Errorf("top_k %d, num_candidates %d", req.TopK, req.NumCandidates),
+		)
+	}
+
+	filterable := map[string]bool{}
+	for _, path := range settings.Filters {
+		filterable[path] = true
+	}
+
+	conditions := bson.A{}
+	for _, filter := range req.Filters {
+		// Vector search can only filter on the fields indexed for it, and not on whether they exist
+		if _, ok := filter.Condition.(*documentspb.Filter_Exists); ok || !filterable[filter.Path] {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid filter",
+				fmt.Errorf("filter on %s must be an equals, range or in condition on a declared filter field", filter.Path),
+			)
+		}
+
+		condition, err := filterCondition(filter)
+		if err != nil {
+			return nil, newErr(
+				codes.InvalidArgument,
+				"invalid filter",
+				err,
+			)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	// Queries aren't made with a key, so in key tenancy mode the tenant is given as a key prefix would be
+	tenantPrefix := ""
+	if v.kv.tenancy == TenancyModeKey {
+		tenantPrefix = req.Tenant + tenantKeySeparator
+	}
+
+	coll, _, err := v.kv.scopedCollection(ctx, req.Store, tenantPrefix)
+	if err != nil {
+		return nil, newErr(
+			codes.InvalidArgument,
+			"unable to determine the tenant",
+			err,
+		)
+	}
+
+	if err := v.kv.quotas.allow(ctx, req.Store); err != nil {
+		return nil, quotaErr(newErr, err)
+	}
+
+	search := bson.D{
+		{"index", vectorIndexName(req.Vector)},
+		{"path", vectorsField + "." + req.Vector},
+		{"queryVector", req.Values},
+		{"numCandidates", numCandidates},
+		{"limit", topK},
+	}
+	if len(conditions) > 0 {
+		search = append(search, bson.E{"filter", bson.D{{"$and", conditions}}})
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{"$vectorSearch", search}},
+		bson.D{{"$replaceWith", bson.D{
+			{"_id", "$_id"},
+			{"score", bson.D{{"$meta", "vectorSearchScore"}}},
+			{"content", "$$ROOT"},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to query store %s", req.Store),
+			err,
+		)
+	}
+
+	var results []vectorResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, newErr(
+			codes.Internal,
+			fmt.Sprintf("unable to query store %s", req.Store),
+			err,
+		)
+	}
+
+	res := &vectorspb.VectorsQueryResponse{
+		Matches: []*vectorspb.VectorMatch{},
+	}
+
+	for _, result := range results {
+		content, err := contentFromDocument(result.Content)
+		if err != nil {
+			return nil, newErr(
+				codes.Internal,
+				"unable to convert value to pb struct",
+				err,
+			)
+		}
+
+		res.Matches = append(res.Matches, &vectorspb.VectorMatch{
+			Key:     tenantPrefix + keyString(result.Id),
+			Score:   result.Score,
+			Content: content,
+		})
+	}
+
+	return res, nil
+}
+
+func NewVectors(kv *MongoDBServer) *MongoVectorsServer {
+	return &MongoVectorsServer{
+		kv: kv,
+	}
+}
@@ -15,12 +15,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/charmbracelet/log"
+	mongo_service "github.com/nitrictech/mongodb-provider/common"
+	mongo_env "github.com/nitrictech/mongodb-provider/common/env"
 	"github.com/nitrictech/nitric/cloud/gcp/runtime/api"
 	cloudrun_plugin "github.com/nitrictech/nitric/cloud/gcp/runtime/gateway"
 	firestore_service "github.com/nitrictech/nitric/cloud/gcp/runtime/keyvalue"
@@ -31,6 +34,7 @@ import (
 	pubsub_service "github.com/nitrictech/nitric/cloud/gcp/runtime/topic"
 	"github.com/nitrictech/nitric/core/pkg/logger"
 	"github.com/nitrictech/nitric/core/pkg/membrane"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func main() {
@@ -40,18 +44,45 @@ func main() {
 	signal.Notify(term, syscall.SIGTERM, syscall.SIGINT)
 
 	membraneOpts := membrane.DefaultMembraneOptions()
-	provider, err := resource.New()
-	if err != nil {
-		logger.Fatalf("Failed create core provider: %s", err.Error())
-	}
 
-	membraneOpts.ApiPlugin = api.NewGcpApiGatewayProvider(provider)
+	mongoQueues, _ := mongo_env.MONGO_QUEUES_ENABLED.Bool()
+	mongoStorage, _ := mongo_env.MONGO_STORAGE_ENABLED.Bool()
+	mongoSnapshots, _ := mongo_env.MONGO_SNAPSHOTS_ENABLED.Bool()
+
+	// Key value stores are kept in firestore, which the outbox's transaction can't include
+	if mongoOutbox, _ := mongo_env.MONGO_OUTBOX_ENABLED.Bool(); mongoOutbox {
+		logger.Fatalf("MONGO_OUTBOX_ENABLED is only supported on AWS, where key value stores are kept in MongoDB")
+	}
 
+	// Loaded first, the cluster's certificates may be secrets
 	membraneOpts.SecretManagerPlugin, err = secret_manager_secret_service.New()
 	if err != nil {
 		logger.Errorf("Failed to load secret plugin: %s", err.Error())
 	}
 
+	// The cluster connection is only injected when the stack uses it
+	var mongoDatabase *mongo.Database
+	if mongo_env.MONGO_CLUSTER_CONNECTION_STRING.String() != "" {
+		mongoDatabase, err = mongo_service.Connect(context.TODO(), mongo_service.WithSecrets(membraneOpts.SecretManagerPlugin))
+		if err != nil {
+			logger.Fatalf("There was an error connecting to the mongo cluster: %v", err)
+		}
+	} else if mongoQueues {
+		// Plugins served from the cluster can't be created without it
+		logger.Fatalf("MONGO_QUEUES_ENABLED requires MONGO_CLUSTER_CONNECTION_STRING")
+	} else if mongoStorage {
+		logger.Fatalf("MONGO_STORAGE_ENABLED requires MONGO_CLUSTER_CONNECTION_STRING")
+	} else if mongoSnapshots {
+		logger.Fatalf("MONGO_SNAPSHOTS_ENABLED requires MONGO_CLUSTER_CONNECTION_STRING")
+	}
+
+	provider, err := resource.New()
+	if err != nil {
+		logger.Fatalf("Failed create core provider: %s", err.Error())
+	}
+
+	membraneOpts.ApiPlugin = api.NewGcpApiGatewayProvider(provider)
+
 	membraneOpts.KeyValuePlugin, err = firestore_service.New()
 	if err != nil {
 		logger.Errorf("Failed to load document plugin: %s", err.Error())
@@ -62,14 +93,29 @@ func main() {
 		logger.Errorf("Failed to load events plugin: %s", err.Error())
 	}
 
-	membraneOpts.QueuesPlugin, err = pubsub_queue_service.New()
-	if err != nil {
-		logger.Errorf("Failed to load queues plugin: %s", err.Error())
+	if mongoQueues {
+		membraneOpts.QueuesPlugin, err = mongo_service.NewQueues(mongoDatabase)
+		if err != nil {
+			logger.Errorf("Failed to load queues plugin: %s", err.Error())
+		}
+	} else {
+		membraneOpts.QueuesPlugin, err = pubsub_queue_service.New()
+		if err != nil {
+			logger.Errorf("Failed to load queues plugin: %s", err.Error())
+		}
 	}
 
-	membraneOpts.StoragePlugin, err = storage_service.New()
-	if err != nil {
-		logger.Errorf("Failed to load storage plugin: %s", err.Error())
+	if mongoStorage {
+		// Presigned urls are unavailable as this gateway doesn't serve the presign route
+		membraneOpts.StoragePlugin, err = mongo_service.NewStorage(mongoDatabase)
+		if err != nil {
+			logger.Errorf("Failed to load storage plugin: %s", err.Error())
+		}
+	} else {
+		membraneOpts.StoragePlugin, err = storage_service.New()
+		if err != nil {
+			logger.Errorf("Failed to load storage plugin: %s", err.Error())
+		}
 	}
 
 	membraneOpts.GatewayPlugin, err = cloudrun_plugin.New(provider)
@@ -84,10 +130,32 @@ func main() {
 		log.Fatalf("There was an error initialising the membrane server: %v", err)
 	}
 
+	startOpts := []membrane.MembraneStartOptions{}
+	if mongoDatabase != nil {
+		// Serve the extension services alongside the membrane
+		extensionServer, err := mongo_service.NewExtensionServer(mongoDatabase, membraneOpts.StoragePlugin)
+		if err != nil {
+			log.Fatalf("There was an error initialising the mongo extension services: %v", err)
+		}
+
+		startOpts = append(startOpts, membrane.WithGrpcServer(extensionServer))
+	}
+
+	// Export key value stores to the snapshot bucket on schedule
+	var snapshotScheduler *mongo_service.SnapshotScheduler
+	if mongoSnapshots {
+		snapshotScheduler, err = mongo_service.NewSnapshotScheduler(mongoDatabase, membraneOpts.StoragePlugin)
+		if err != nil {
+			log.Fatalf("There was an error initialising the mongo snapshot scheduler: %v", err)
+		}
+
+		snapshotScheduler.Start()
+	}
+
 	errChan := make(chan error)
 	// Start the Membrane server
 	go func(chan error) {
-		errChan <- m.Start()
+		errChan <- m.Start(startOpts...)
 	}(errChan)
 
 	select {
@@ -98,4 +166,14 @@ func main() {
 	}
 
 	m.Stop()
+
+	if snapshotScheduler != nil {
+		snapshotScheduler.Stop()
+	}
+
+	if mongoDatabase != nil {
+		if err := mongoDatabase.Client().Disconnect(context.Background()); err != nil {
+			logger.Errorf("unable to disconnect from the mongo cluster: %v", err)
+		}
+	}
 }